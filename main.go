@@ -15,6 +15,20 @@ func main() {
 		packagePath = flag.String("package-path", "", "Base package path for the provider (required)")
 		version     = flag.String("version", "", "Version of the provider (required)")
 		outputDir   = flag.String("output", "./index", "Output directory for index files")
+		dryRun      = flag.Bool("dry-run", false, "Report the files that would be written without writing them")
+		compress    = flag.Bool("compress", false, "Write gzip-compressed .json.gz files")
+		compact     = flag.Bool("compact", false, "Write JSON without indentation")
+		services    = flag.Bool("service-files", false, "Also write one services/<service>.json file per service")
+		atomic      = flag.Bool("atomic", false, "Write to a temporary directory and replace the output directory only on success")
+		jsonLines   = flag.String("jsonl", "", "Also write every entry as JSON Lines to this file")
+		fileName    = flag.String("file-name-template", "", "Go template naming each resource, data source and ephemeral file")
+		resources   = flag.String("resources-dir", "", "Directory name for resource files (default \"resources\")")
+		dataSources = flag.String("datasources-dir", "", "Directory name for data source files (default \"datasources\")")
+		ephemeral   = flag.String("ephemeral-dir", "", "Directory name for ephemeral resource files (default \"ephemeral\")")
+		sdkPrefix   = flag.String("sdk-type-prefix", "", "Prefix prepended to the sdk_type of every entry file, e.g. \"my\" for \"myaws_sdk\"")
+		related     = flag.Bool("related-functions", false, "Add the helper functions referenced by each factory to resource and data source files")
+		attributes  = flag.Bool("attribute-schemas", false, "Also write one attributes/<terraform_type>.json schema file per resource")
+		mainIndex   = flag.String("main-index-file", "", "File name of the main index (default \"terraform-provider-aws-index.json\")")
 		help        = flag.Bool("help", false, "Show help message")
 	)
 
@@ -35,6 +49,37 @@ Required flags:
 Optional flags:
   -output string
         Output directory for index files (default "./index")
+  -dry-run
+        Report the files that would be written without writing them
+  -compress
+        Write gzip-compressed .json.gz files
+  -compact
+        Write JSON without indentation
+  -service-files
+        Also write one services/<service>.json file per service
+  -atomic
+        Write to a temporary directory and replace the output directory only on success
+  -jsonl string
+        Also write every entry as JSON Lines to this file
+  -file-name-template string
+        Go template naming each resource, data source and ephemeral file, with fields
+        .TerraformType, .Service and .Category (default "{{.TerraformType}}.json")
+  -resources-dir string
+        Directory name for resource files (default "resources")
+  -datasources-dir string
+        Directory name for data source files (default "datasources")
+  -ephemeral-dir string
+        Directory name for ephemeral resource files (default "ephemeral")
+  -sdk-type-prefix string
+        Prefix prepended to the sdk_type of every entry file, e.g. "my" for "myaws_sdk"
+  -related-functions
+        Add the helper functions referenced by each factory, e.g. expand and flatten helpers,
+        to resource and data source files
+  -attribute-schemas
+        Also write one attributes/<terraform_type>.json file per resource describing its schema attributes,
+        with their types, required, optional and computed flags and nesting
+  -main-index-file string
+        File name of the main index (default "terraform-provider-aws-index.json")
   -help
         Show this help message
 
@@ -101,17 +146,84 @@ Example:
 	fmt.Printf("  🔗 Legacy Resources: %d\n", index.Statistics.LegacyResources)
 	fmt.Printf("  ⚡ Modern Resources: %d\n", index.Statistics.ModernResources)
 	fmt.Printf("  🔄 Ephemeral Resources: %d\n", index.Statistics.EphemeralResources)
+	fmt.Printf("  ⏱️  Scan Duration: %dms\n", index.Statistics.ScanDurationMs)
 	fmt.Printf("\n")
 
+	outputOptions := pkg.OutputOptions{Compress: *compress, Compact: *compact, ServiceFiles: *services, Atomic: *atomic, FileNameTemplate: *fileName,
+		ResourcesDir: *resources, DataSourcesDir: *dataSources, EphemeralDir: *ephemeral, SDKTypePrefix: *sdkPrefix,
+		RelatedFunctions: *related, MainIndexFileName: *mainIndex,
+		AttributeSchemas: *attributes}
+
+	if *dryRun {
+		plan, err := index.PlanIndexFilesWithOptions(*outputDir, outputOptions)
+		if err != nil {
+			log.Fatalf("Error planning JSON output: %v", err)
+		}
+
+		fmt.Printf("📝 Dry run, no files written. Would write %d files to %s:\n", len(plan.Files), *outputDir)
+		dirs := []string{".", "resources", "datasources", "ephemeral"}
+		if *services {
+			dirs = append(dirs, "services")
+		}
+		if *attributes {
+			dirs = append(dirs, "attributes")
+		}
+		for _, dir := range dirs {
+			fmt.Printf("  📂 %s: %d\n", dir, plan.Totals[dir])
+		}
+		return
+	}
+
 	// Generate JSON output
-	err = index.WriteIndexFiles(*outputDir, progressCallback)
+	err = index.WriteIndexFilesWithOptions(*outputDir, progressCallback, outputOptions)
 	if err != nil {
 		log.Fatalf("Error generating JSON output: %v", err)
 	}
 
+	if *jsonLines != "" {
+		if err := writeJSONLinesFile(index, *jsonLines); err != nil {
+			log.Fatalf("Error writing JSON Lines output: %v", err)
+		}
+	}
+
+	ext := ".json"
+	if *compress {
+		ext = ".json.gz"
+	}
+
 	fmt.Printf("\n🎉 Index files generated successfully!\n")
-	fmt.Printf("  📋 Main index: %s/terraform-provider-aws-index.json\n", *outputDir)
+	mainIndexFile := "terraform-provider-aws-index.json"
+	if *mainIndex != "" {
+		mainIndexFile = *mainIndex
+	}
+	if *compress {
+		mainIndexFile += ".gz"
+	}
+	fmt.Printf("  📋 Main index: %s/%s\n", *outputDir, mainIndexFile)
+	fmt.Printf("  🔍 Factory index: %s/factory-index%s\n", *outputDir, ext)
+	fmt.Printf("  📈 Statistics: %s/statistics%s\n", *outputDir, ext)
+	fmt.Printf("  🧾 Manifest: %s/manifest%s\n", *outputDir, ext)
 	fmt.Printf("  🔧 Resources: %s/resources/\n", *outputDir)
 	fmt.Printf("  📊 Data Sources: %s/datasources/\n", *outputDir)
 	fmt.Printf("  ⚡ Ephemeral Resources: %s/ephemeral/\n", *outputDir)
+	if *attributes {
+		fmt.Printf("  🧬 Attribute Schemas: %s/attributes/\n", *outputDir)
+	}
+	if *jsonLines != "" {
+		fmt.Printf("  📜 JSON Lines: %s\n", *jsonLines)
+	}
+}
+
+// writeJSONLinesFile writes the index entries as JSON Lines to path
+func writeJSONLinesFile(index *pkg.TerraformProviderIndex, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := index.WriteJSONLines(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
 }
@@ -5,184 +5,1200 @@ import (
 	"go/ast"
 	"go/token"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	gophon "github.com/lonegunmanb/gophon/pkg"
 )
 
-// annotationRegex matches Terraform provider annotations
+// annotationRegex matches the opening of a Terraform provider annotation
+// The argument list is parsed separately by parseAnnotationArguments so that
+// the name may be positional or keyword and trailing attributes are tolerated
 // Examples:
 // @SDKResource("aws_lambda_function", name="Function")
 // @FrameworkDataSource("aws_bedrock_custom_model", name="Custom Model")
-var annotationRegex = regexp.MustCompile(`@(SDKResource|SDKDataSource|FrameworkResource|FrameworkDataSource|EphemeralResource)\("([^"]+)"(?:,\s*name="([^"]+)")?[^)]*\)`)
+// @FrameworkResource("aws_bedrock_guardrail", "Guardrail")
+var annotationRegex = regexp.MustCompile(`@(SDKResource|SDKDataSource|FrameworkResource|FrameworkDataSource|EphemeralResource)\(`)
+
+// identityAnnotationRegex matches resource identity annotations accompanying a registration annotation
+// The argument list is optional, e.g. @SingletonIdentity or @ArnIdentity
+// Examples:
+// @IdentityAttribute("bucket")
+// @IdentityAttribute("region", optional="true")
+// @ArnIdentity("arn")
+var identityAnnotationRegex = regexp.MustCompile(`@(IdentityAttribute|ArnIdentity|SingletonIdentity)\b`)
+
+// regionAnnotationRegex matches the region annotation accompanying a registration annotation
+// Examples:
+// @Region(global=true)
+// @Region(overrideEnabled=false)
+var regionAnnotationRegex = regexp.MustCompile(`@Region\b`)
+
+// testingAnnotationRegex matches the @Testing annotations carrying acceptance test generation hints, e.g.
+// @Testing(existsType="github.com/aws/aws-sdk-go-v2/service/s3;s3.GetBucketOutput", importIgnore="force_destroy")
+var testingAnnotationRegex = regexp.MustCompile(`@Testing\(`)
+
+// annotationArguments holds the parsed argument list of an annotation
+type annotationArguments struct {
+	Positional []string          // Positional arguments in order, unquoted
+	Keywords   map[string]string // Keyword arguments, unquoted
+}
+
+// parseAnnotationArguments parses the argument list of an annotation starting right after the opening parenthesis
+// It returns the parsed arguments and the length of the consumed text including the closing parenthesis
+// Commas and parentheses inside quoted strings are treated as literal text
+func parseAnnotationArguments(text string) (annotationArguments, int, bool) {
+	args := annotationArguments{
+		Keywords: make(map[string]string),
+	}
+
+	var current strings.Builder
+	inQuotes := false
+	addArgument := func() {
+		arg := strings.TrimSpace(current.String())
+		current.Reset()
+		if arg == "" {
+			return
+		}
+
+		key, value, isKeyword := splitKeywordArgument(arg)
+		if isKeyword {
+			args.Keywords[key] = unquoteAnnotationValue(value)
+			return
+		}
+		args.Positional = append(args.Positional, unquoteAnnotationValue(arg))
+	}
+
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case ch == '\\' && inQuotes && i+1 < len(text):
+			// Escaped character inside a quoted value, e.g. \", kept as written for unquoteAnnotationValue
+			current.WriteByte(ch)
+			current.WriteByte(text[i+1])
+			i++
+		case ch == '"':
+			inQuotes = !inQuotes
+			current.WriteByte(ch)
+		case inQuotes:
+			current.WriteByte(ch)
+		case ch == ',':
+			addArgument()
+		case ch == ')':
+			addArgument()
+			return args, i + 1, true
+		default:
+			current.WriteByte(ch)
+		}
+	}
+
+	// Unterminated argument list
+	return args, len(text), false
+}
+
+// splitKeywordArgument splits a `key=value` argument, ignoring '=' inside quoted values
+func splitKeywordArgument(arg string) (string, string, bool) {
+	if strings.HasPrefix(arg, "\"") {
+		return "", "", false
+	}
+
+	idx := strings.Index(arg, "=")
+	if idx <= 0 {
+		return "", "", false
+	}
+
+	return strings.TrimSpace(arg[:idx]), strings.TrimSpace(arg[idx+1:]), true
+}
+
+// unquoteAnnotationValue strips surrounding double quotes from an annotation value, resolving escapes
+// such as \" the way Go string literals do. A value with an invalid escape keeps its text as written
+func unquoteAnnotationValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// terraformTypeRegex matches a valid terraform type name, e.g. "aws_s3_bucket"
+var terraformTypeRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
 
 // ScanPackageForAnnotations scans all files in the package for annotations
 // and returns structured results mapping annotations to their context
 func ScanPackageForAnnotations(packageInfo *gophon.PackageInfo) (*AnnotationResults, error) {
+	return scanPackageForAnnotations(packageInfo, ScanOptions{})
+}
+
+// ScanPackageForAnnotationsFunc is ScanPackageForAnnotations streaming each result to fn as soon as the file
+// declaring it has been scanned, instead of collecting all results first. Files are scanned one at a time in
+// package order; map-style registrations are only reported after the last file, once it is known that no
+// annotation registers the same terraform type. The scan stops and returns the first error returned by fn,
+// later files are not scanned
+func ScanPackageForAnnotationsFunc(packageInfo *gophon.PackageInfo, fn func(AnnotationResult) error) error {
+	return scanPackageForAnnotationsFunc(packageInfo, ScanOptions{}, fn)
+}
+
+// parallelScanMinFiles is the number of files from which scanPackageForAnnotations scans the files of a package
+// in parallel, smaller packages are not worth the goroutines
+const parallelScanMinFiles = 16
+
+// scanPackageForAnnotations is ScanPackageForAnnotations reporting skipped files to the options' logger and
+// extracting only the categories the options include. It accumulates the results of scanPackageFiles, which scans
+// the files of large packages in parallel but still reports in package order, so the outcome is the same either way
+func scanPackageForAnnotations(packageInfo *gophon.PackageInfo, options ScanOptions) (*AnnotationResults, error) {
 	results := NewAnnotationResults()
+	err := scanPackageFiles(packageInfo, options, true, func(result AnnotationResult) error {
+		results.Add(result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
 
-	// Scan each file in the package
-	for _, fileInfo := range packageInfo.Files {
-		fileResults, err := scanFileForAnnotations(fileInfo)
-		if err != nil {
+// scanPackageForAnnotationsFunc is ScanPackageForAnnotationsFunc reporting skipped files to the options' logger and
+// extracting only the categories the options include
+func scanPackageForAnnotationsFunc(packageInfo *gophon.PackageInfo, options ScanOptions, fn func(AnnotationResult) error) error {
+	return scanPackageFiles(packageInfo, options, false, fn)
+}
+
+// scanPackageFiles scans the files of a package in package order and streams their registrations to fn, followed
+// by the map-style registrations no annotation registers. Files that cannot be scanned are logged and skipped.
+// It stops at the first error returned by fn. With parallel set, the files of large packages are all scanned
+// up front in parallel, otherwise each file is only scanned once the previous one has been reported
+func scanPackageFiles(packageInfo *gophon.PackageInfo, options ScanOptions, parallel bool, fn func(AnnotationResult) error) error {
+	logger := options.logger()
+	scanner := newPackageAnnotationScanner(packageInfo, options.includesAnnotationType)
+
+	files := packageInfo.Files
+	fileResults := make([][]AnnotationResult, len(files))
+	fileErrors := make([]error, len(files))
+	scanned := parallel && len(files) >= parallelScanMinFiles
+	if scanned {
+		tasks := make([]func() error, len(files))
+		for i, fileInfo := range files {
+			tasks[i] = func() error {
+				fileResults[i], fileErrors[i] = scanner.scanFile(fileInfo)
+				return nil
+			}
+		}
+		_ = processCallbacksParallel(tasks)
+	}
+
+	for i, fileInfo := range files {
+		if !scanned {
+			fileResults[i], fileErrors[i] = scanner.scanFile(fileInfo)
+		}
+		if fileErrors[i] != nil {
 			// Log error but continue with other files
+			logger.Warnf("skipping file: %v", fileErrors[i])
 			continue
 		}
+		if err := scanner.report(fileResults[i], fn); err != nil {
+			return err
+		}
+	}
+
+	return scanner.reportMapRegistrations(fn)
+}
+
+// packageAnnotationScanner scans the files of one package for registrations and reports them, holding back
+// map-style registrations until every file has been reported, since an annotation elsewhere in the package for
+// the same terraform type takes precedence. scanFile is safe for concurrent use, report is not
+type packageAnnotationScanner struct {
+	lines            map[string]int
+	mapRegistrations map[string][]basicAnnotation
+	includes         func(AnnotationType) bool // Annotation types to extract, nil extracts all
+
+	mapResults []AnnotationResult
+	annotated  map[AnnotationType]map[string]bool
+}
+
+// newPackageAnnotationScanner prepares the package-wide lookups needed to scan the files of packageInfo for
+// registrations of the annotation types includes accepts, all types when includes is nil
+func newPackageAnnotationScanner(packageInfo *gophon.PackageInfo, includes func(AnnotationType) bool) *packageAnnotationScanner {
+	return &packageAnnotationScanner{
+		lines:            declarationLines(packageInfo),
+		mapRegistrations: findMapRegistrations(packageInfo),
+		includes:         includes,
+		annotated:        make(map[AnnotationType]map[string]bool),
+	}
+}
+
+// scanFile returns the registrations declared in fileInfo with their source lines resolved
+func (s *packageAnnotationScanner) scanFile(fileInfo *gophon.FileInfo) ([]AnnotationResult, error) {
+	fileResults, err := scanFileForRegistrations(fileInfo, s.mapRegistrations, s.includes)
+	if err != nil {
+		return nil, err
+	}
+	for i := range fileResults {
+		declName := fileResults[i].FunctionName
+		if declName == "" {
+			declName = fileResults[i].StructType // Annotations on a type declaration
+		}
+		fileResults[i].SourceLine = s.lines[declName]
+	}
+	return fileResults, nil
+}
+
+// report passes the annotated registrations of one file to fn and holds back its map-style registrations,
+// stopping at the first error returned by fn
+func (s *packageAnnotationScanner) report(fileResults []AnnotationResult, fn func(AnnotationResult) error) error {
+	for _, result := range fileResults {
+		if result.RegistrationMethod != "" {
+			s.mapResults = append(s.mapResults, result)
+			continue
+		}
+		if s.annotated[result.Type] == nil {
+			s.annotated[result.Type] = make(map[string]bool)
+		}
+		s.annotated[result.Type][result.TerraformType] = true
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportMapRegistrations passes the held back map-style registrations no annotation registers to fn, it is
+// called once after every file has been reported
+func (s *packageAnnotationScanner) reportMapRegistrations(fn func(AnnotationResult) error) error {
+	for _, result := range s.mapResults {
+		if s.annotated[result.Type][result.TerraformType] {
+			continue
+		}
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// declarationLines maps the package-level functions and types of the package to the line they are declared on,
+// taken from the ranges gophon records while scanning since the AST alone carries no line information.
+// Methods are left out, registration annotations only sit on functions and types
+func declarationLines(packageInfo *gophon.PackageInfo) map[string]int {
+	lines := make(map[string]int)
+	for _, funcInfo := range packageInfo.Functions {
+		if funcInfo.Range != nil && funcInfo.FuncDecl != nil && funcInfo.FuncDecl.Recv == nil {
+			lines[funcInfo.Name] = funcInfo.StartLine
+		}
+	}
+	for _, typeInfo := range packageInfo.Types {
+		if typeInfo.Range != nil {
+			lines[typeInfo.Name] = typeInfo.StartLine
+		}
+	}
+	return lines
+}
 
-		// Merge file results into package results
-		for _, result := range fileResults {
-			results.Add(result)
+// scanFileForAnnotations scans a single Go file for annotations and extracts relevant info
+func scanFileForAnnotations(fileInfo *gophon.FileInfo) ([]AnnotationResult, error) {
+	return scanFileForRegistrations(fileInfo, nil, nil)
+}
+
+// scanFileForRegistrations is scanFileForAnnotations also extracting the factory functions declared in the file
+// that are registered through a map, see findMapRegistrations. Only annotation types includes accepts are
+// extracted, a nil includes extracts all
+func scanFileForRegistrations(fileInfo *gophon.FileInfo, mapRegistrations map[string][]basicAnnotation, includes func(AnnotationType) bool) ([]AnnotationResult, error) {
+	var results []AnnotationResult
+
+	// gophon only populates FileName when scanning packages from disk
+	filePath := fileInfo.FilePath
+	if filePath == "" {
+		filePath = fileInfo.FileName
+	}
+
+	if fileInfo.File == nil {
+		return results, fmt.Errorf("no AST available for file %s", filePath)
+	}
+
+	// First, scan for any annotations in the file
+	annotations := append(findAnnotationsInFile(fileInfo.File), mapRegistrationsInFile(fileInfo.File, mapRegistrations)...)
+	if includes != nil {
+		// Excluded types are dropped before their context is extracted, saving the AST walks
+		annotations = slices.DeleteFunc(annotations, func(annotation basicAnnotation) bool {
+			return !includes(annotation.Type)
+		})
+	}
+	if len(annotations) == 0 {
+		return results, nil // No annotations found
+	}
+
+	buildTags := fileBuildTags(fileInfo.File)
+
+	// CRUD methods of SDK resource factories, shared by every terraform type registered through the same factory
+	sdkCRUDCache := make(map[string]map[string]string)
+
+	// For each annotation found, extract the full context from the file
+	for _, annotation := range annotations {
+		result := AnnotationResult{
+			Type:          annotation.Type,
+			TerraformType: annotation.TerraformType,
+			Name:          annotation.Name,
+			FilePath:      filePath,
+			RawAnnotation: annotation.RawAnnotation,
+			FunctionName:  annotation.FunctionName,
+			Aliases:       annotation.Aliases,
+			Identity:      annotation.Identity,
+			Region:        annotation.Region,
+			TestingConfig: annotation.TestingConfig,
+			BuildTags:     buildTags,
+			SourceHash:    funcSourceHash(annotation.Decl),
+
+			FactoryReturnType:  factoryReturnType(annotation.Decl),
+			RegistrationMethod: annotation.RegistrationMethod,
+		}
+
+		// Extract type-specific information from the file
+		switch annotation.Type {
+		case AnnotationSDKResource:
+			result.CRUDMethods = extractSDKResourceCRUDForFunction(fileInfo.File, annotation.Decl, sdkCRUDCache)
+			result.NoopMethods = extractSDKResourceNoopMethodsFromFile(fileInfo.File, annotation.Decl)
+			result.Description, result.DeprecationMessage = extractSDKResourceDocumentationFromFile(fileInfo.File, annotation.Decl)
+			result.SchemaVersion, result.StateUpgraders = extractSDKResourceSchemaVersionFromFile(fileInfo.File, annotation.Decl)
+			result.Import = extractSDKResourceImporterFromFile(fileInfo.File, annotation.Decl)
+		case AnnotationSDKDataSource:
+			result.CRUDMethods = extractSDKDataSourceMethodsFromFile(fileInfo.File, annotation.Decl)
+		case AnnotationFrameworkResource, AnnotationFrameworkDataSource, AnnotationEphemeralResource:
+			// An annotated type names the struct directly, otherwise find it by Schema method -
+			// the struct that implements framework interfaces
+			result.StructType = annotation.TypeName
+			if result.StructType == "" {
+				result.StructType = extractFrameworkStructTypeBySchemaMethod(fileInfo.File)
+			}
+			result.FrameworkMethods = inferFrameworkMethods(annotation.Type)
+			result.FrameworkCRUDMethods = extractFrameworkMethodsFromFile(fileInfo.File, result.StructType)
+			result.ImplementedFrameworkMethods, result.MissingFrameworkMethods = splitFrameworkMethods(fileInfo.File, result.StructType, result.FrameworkMethods, result.FrameworkCRUDMethods)
+		}
+
+		// Tags apply to resources and data sources, ephemeral resources are never tagged
+		if annotation.Type != AnnotationEphemeralResource {
+			result.Tags = extractAWSTagsConfig(fileInfo.File, annotation, result.StructType)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// basicAnnotation represents a simple annotation found in comments
+type basicAnnotation struct {
+	Type          AnnotationType
+	TerraformType string
+	Name          string
+	RawAnnotation string
+	FunctionName  string   // Added to track which function has the annotation
+	Aliases       []string // Terraform types of further annotations of the same kind on the function
+	Identity      *AWSIdentityConfig
+	Region        *AWSRegionConfig
+	Tags          *AWSTagsConfig    // From a @Tags annotation, completed by extractAWSTagsConfig
+	TestingConfig map[string]string // Keyword arguments of the @Testing annotations
+	Decl          *ast.FuncDecl     // The annotated registration function, nil for TypeName annotations
+	TypeName      string            // Annotated type when the annotation sits on the struct declaration
+
+	// Map-style registration function listing the factory, e.g. "SupportedResources", empty for annotations
+	RegistrationMethod string
+}
+
+// findAnnotationsInFile searches for annotations in all function comments in the file, and in the doc comments
+// of type declarations for framework and ephemeral registrations placed on the struct instead of its factory
+func findAnnotationsInFile(file *ast.File) []basicAnnotation {
+	var annotations []basicAnnotation
+	var typeAnnotations []basicAnnotation
+
+	// Walk through all declarations looking for function and type comments
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			annotation, ok := parseRegistrationAnnotation(d.Doc)
+			if !ok {
+				continue
+			}
+			annotation.FunctionName = d.Name.Name // Capture the function name
+			annotation.Decl = d
+			annotations = append(annotations, annotation)
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				// A lone type declaration carries its comment on the GenDecl, a grouped one on each TypeSpec
+				doc := typeSpec.Doc
+				if doc == nil && len(d.Specs) == 1 {
+					doc = d.Doc
+				}
+				annotation, ok := parseRegistrationAnnotation(doc)
+				if !ok || annotation.Type == AnnotationSDKResource || annotation.Type == AnnotationSDKDataSource {
+					continue
+				}
+				annotation.TypeName = typeSpec.Name.Name
+				typeAnnotations = append(typeAnnotations, annotation)
+			}
+		}
+	}
+
+	// An annotation repeated on both the factory and its type is only counted once, from the factory
+	for _, typeAnnotation := range typeAnnotations {
+		duplicate := false
+		for _, annotation := range annotations {
+			if annotation.Type == typeAnnotation.Type && annotation.TerraformType == typeAnnotation.TerraformType {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			annotations = append(annotations, typeAnnotation)
+		}
+	}
+
+	return annotations
+}
+
+// parseRegistrationAnnotation parses the first registration annotation in a doc comment together with the
+// identity, tags and alias annotations next to it
+func parseRegistrationAnnotation(doc *ast.CommentGroup) (basicAnnotation, bool) {
+	if doc == nil {
+		return basicAnnotation{}, false
+	}
+
+	// Combine all comment lines, stripping comment markers so argument lists may span lines
+	var commentText strings.Builder
+	for _, comment := range doc.List {
+		commentText.WriteString(strings.TrimPrefix(comment.Text, "//"))
+		commentText.WriteString("\n")
+	}
+	text := commentText.String()
+
+	// Search for annotation patterns
+	loc := annotationRegex.FindStringSubmatchIndex(text)
+	if loc == nil {
+		return basicAnnotation{}, false
+	}
+
+	annotationType := text[loc[2]:loc[3]]
+	args, consumed, ok := parseAnnotationArguments(text[loc[1]:])
+	if !ok || len(args.Positional) == 0 || !terraformTypeRegex.MatchString(args.Positional[0]) {
+		return basicAnnotation{}, false
+	}
+
+	terraformType := args.Positional[0]
+
+	// The name may be given as a keyword or as the second positional argument
+	name := args.Keywords["name"]
+	if name == "" && len(args.Positional) > 1 {
+		name = args.Positional[1]
+	}
+
+	// Convert to enum type
+	var annoType AnnotationType
+	switch annotationType {
+	case "SDKResource":
+		annoType = AnnotationSDKResource
+	case "SDKDataSource":
+		annoType = AnnotationSDKDataSource
+	case "FrameworkResource":
+		annoType = AnnotationFrameworkResource
+	case "FrameworkDataSource":
+		annoType = AnnotationFrameworkDataSource
+	case "EphemeralResource":
+		annoType = AnnotationEphemeralResource
+	default:
+		return basicAnnotation{}, false // Skip unknown annotations
+	}
+
+	return basicAnnotation{
+		Type:          annoType,
+		TerraformType: terraformType,
+		Name:          name,
+		RawAnnotation: text[loc[0] : loc[1]+consumed],
+		Aliases:       parseAliasAnnotations(text[loc[1]+consumed:], annotationType),
+		Identity:      parseIdentityAnnotations(text),
+		Region:        parseRegionAnnotation(text),
+		Tags:          parseTagsAnnotation(text),
+		TestingConfig: parseTestingAnnotations(text),
+	}, true
+}
+
+// parseAliasAnnotations returns the terraform types of further annotations of the same kind following the
+// primary one, e.g. a second @SDKResource keeping a renamed resource's deprecated type working
+func parseAliasAnnotations(text, annotationType string) []string {
+	var aliases []string
+	for _, loc := range annotationRegex.FindAllStringSubmatchIndex(text, -1) {
+		if text[loc[2]:loc[3]] != annotationType {
+			continue
+		}
+		args, _, ok := parseAnnotationArguments(text[loc[1]:])
+		if !ok || len(args.Positional) == 0 || !terraformTypeRegex.MatchString(args.Positional[0]) {
+			continue
+		}
+		aliases = append(aliases, args.Positional[0])
+	}
+	return aliases
+}
+
+// parseIdentityAnnotations collects the identity annotations in a function's doc comment
+// It returns nil when the comment declares no identity
+func parseIdentityAnnotations(text string) *AWSIdentityConfig {
+	matches := identityAnnotationRegex.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	identity := &AWSIdentityConfig{}
+	for _, loc := range matches {
+		var args annotationArguments
+		if rest := text[loc[1]:]; strings.HasPrefix(rest, "(") {
+			parsed, _, ok := parseAnnotationArguments(rest[1:])
+			if !ok {
+				continue
+			}
+			args = parsed
+		}
+
+		switch text[loc[2]:loc[3]] {
+		case "IdentityAttribute":
+			if len(args.Positional) == 0 || args.Positional[0] == "" {
+				continue
+			}
+			identity.Attributes = append(identity.Attributes, AWSIdentityAttribute{
+				Name:     args.Positional[0],
+				Optional: args.Keywords["optional"] == "true",
+			})
+		case "ArnIdentity":
+			identity.ARNAttribute = defaultARNIdentityAttribute
+			if len(args.Positional) > 0 && args.Positional[0] != "" {
+				identity.ARNAttribute = args.Positional[0]
+			}
+		case "SingletonIdentity":
+			identity.Singleton = true
+		}
+	}
+
+	return identity
+}
+
+// parseRegionAnnotation parses the @Region annotation in a function's doc comment
+// It returns nil when the comment declares none, region override is enabled unless turned off explicitly
+func parseRegionAnnotation(text string) *AWSRegionConfig {
+	loc := regionAnnotationRegex.FindStringIndex(text)
+	if loc == nil {
+		return nil
+	}
+
+	var args annotationArguments
+	if rest := text[loc[1]:]; strings.HasPrefix(rest, "(") {
+		parsed, _, ok := parseAnnotationArguments(rest[1:])
+		if !ok {
+			return nil
+		}
+		args = parsed
+	}
+
+	return &AWSRegionConfig{
+		Global:          args.Keywords["global"] == "true",
+		OverrideEnabled: args.Keywords["overrideEnabled"] != "false",
+	}
+}
+
+// parseTestingAnnotations merges the keyword arguments of every @Testing annotation in a function's doc comment,
+// whatever their keys, a key repeated in a later annotation overrides the earlier value. Values are kept as
+// written after unquoting. It returns nil when the comment has no @Testing annotation with keyword arguments
+func parseTestingAnnotations(text string) map[string]string {
+	var config map[string]string
+	for _, loc := range testingAnnotationRegex.FindAllStringIndex(text, -1) {
+		args, _, ok := parseAnnotationArguments(text[loc[1]:])
+		if !ok {
+			continue
+		}
+		for key, value := range args.Keywords {
+			if config == nil {
+				config = make(map[string]string)
+			}
+			config[key] = value
+		}
+	}
+	return config
+}
+
+// extractSDKResourceCRUDFromFile extracts CRUD method names from SDK resource files
+func extractSDKResourceCRUDFromFile(file *ast.File) map[string]string {
+	methods := make(map[string]string)
+
+	// Look for function that returns *schema.Resource and extract CRUD methods
+	forEachReturnedCompositeLit(file, func(compositeLit *ast.CompositeLit) {
+		extractCRUDFromCompositeLit(compositeLit, methods)
+	})
+
+	return methods
+}
+
+// extractSDKResourceNoopMethodsFromFile returns the CRUD method types ("read", "delete", ...) of the resource built
+// by the annotated function decl that are intentionally set to a schema noop function such as schema.NoopContext,
+// sorted for stable output
+func extractSDKResourceNoopMethodsFromFile(file *ast.File, decl *ast.FuncDecl) []string {
+	noopSet := make(map[string]bool)
+
+	forEachSDKResourceLit(file, decl, func(compositeLit *ast.CompositeLit) {
+		for _, elt := range compositeLit.Elts {
+			keyValue, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			ident, ok := keyValue.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			methodType := crudMethodType(ident.Name)
+			if methodType == "" {
+				continue
+			}
+
+			if isSchemaNoopFunction(keyValue.Value) {
+				noopSet[methodType] = true
+			}
+		}
+	})
+
+	var noopMethods []string
+	for _, methodType := range []string{"create", "read", "update", "delete"} {
+		if noopSet[methodType] {
+			noopMethods = append(noopMethods, methodType)
+		}
+	}
+
+	return noopMethods
+}
+
+// extractSDKResourceDocumentationFromFile extracts the top-level Description and DeprecationMessage
+// fields of the &schema.Resource{...} literal built by the annotated function decl, see forEachSDKResourceLit.
+// Empty strings are returned when absent
+func extractSDKResourceDocumentationFromFile(file *ast.File, decl *ast.FuncDecl) (description, deprecationMessage string) {
+	forEachSDKResourceLit(file, decl, func(compositeLit *ast.CompositeLit) {
+		if !isSchemaResourceType(compositeLit.Type) {
+			return
+		}
+		for _, elt := range compositeLit.Elts {
+			keyValue, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			ident, ok := keyValue.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			switch ident.Name {
+			case "Description":
+				if value := resolveStringExpr(file, keyValue.Value); value != "" {
+					description = value
+				}
+			case "DeprecationMessage":
+				if value := resolveStringExpr(file, keyValue.Value); value != "" {
+					deprecationMessage = value
+				}
+			}
+		}
+	})
+
+	return description, deprecationMessage
+}
+
+// inlineStateUpgrader stands in for a state upgrader whose Upgrade function is a function literal
+const inlineStateUpgrader = "<inline>"
+
+// extractSDKResourceSchemaVersionFromFile extracts SchemaVersion and the Upgrade functions of StateUpgraders
+// from the schema.Resource literal built by the annotated function decl. SchemaVersion may be an integer literal
+// or a constant declared in the file
+func extractSDKResourceSchemaVersionFromFile(file *ast.File, decl *ast.FuncDecl) (schemaVersion int, stateUpgraders []string) {
+	forEachSDKResourceLit(file, decl, func(compositeLit *ast.CompositeLit) {
+		for _, elt := range compositeLit.Elts {
+			keyValue, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			ident, ok := keyValue.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			switch ident.Name {
+			case "SchemaVersion":
+				if value, ok := resolveIntExpr(file, keyValue.Value); ok {
+					schemaVersion = value
+				}
+			case "StateUpgraders":
+				if upgraders := extractStateUpgraders(keyValue.Value); len(upgraders) > 0 {
+					stateUpgraders = upgraders
+				}
+			}
+		}
+	})
+
+	return schemaVersion, stateUpgraders
+}
+
+// extractSDKResourceImporterFromFile extracts the Importer field of the schema.Resource literal built by the
+// annotated function decl. It returns nil when there is no importer, and leaves ImportStateFunc empty for
+// passthrough and function literal importers
+func extractSDKResourceImporterFromFile(file *ast.File, decl *ast.FuncDecl) *AWSImportConfig {
+	var importConfig *AWSImportConfig
+	forEachSDKResourceLit(file, decl, func(compositeLit *ast.CompositeLit) {
+		for _, elt := range compositeLit.Elts {
+			keyValue, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			ident, ok := keyValue.Key.(*ast.Ident)
+			if !ok || ident.Name != "Importer" {
+				continue
+			}
+
+			if config := parseResourceImporter(keyValue.Value); config != nil {
+				importConfig = config
+			}
+		}
+	})
+
+	return importConfig
+}
+
+// parseResourceImporter reads the StateContext (or deprecated State) function of a &schema.ResourceImporter{...}
+// literal, telling the schema passthrough functions apart from custom ones
+func parseResourceImporter(expr ast.Expr) *AWSImportConfig {
+	importerLit := addressOfCompositeLit(expr)
+	if importerLit == nil {
+		return nil
+	}
+
+	importConfig := &AWSImportConfig{}
+	for _, elt := range importerLit.Elts {
+		keyValue, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		ident, ok := keyValue.Key.(*ast.Ident)
+		if !ok || (ident.Name != "StateContext" && ident.Name != "State") {
+			continue
+		}
+
+		if isSchemaImportPassthrough(keyValue.Value) {
+			importConfig.Passthrough = true
+			continue
+		}
+		importConfig.ImportStateFunc = crudFunctionName(keyValue.Value)
+	}
+
+	return importConfig
+}
+
+// isSchemaImportPassthrough checks whether an expression refers to schema.ImportStatePassthroughContext or
+// the deprecated schema.ImportStatePassthrough
+func isSchemaImportPassthrough(expr ast.Expr) bool {
+	selectorExpr, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	pkgIdent, ok := selectorExpr.X.(*ast.Ident)
+	return ok && pkgIdent.Name == "schema" && strings.HasPrefix(selectorExpr.Sel.Name, "ImportStatePassthrough")
+}
+
+// extractStateUpgraders returns the Upgrade function reference of each element of a []schema.StateUpgrader literal
+func extractStateUpgraders(expr ast.Expr) []string {
+	sliceLit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+
+	var upgraders []string
+	for _, elt := range sliceLit.Elts {
+		upgraderLit, ok := elt.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+
+		upgrader := ""
+		for _, field := range upgraderLit.Elts {
+			keyValue, ok := field.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if key, ok := keyValue.Key.(*ast.Ident); !ok || key.Name != "Upgrade" {
+				continue
+			}
+
+			switch value := keyValue.Value.(type) {
+			case *ast.Ident:
+				upgrader = value.Name
+			case *ast.SelectorExpr:
+				if pkgIdent, ok := value.X.(*ast.Ident); ok {
+					upgrader = pkgIdent.Name + "." + value.Sel.Name
+				}
+			case *ast.FuncLit:
+				upgrader = inlineStateUpgrader
+			}
+		}
+
+		if upgrader != "" {
+			upgraders = append(upgraders, upgrader)
+		}
+	}
+
+	return upgraders
+}
+
+// resolveIntExpr resolves an integer literal, or a constant declared in the file, to its value
+func resolveIntExpr(file *ast.File, expr ast.Expr) (int, bool) {
+	switch value := expr.(type) {
+	case *ast.BasicLit:
+		if value.Kind != token.INT {
+			return 0, false
+		}
+		parsed, err := strconv.ParseInt(value.Value, 0, 0)
+		if err != nil {
+			return 0, false
+		}
+		return int(parsed), true
+	case *ast.ParenExpr:
+		return resolveIntExpr(file, value.X)
+	case *ast.Ident:
+		if constValue := findConstantValue(file, value.Name); constValue != nil {
+			return resolveIntExpr(file, constValue)
+		}
+	}
+
+	return 0, false
+}
+
+// resolveStringExpr resolves a string expression to its value where possible
+// String literals and concatenations are evaluated, identifiers are looked up among the file's
+// string constants, and any other reference (e.g. names.SomeMessage) is returned as written.
+// Identifiers that aren't a constant of the file, e.g. local variables, resolve to an empty string
+func resolveStringExpr(file *ast.File, expr ast.Expr) string {
+	switch value := expr.(type) {
+	case *ast.BasicLit:
+		if value.Kind != token.STRING {
+			return ""
+		}
+		unquoted, err := strconv.Unquote(value.Value)
+		if err != nil {
+			return ""
+		}
+		return unquoted
+	case *ast.BinaryExpr:
+		if value.Op != token.ADD {
+			return ""
+		}
+		return resolveStringExpr(file, value.X) + resolveStringExpr(file, value.Y)
+	case *ast.ParenExpr:
+		return resolveStringExpr(file, value.X)
+	case *ast.Ident:
+		if constValue := findConstantValue(file, value.Name); constValue != nil {
+			return resolveStringExpr(file, constValue)
+		}
+	case *ast.SelectorExpr:
+		if pkgIdent, ok := value.X.(*ast.Ident); ok {
+			return pkgIdent.Name + "." + value.Sel.Name
+		}
+	}
+
+	return ""
+}
+
+// findConstantValue returns the value expression of a top-level constant declared in the file
+func findConstantValue(file *ast.File, name string) ast.Expr {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+
+			for i, ident := range valueSpec.Names {
+				if ident.Name == name && i < len(valueSpec.Values) {
+					return valueSpec.Values[i]
+				}
+			}
 		}
 	}
 
-	return results, nil
+	return nil
 }
 
-// scanFileForAnnotations scans a single Go file for annotations and extracts relevant info
-func scanFileForAnnotations(fileInfo *gophon.FileInfo) ([]AnnotationResult, error) {
-	var results []AnnotationResult
+// forEachReturnedCompositeLit calls fn for every &T{...} composite literal returned by a function in the file
+func forEachReturnedCompositeLit(file *ast.File, fn func(*ast.CompositeLit)) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		funcDecl, ok := n.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			return true
+		}
 
-	if fileInfo.File == nil {
-		return results, fmt.Errorf("no AST available for file %s", fileInfo.FilePath)
-	}
+		forEachFuncReturnedCompositeLit(funcDecl, fn)
+		return true
+	})
+}
 
-	// First, scan for any annotations in the file
-	annotations := findAnnotationsInFile(fileInfo.File)
-	if len(annotations) == 0 {
-		return results, nil // No annotations found
+// forEachFuncReturnedCompositeLit is like forEachReturnedCompositeLit for the return statements of a single function
+func forEachFuncReturnedCompositeLit(funcDecl *ast.FuncDecl, fn func(*ast.CompositeLit)) {
+	if funcDecl.Body == nil {
+		return
 	}
 
-	// For each annotation found, extract the full context from the file
-	for _, annotation := range annotations {
-		result := AnnotationResult{
-			Type:          annotation.Type,
-			TerraformType: annotation.TerraformType,
-			Name:          annotation.Name,
-			FilePath:      fileInfo.FilePath,
-			RawAnnotation: annotation.RawAnnotation,
+	// Variables assigned &schema.Resource{...} so `return r` and `return wrap(r)` can be resolved
+	assigned := collectSchemaResourceAssignments(funcDecl.Body)
+
+	// Look for return statements that return &schema.Resource{...}
+	ast.Inspect(funcDecl.Body, func(inner ast.Node) bool {
+		returnStmt, ok := inner.(*ast.ReturnStmt)
+		if !ok {
+			return true
 		}
 
-		// Extract type-specific information from the file
-		switch annotation.Type {
-		case AnnotationSDKResource:
-			result.CRUDMethods = extractSDKResourceCRUDFromFile(fileInfo.File)
-		case AnnotationSDKDataSource:
-			result.CRUDMethods = extractSDKDataSourceMethodsFromFile(fileInfo.File)
-		case AnnotationFrameworkResource, AnnotationFrameworkDataSource, AnnotationEphemeralResource:
-			// Find struct type by Schema method - the struct that implements framework interfaces
-			result.StructType = extractFrameworkStructTypeBySchemaMethod(fileInfo.File)
-			result.FrameworkMethods = inferFrameworkMethods(annotation.Type)
+		for _, result := range returnStmt.Results {
+			if compositeLit := resolveReturnedCompositeLit(result, assigned); compositeLit != nil {
+				fn(compositeLit)
+			}
 		}
+		return true
+	})
+}
 
-		results = append(results, result)
+// extractSDKResourceCRUDForFunction extracts the CRUD methods of the schema.Resource returned by an annotated
+// registration function. A function delegating to a shared generic factory, e.g. `return resourceGeneric("a")`,
+// gets the factory's CRUD methods; cache holds them by factory name so every terraform type referencing the same
+// factory shares one extraction. When no schema.Resource literal can be resolved the whole file is searched
+func extractSDKResourceCRUDForFunction(file *ast.File, decl *ast.FuncDecl, cache map[string]map[string]string) map[string]string {
+	factory := sdkResourceFactory(file, decl)
+	if factory == nil {
+		return extractSDKResourceCRUDFromFile(file)
 	}
 
-	return results, nil
+	if methods, exists := cache[factory.Name.Name]; exists {
+		return methods
+	}
+
+	methods := make(map[string]string)
+	forEachFuncReturnedCompositeLit(factory, func(compositeLit *ast.CompositeLit) {
+		extractCRUDFromCompositeLit(compositeLit, methods)
+	})
+	cache[factory.Name.Name] = methods
+	return methods
 }
 
-// basicAnnotation represents a simple annotation found in comments
-type basicAnnotation struct {
-	Type          AnnotationType
-	TerraformType string
-	Name          string
-	RawAnnotation string
-	FunctionName  string // Added to track which function has the annotation
+// forEachSDKResourceLit calls fn for every composite literal returned by the factory of the annotated function
+// decl, see sdkResourceFactory, so the fields of one resource don't leak into another declared in the same file.
+// Like extractSDKResourceCRUDForFunction, the whole file is searched when no factory can be resolved
+func forEachSDKResourceLit(file *ast.File, decl *ast.FuncDecl, fn func(*ast.CompositeLit)) {
+	factory := sdkResourceFactory(file, decl)
+	if factory == nil {
+		forEachReturnedCompositeLit(file, fn)
+		return
+	}
+	forEachFuncReturnedCompositeLit(factory, fn)
 }
 
-// findAnnotationsInFile searches for annotations in all function comments in the file
-func findAnnotationsInFile(file *ast.File) []basicAnnotation {
-	var annotations []basicAnnotation
+// sdkResourceFactory returns the function building the schema.Resource of the annotated function decl, either
+// decl itself or a function of the same file it returns a call to. It returns nil when neither returns one
+func sdkResourceFactory(file *ast.File, decl *ast.FuncDecl) *ast.FuncDecl {
+	if decl == nil {
+		return nil
+	}
+	if returnsSchemaResource(decl) {
+		return decl
+	}
 
-	// Walk through all declarations looking for function comments
-	for _, decl := range file.Decls {
-		funcDecl, ok := decl.(*ast.FuncDecl)
-		if !ok || funcDecl.Doc == nil {
-			continue
+	var factory *ast.FuncDecl
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if factory != nil {
+			return false
 		}
-
-		// Combine all comment lines
-		var commentText strings.Builder
-		for _, comment := range funcDecl.Doc.List {
-			commentText.WriteString(comment.Text)
-			commentText.WriteString("\n")
+		returnStmt, ok := n.(*ast.ReturnStmt)
+		if !ok {
+			return true
 		}
-
-		// Search for annotation patterns
-		matches := annotationRegex.FindStringSubmatch(commentText.String())
-		if len(matches) < 3 {
-			continue
+		for _, result := range returnStmt.Results {
+			callExpr, ok := result.(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			ident, ok := callExpr.Fun.(*ast.Ident)
+			if !ok || ident.Name == decl.Name.Name {
+				continue
+			}
+			if callee := findFileFuncDecl(file, ident.Name); callee != nil && returnsSchemaResource(callee) {
+				factory = callee
+				return false
+			}
 		}
+		return true
+	})
 
-		annotationType := matches[1]
-		terraformType := matches[2]
-		name := ""
-		if len(matches) > 3 && matches[3] != "" {
-			name = matches[3]
-		}
-
-		// Convert to enum type
-		var annoType AnnotationType
-		switch annotationType {
-		case "SDKResource":
-			annoType = AnnotationSDKResource
-		case "SDKDataSource":
-			annoType = AnnotationSDKDataSource
-		case "FrameworkResource":
-			annoType = AnnotationFrameworkResource
-		case "FrameworkDataSource":
-			annoType = AnnotationFrameworkDataSource
-		case "EphemeralResource":
-			annoType = AnnotationEphemeralResource
-		default:
-			continue // Skip unknown annotations
+	return factory
+}
+
+// returnsSchemaResource reports whether funcDecl returns a &schema.Resource{...} literal
+func returnsSchemaResource(funcDecl *ast.FuncDecl) bool {
+	found := false
+	forEachFuncReturnedCompositeLit(funcDecl, func(compositeLit *ast.CompositeLit) {
+		found = found || isSchemaResourceType(compositeLit.Type)
+	})
+	return found
+}
+
+// findFileFuncDecl finds the declaration of a package-level function in file
+func findFileFuncDecl(file *ast.File, funcName string) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil && funcDecl.Name.Name == funcName {
+			return funcDecl
 		}
+	}
+	return nil
+}
 
-		annotations = append(annotations, basicAnnotation{
-			Type:          annoType,
-			TerraformType: terraformType,
-			Name:          name,
-			RawAnnotation: matches[0],
-			FunctionName:  funcDecl.Name.Name, // Capture the function name
-		})
+// resolveReturnedCompositeLit resolves a returned expression to the composite literal it returns
+// Besides `return &T{...}` this handles `return r` for a variable holding &schema.Resource{...}, and
+// one level of wrapper call such as `return tfresource.NewResource(&schema.Resource{...})` or `return withRegionOverride(r)`
+func resolveReturnedCompositeLit(expr ast.Expr, assigned map[string]*ast.CompositeLit) *ast.CompositeLit {
+	if compositeLit := addressOfCompositeLit(expr); compositeLit != nil {
+		return compositeLit
 	}
 
-	return annotations
+	if ident, ok := expr.(*ast.Ident); ok {
+		return assigned[ident.Name]
+	}
+
+	callExpr, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	for _, arg := range callExpr.Args {
+		if compositeLit := addressOfCompositeLit(arg); compositeLit != nil && isSchemaResourceType(compositeLit.Type) {
+			return compositeLit
+		}
+		if ident, ok := arg.(*ast.Ident); ok && assigned[ident.Name] != nil {
+			return assigned[ident.Name]
+		}
+	}
+	return nil
 }
 
-// extractSDKResourceCRUDFromFile extracts CRUD method names from SDK resource files
-func extractSDKResourceCRUDFromFile(file *ast.File) map[string]string {
-	methods := make(map[string]string)
+// addressOfCompositeLit returns the composite literal of an `&T{...}` expression
+func addressOfCompositeLit(expr ast.Expr) *ast.CompositeLit {
+	unaryExpr, ok := expr.(*ast.UnaryExpr)
+	if !ok || unaryExpr.Op != token.AND {
+		return nil
+	}
+	compositeLit, _ := unaryExpr.X.(*ast.CompositeLit)
+	return compositeLit
+}
 
-	// Look for function that returns *schema.Resource and extract CRUD methods
-	ast.Inspect(file, func(n ast.Node) bool {
-		funcDecl, ok := n.(*ast.FuncDecl)
-		if !ok || funcDecl.Body == nil {
-			return true
+// collectSchemaResourceAssignments maps variable names to the &schema.Resource{...} literal assigned to them in body.
+// Fields set on the variable after the literal, e.g. `r.CreateWithoutTimeout = resourceWidgetCreate`, are appended
+// to a copy of the literal as key-value elements, so they are read as if declared in the literal itself
+func collectSchemaResourceAssignments(body *ast.BlockStmt) map[string]*ast.CompositeLit {
+	assigned := make(map[string]*ast.CompositeLit)
+	record := func(name *ast.Ident, value ast.Expr) {
+		if compositeLit := addressOfCompositeLit(value); compositeLit != nil && isSchemaResourceType(compositeLit.Type) {
+			assigned[name.Name] = compositeLit
 		}
+	}
+	recordField := func(selectorExpr *ast.SelectorExpr, value ast.Expr) {
+		ident, ok := selectorExpr.X.(*ast.Ident)
+		if !ok || assigned[ident.Name] == nil {
+			return
+		}
+		compositeLit := *assigned[ident.Name]
+		compositeLit.Elts = append(append([]ast.Expr(nil), compositeLit.Elts...), &ast.KeyValueExpr{Key: selectorExpr.Sel, Value: value})
+		assigned[ident.Name] = &compositeLit
+	}
 
-		// Look for return statements that return &schema.Resource{...}
-		ast.Inspect(funcDecl.Body, func(inner ast.Node) bool {
-			returnStmt, ok := inner.(*ast.ReturnStmt)
-			if !ok {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			if len(stmt.Lhs) != len(stmt.Rhs) {
 				return true
 			}
-
-			for _, result := range returnStmt.Results {
-				unaryExpr, ok := result.(*ast.UnaryExpr)
-				if !ok || unaryExpr.Op != token.AND {
-					continue
-				}
-				if compositeLit, ok := unaryExpr.X.(*ast.CompositeLit); ok {
-					extractCRUDFromCompositeLit(compositeLit, methods)
+			for i, lhs := range stmt.Lhs {
+				switch target := lhs.(type) {
+				case *ast.Ident:
+					record(target, stmt.Rhs[i])
+				case *ast.SelectorExpr:
+					if stmt.Tok == token.ASSIGN {
+						recordField(target, stmt.Rhs[i])
+					}
 				}
 			}
-			return true
-		})
-
+		case *ast.ValueSpec:
+			if len(stmt.Names) != len(stmt.Values) {
+				return true
+			}
+			for i, name := range stmt.Names {
+				record(name, stmt.Values[i])
+			}
+		}
 		return true
 	})
 
-	return methods
+	return assigned
+}
+
+// isSchemaResourceType reports whether expr is the schema.Resource type
+func isSchemaResourceType(expr ast.Expr) bool {
+	selectorExpr, ok := expr.(*ast.SelectorExpr)
+	if !ok || selectorExpr.Sel.Name != "Resource" {
+		return false
+	}
+	ident, ok := selectorExpr.X.(*ast.Ident)
+	return ok && ident.Name == "schema"
+}
+
+// crudMethodType maps a schema.Resource field name like "ReadWithoutTimeout" to its CRUD method type
+func crudMethodType(fieldName string) string {
+	switch {
+	case strings.HasPrefix(fieldName, "Create"):
+		return "create"
+	case strings.HasPrefix(fieldName, "Read"):
+		return "read"
+	case strings.HasPrefix(fieldName, "Update"):
+		return "update"
+	case strings.HasPrefix(fieldName, "Delete"):
+		return "delete"
+	default:
+		return ""
+	}
+}
+
+// isSchemaNoopFunction checks whether an expression refers to a schema noop function like schema.NoopContext
+func isSchemaNoopFunction(expr ast.Expr) bool {
+	selectorExpr, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	pkgIdent, ok := selectorExpr.X.(*ast.Ident)
+	return ok && pkgIdent.Name == "schema" && strings.HasPrefix(selectorExpr.Sel.Name, "Noop")
 }
 
 // extractCRUDFromCompositeLit extracts CRUD methods from &schema.Resource{...} composite literal
@@ -198,88 +1214,63 @@ func extractCRUDFromCompositeLit(compositeLit *ast.CompositeLit, methods map[str
 			continue
 		}
 
-		var methodType string
-		if strings.HasPrefix(ident.Name, "Create") {
-			methodType = "create"
-		} else if strings.HasPrefix(ident.Name, "Read") {
-			methodType = "read"
-		} else if strings.HasPrefix(ident.Name, "Update") {
-			methodType = "update"
-		} else if strings.HasPrefix(ident.Name, "Delete") {
-			methodType = "delete"
-		} else {
+		methodType := crudMethodType(ident.Name)
+		if methodType == "" {
 			continue
 		}
 
-		// Extract function name - handle both identifiers and selector expressions
-		switch value := keyValue.Value.(type) {
-		case *ast.Ident:
-			methods[methodType] = value.Name
-		case *ast.SelectorExpr:
-			// Handle cases like schema.NoopContext
-			pkgIdent, ok := value.X.(*ast.Ident)
-			if !ok {
-				continue
-			}
+		if name := crudFunctionName(keyValue.Value); name != "" {
+			methods[methodType] = name
+		}
+	}
+}
 
-			fullName := pkgIdent.Name + "." + value.Sel.Name
-			// Skip special schema functions as requested - leave these empty
-			if !strings.HasPrefix(fullName, "schema.") {
-				methods[methodType] = fullName
-			}
-			// For schema.* cases, we intentionally don't add anything to methods
+// crudFunctionName returns the function referenced by a schema.Resource CRUD field value, either a plain
+// identifier or a package-qualified name like "tfec2.ReadFunc". It returns an empty string for anything else
+func crudFunctionName(expr ast.Expr) string {
+	// Extract function name - handle both identifiers and selector expressions
+	switch value := expr.(type) {
+	case *ast.Ident:
+		return value.Name
+	case *ast.SelectorExpr:
+		// Handle cases like schema.NoopContext
+		pkgIdent, ok := value.X.(*ast.Ident)
+		if !ok {
+			return ""
 		}
+
+		// Skip special schema functions as requested - leave these empty,
+		// noops are reported separately by extractSDKResourceNoopMethodsFromFile
+		if pkgIdent.Name == "schema" {
+			return ""
+		}
+		return pkgIdent.Name + "." + value.Sel.Name
 	}
+	return ""
 }
 
-// extractSDKDataSourceMethodsFromFile extracts read method from SDK data source files
-func extractSDKDataSourceMethodsFromFile(file *ast.File) map[string]string {
+// extractSDKDataSourceMethodsFromFile extracts the read method of the schema.Resource built by the annotated
+// data source function decl, see forEachSDKResourceLit
+func extractSDKDataSourceMethodsFromFile(file *ast.File, decl *ast.FuncDecl) map[string]string {
 	methods := make(map[string]string)
 
 	// Similar to SDK resources but only looking for Read method
-	ast.Inspect(file, func(n ast.Node) bool {
-		funcDecl, ok := n.(*ast.FuncDecl)
-		if !ok || funcDecl.Body == nil {
-			return true
-		}
-
-		ast.Inspect(funcDecl.Body, func(inner ast.Node) bool {
-			returnStmt, ok := inner.(*ast.ReturnStmt)
+	forEachSDKResourceLit(file, decl, func(compositeLit *ast.CompositeLit) {
+		for _, elt := range compositeLit.Elts {
+			keyValue, ok := elt.(*ast.KeyValueExpr)
 			if !ok {
-				return true
+				continue
 			}
 
-			for _, result := range returnStmt.Results {
-				unaryExpr, ok := result.(*ast.UnaryExpr)
-				if !ok || unaryExpr.Op != token.AND {
-					continue
-				}
-
-				compositeLit, ok := unaryExpr.X.(*ast.CompositeLit)
-				if !ok {
-					continue
-				}
-
-				for _, elt := range compositeLit.Elts {
-					keyValue, ok := elt.(*ast.KeyValueExpr)
-					if !ok {
-						continue
-					}
-
-					ident, ok := keyValue.Key.(*ast.Ident)
-					if !ok || !strings.HasPrefix(ident.Name, "Read") {
-						continue
-					}
-
-					if valueIdent, ok := keyValue.Value.(*ast.Ident); ok {
-						methods["read"] = valueIdent.Name
-					}
-				}
+			ident, ok := keyValue.Key.(*ast.Ident)
+			if !ok || !strings.HasPrefix(ident.Name, "Read") {
+				continue
 			}
-			return true
-		})
 
-		return true
+			if name := crudFunctionName(keyValue.Value); name != "" {
+				methods["read"] = name
+			}
+		}
 	})
 
 	return methods
@@ -322,25 +1313,15 @@ func findStructsWithSchemaMethod(file *ast.File) []string {
 			return true
 		}
 
-		// Check if this is a method on a struct (receiver should be a pointer to a struct)
-		if len(funcDecl.Recv.List) == 0 {
-			return true
-		}
-
-		field := funcDecl.Recv.List[0]
-		starExpr, ok := field.Type.(*ast.StarExpr)
-		if !ok {
-			return true
-		}
-
-		ident, ok := starExpr.X.(*ast.Ident)
-		if !ok {
+		// Pointer and value receivers both declare the method on the struct
+		structName := receiverTypeName(funcDecl)
+		if structName == "" {
 			return true
 		}
 
-		if !structSet[ident.Name] {
-			structSet[ident.Name] = true
-			structs = append(structs, ident.Name)
+		if !structSet[structName] {
+			structSet[structName] = true
+			structs = append(structs, structName)
 		}
 
 		return true
@@ -410,3 +1391,217 @@ func inferFrameworkMethods(annoType AnnotationType) []string {
 		return []string{}
 	}
 }
+
+// frameworkMetadataHelpers lists embeddable framework base types that implement Metadata
+var frameworkMetadataHelpers = map[string]bool{
+	"ResourceWithConfigure":          true,
+	"ResourceWithModel":              true,
+	"DataSourceWithConfigure":        true,
+	"DataSourceWithModel":            true,
+	"EphemeralResourceWithConfigure": true,
+	"EphemeralResourceWithModel":     true,
+}
+
+// splitFrameworkMethods splits the inferred methods of structType into those the struct declares or gets from an
+// embedded framework helper and those it lacks, such as a resource without Update. The order of inferred is kept
+func splitFrameworkMethods(file *ast.File, structType string, inferred []string, methods *AWSCRUDMethods) (implemented, missing []string) {
+	if structType == "" {
+		return nil, nil
+	}
+
+	declared := make(map[string]bool)
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if ok && funcDecl.Recv != nil && receiverTypeName(funcDecl) == structType {
+			declared[funcDecl.Name.Name] = true
+		}
+	}
+	if methods != nil {
+		for method := range methods.EmbeddedMethods {
+			declared[method] = true
+		}
+	}
+	if findEmbeddedFrameworkHelper(file, structType, frameworkMetadataHelpers) != "" {
+		declared["Metadata"] = true
+	}
+
+	for _, method := range inferred {
+		if declared[method] {
+			implemented = append(implemented, method)
+		} else {
+			missing = append(missing, method)
+		}
+	}
+	return implemented, missing
+}
+
+// frameworkImportHelpers lists embeddable framework helpers that implement ImportState
+var frameworkImportHelpers = map[string]bool{
+	"WithImportByID":       true,
+	"WithImportByIdentity": true,
+}
+
+// extractFrameworkMethodsFromFile collects the methods declared on a framework struct type in the file
+// and detects import support provided by embedded framework helpers
+func extractFrameworkMethodsFromFile(file *ast.File, structType string) *AWSCRUDMethods {
+	if structType == "" {
+		return nil
+	}
+
+	methods := &AWSCRUDMethods{}
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv == nil || receiverTypeName(funcDecl) != structType {
+			continue
+		}
+		mapFrameworkMethod(methods, funcDecl.Name.Name)
+
+		// Prefer the Schema receiver name, falling back to the first named receiver
+		if name := receiverVarName(funcDecl); name != "" && (methods.ReceiverName == "" || funcDecl.Name.Name == "Schema") {
+			methods.ReceiverName = name
+		}
+	}
+
+	// An explicit ImportState method takes precedence over embedded helpers
+	if methods.ImportStateMethod == "" {
+		methods.ImportStateMethod = findEmbeddedImportHelper(file, structType)
+	}
+
+	// Core methods not declared on the struct may be provided by embedded framework helpers
+	for _, helper := range embeddedFrameworkHelpers(file, structType) {
+		for _, method := range frameworkHelperMethods[helper] {
+			if frameworkMethodDeclared(methods, method) {
+				continue
+			}
+			if methods.EmbeddedMethods == nil {
+				methods.EmbeddedMethods = make(map[string]string)
+			}
+			if _, exists := methods.EmbeddedMethods[method]; !exists {
+				methods.EmbeddedMethods[method] = "framework." + helper
+			}
+		}
+	}
+
+	return methods
+}
+
+// frameworkHelperMethods lists the core lifecycle methods provided by embeddable framework helpers
+var frameworkHelperMethods = map[string][]string{
+	"WithNoOpUpdate":       {"Update"},
+	"WithNoUpdate":         {"Update"},
+	"WithNoOpDelete":       {"Delete"},
+	"WithImportByID":       {"ImportState"},
+	"WithImportByIdentity": {"ImportState"},
+}
+
+// frameworkMethodDeclared reports whether a core method was declared directly on the struct
+func frameworkMethodDeclared(methods *AWSCRUDMethods, method string) bool {
+	switch method {
+	case "Create":
+		return methods.CreateMethod != ""
+	case "Read":
+		return methods.ReadMethod != ""
+	case "Update":
+		return methods.UpdateMethod != ""
+	case "Delete":
+		return methods.DeleteMethod != ""
+	case "ImportState":
+		return methods.ImportStateMethod == "ImportState"
+	}
+	return false
+}
+
+// mapFrameworkMethod records a framework method name in the matching AWSCRUDMethods field
+func mapFrameworkMethod(methods *AWSCRUDMethods, methodName string) {
+	switch methodName {
+	case "Create":
+		methods.CreateMethod = methodName
+	case "Read":
+		methods.ReadMethod = methodName
+	case "Update":
+		methods.UpdateMethod = methodName
+	case "Delete":
+		methods.DeleteMethod = methodName
+	case "Schema":
+		methods.SchemaMethod = methodName
+	case "Open":
+		methods.OpenMethod = methodName
+	case "Renew":
+		methods.RenewMethod = methodName
+	case "Close":
+		methods.CloseMethod = methodName
+	case "ImportState":
+		methods.ImportStateMethod = methodName
+	case "ModifyPlan":
+		methods.ModifyPlanMethod = methodName
+	case "ValidateConfig":
+		methods.ValidateConfigMethod = methodName
+	case "ConfigValidators":
+		methods.ConfigValidatorsMethod = methodName
+	}
+}
+
+// findEmbeddedImportHelper returns the qualified name of an embedded framework import helper
+// such as "framework.WithImportByID", or an empty string when the struct embeds none
+func findEmbeddedImportHelper(file *ast.File, structType string) string {
+	return findEmbeddedFrameworkHelper(file, structType, frameworkImportHelpers)
+}
+
+// findEmbeddedFrameworkHelper returns the first framework.X embedded in structType whose name is in helpers,
+// e.g. "framework.WithImportByID", or "" when none is embedded
+func findEmbeddedFrameworkHelper(file *ast.File, structType string, helpers map[string]bool) string {
+	for _, helper := range embeddedFrameworkHelpers(file, structType) {
+		if helpers[helper] {
+			return "framework." + helper
+		}
+	}
+	return ""
+}
+
+// embeddedFrameworkHelpers returns the names of the framework.X types embedded in structType, in declaration order
+// Generic embeds such as framework.WithNoOpUpdate[widgetModel] are reported without their type arguments
+func embeddedFrameworkHelpers(file *ast.File, structType string) []string {
+	var helpers []string
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		typeSpec, ok := n.(*ast.TypeSpec)
+		if !ok || typeSpec.Name.Name != structType {
+			return true
+		}
+
+		structTypeDecl, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+
+		for _, field := range structTypeDecl.Fields.List {
+			if len(field.Names) != 0 { // Not an embedded field
+				continue
+			}
+
+			fieldType := field.Type
+			switch generic := fieldType.(type) {
+			case *ast.IndexExpr:
+				fieldType = generic.X
+			case *ast.IndexListExpr:
+				fieldType = generic.X
+			}
+
+			selectorExpr, ok := fieldType.(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+
+			ident, ok := selectorExpr.X.(*ast.Ident)
+			if !ok || ident.Name != "framework" {
+				continue
+			}
+
+			helpers = append(helpers, selectorExpr.Sel.Name)
+		}
+
+		return false
+	})
+
+	return helpers
+}
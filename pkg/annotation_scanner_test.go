@@ -2,9 +2,13 @@ package pkg
 
 import (
 	"embed"
+	"errors"
+	"fmt"
+	"go/ast"
 	"go/parser"
 	"go/token"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	gophon "github.com/lonegunmanb/gophon/pkg"
@@ -263,7 +267,7 @@ func TestSDKCRUDExtraction(t *testing.T) {
 			if filepath.Base(tc.filename) == "sdk_resource_aws_lambda_invocation.gocode" {
 				methods = extractSDKResourceCRUDFromFile(astFile)
 			} else {
-				methods = extractSDKDataSourceMethodsFromFile(astFile)
+				methods = extractSDKDataSourceMethodsFromFile(astFile, nil)
 			}
 
 			// Verify expected methods are found
@@ -283,6 +287,80 @@ func TestSDKCRUDExtraction(t *testing.T) {
 	}
 }
 
+// TestSDKDataSourceQualifiedReadExtraction tests that package-qualified Read functions are recorded
+// while schema noops are still skipped
+func TestSDKDataSourceQualifiedReadExtraction(t *testing.T) {
+	testCases := []struct {
+		name         string
+		readValue    string
+		expectedRead string
+	}{
+		{
+			name:         "Package-qualified ReadContext",
+			readValue:    "tfec2.FindWidgetRead",
+			expectedRead: "tfec2.FindWidgetRead",
+		},
+		{
+			name:         "Local function",
+			readValue:    "dataSourceWidgetRead",
+			expectedRead: "dataSourceWidgetRead",
+		},
+		{
+			name:      "Schema noop",
+			readValue: "schema.NoopContext",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file := parseSourceForTest(t, `package example
+
+// @SDKDataSource("aws_example_widget", name="Widget")
+func dataSourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: `+tc.readValue+`,
+	}
+}
+`)
+
+			methods := extractSDKDataSourceMethodsFromFile(file, findFileFuncDecl(file, "dataSourceWidget"))
+			if methods["read"] != tc.expectedRead {
+				t.Errorf("Expected read method %q, got %q", tc.expectedRead, methods["read"])
+			}
+		})
+	}
+}
+
+// TestSDKDataSourceReadExtractionPerFunction tests that each data source of a file gets its own read method
+func TestSDKDataSourceReadExtractionPerFunction(t *testing.T) {
+	file := parseSourceForTest(t, `package example
+
+// @SDKDataSource("aws_example_widget", name="Widget")
+func dataSourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceWidgetRead,
+	}
+}
+
+// @SDKDataSource("aws_example_widgets", name="Widgets")
+func dataSourceWidgets() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceWidgetsRead,
+	}
+}
+`)
+
+	for funcName, expectedRead := range map[string]string{
+		"dataSourceWidget":  "dataSourceWidgetRead",
+		"dataSourceWidgets": "dataSourceWidgetsRead",
+	} {
+		methods := extractSDKDataSourceMethodsFromFile(file, findFileFuncDecl(file, funcName))
+		if methods["read"] != expectedRead {
+			t.Errorf("Expected read method %q for %s, got %q", expectedRead, funcName, methods["read"])
+		}
+	}
+}
+
 // TestAnnotationRegexAgainstRealWorld tests the annotation regex against real comment patterns
 func TestAnnotationRegexAgainstRealWorld(t *testing.T) {
 	testCases := []struct {
@@ -406,3 +484,1994 @@ func stringToAnnotationType(s string) AnnotationType {
 		return AnnotationType("") // Invalid
 	}
 }
+
+// TestAnnotationArgumentParsing tests that the annotation name is parsed from keyword or positional
+// arguments and that trailing attributes do not break parsing
+func TestAnnotationArgumentParsing(t *testing.T) {
+	testCases := []struct {
+		name          string
+		comment       string
+		expectedFound bool
+		expectedTF    string
+		expectedName  string
+		expectedRaw   string
+	}{
+		{
+			name:          "Keyword name",
+			comment:       `// @FrameworkResource("aws_widget", name="Widget")`,
+			expectedFound: true,
+			expectedTF:    "aws_widget",
+			expectedName:  "Widget",
+			expectedRaw:   `@FrameworkResource("aws_widget", name="Widget")`,
+		},
+		{
+			name:          "Positional name",
+			comment:       `// @FrameworkResource("aws_widget", "Widget")`,
+			expectedFound: true,
+			expectedTF:    "aws_widget",
+			expectedName:  "Widget",
+			expectedRaw:   `@FrameworkResource("aws_widget", "Widget")`,
+		},
+		{
+			name:          "Keyword name after trailing attribute",
+			comment:       `// @FrameworkResource("aws_widget", tagSpec="tags(all)", name="Widget")`,
+			expectedFound: true,
+			expectedTF:    "aws_widget",
+			expectedName:  "Widget",
+			expectedRaw:   `@FrameworkResource("aws_widget", tagSpec="tags(all)", name="Widget")`,
+		},
+		{
+			name:          "Keyword name with trailing attributes",
+			comment:       `// @SDKResource("aws_widget", name="Widget", tagSpec=true, transparentTagging=false)`,
+			expectedFound: true,
+			expectedTF:    "aws_widget",
+			expectedName:  "Widget",
+			expectedRaw:   `@SDKResource("aws_widget", name="Widget", tagSpec=true, transparentTagging=false)`,
+		},
+		{
+			name:          "Name absent",
+			comment:       `// @EphemeralResource("aws_widget")`,
+			expectedFound: true,
+			expectedTF:    "aws_widget",
+			expectedName:  "",
+			expectedRaw:   `@EphemeralResource("aws_widget")`,
+		},
+		{
+			name:          "Name absent with trailing attributes",
+			comment:       `// @SDKDataSource("aws_widget", tagSpec=true)`,
+			expectedFound: true,
+			expectedTF:    "aws_widget",
+			expectedName:  "",
+			expectedRaw:   `@SDKDataSource("aws_widget", tagSpec=true)`,
+		},
+		{
+			name:          "Argument list spanning comment lines",
+			comment:       "// @FrameworkResource(\"aws_widget\",\n// name=\"Widget\")",
+			expectedFound: true,
+			expectedTF:    "aws_widget",
+			expectedName:  "Widget",
+		},
+		{
+			name:          "Name containing a comma",
+			comment:       `// @SDKResource("aws_s3_bucket", name="Bucket, Regional")`,
+			expectedFound: true,
+			expectedTF:    "aws_s3_bucket",
+			expectedName:  "Bucket, Regional",
+			expectedRaw:   `@SDKResource("aws_s3_bucket", name="Bucket, Regional")`,
+		},
+		{
+			name:          "Name containing escaped quotes and a parenthesis",
+			comment:       `// @SDKResource("aws_s3_bucket", name="Bucket \"Regional\" (legacy)", tagSpec=true)`,
+			expectedFound: true,
+			expectedTF:    "aws_s3_bucket",
+			expectedName:  `Bucket "Regional" (legacy)`,
+			expectedRaw:   `@SDKResource("aws_s3_bucket", name="Bucket \"Regional\" (legacy)", tagSpec=true)`,
+		},
+		{
+			name:          "Positional name containing a comma",
+			comment:       `// @FrameworkResource("aws_widget", "Widget, Global")`,
+			expectedFound: true,
+			expectedTF:    "aws_widget",
+			expectedName:  "Widget, Global",
+		},
+		{
+			name:          "Terraform type that is not a plain identifier",
+			comment:       `// @SDKResource("aws widget, legacy", name="Widget")`,
+			expectedFound: false,
+		},
+		{
+			name:          "Missing terraform type",
+			comment:       `// @FrameworkResource(name="Widget")`,
+			expectedFound: false,
+		},
+		{
+			name:          "Unterminated argument list",
+			comment:       `// @FrameworkResource("aws_widget", name="Widget"`,
+			expectedFound: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := "package test\n\n" + tc.comment + "\nfunc newWidget() {}\n"
+
+			fset := token.NewFileSet()
+			astFile, err := parser.ParseFile(fset, "test.go", source, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse source: %v", err)
+			}
+
+			annotations := findAnnotationsInFile(astFile)
+			if !tc.expectedFound {
+				if len(annotations) != 0 {
+					t.Fatalf("Expected no annotations, got %d", len(annotations))
+				}
+				return
+			}
+
+			if len(annotations) != 1 {
+				t.Fatalf("Expected 1 annotation, got %d", len(annotations))
+			}
+
+			annotation := annotations[0]
+			if annotation.TerraformType != tc.expectedTF {
+				t.Errorf("Expected terraform type %s, got %s", tc.expectedTF, annotation.TerraformType)
+			}
+			if annotation.Name != tc.expectedName {
+				t.Errorf("Expected name %q, got %q", tc.expectedName, annotation.Name)
+			}
+			if tc.expectedRaw != "" && annotation.RawAnnotation != tc.expectedRaw {
+				t.Errorf("Expected raw annotation %s, got %s", tc.expectedRaw, annotation.RawAnnotation)
+			}
+		})
+	}
+}
+
+// TestFrameworkSchemaAttributeExtraction tests attribute name extraction from a framework resource Schema method
+func TestFrameworkSchemaAttributeExtraction(t *testing.T) {
+	content, err := testHarnessFS.ReadFile("testharness/framework_resource_aws_bedrock_guardrail.gocode")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	astFile, err := parser.ParseFile(fset, "framework_resource_aws_bedrock_guardrail.gocode", string(content), parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Failed to parse source: %v", err)
+	}
+
+	packageInfo := &gophon.PackageInfo{
+		Files: []*gophon.FileInfo{{File: astFile}},
+	}
+
+	attributes := extractFrameworkSchemaAttributes(packageInfo, "guardrailResource")
+
+	expectedAttributes := []string{
+		// String literal attribute keys
+		"blocked_input_messaging",
+		"blocked_outputs_messaging",
+		"guardrail_arn",
+		"guardrail_id",
+		// names.Attr* constant keys
+		"created_at",
+		"description",
+		"kms_key_arn",
+		"name",
+		"status",
+		"tags",
+		"tags_all",
+		"version",
+		// Blocks
+		"content_policy_config",
+	}
+
+	attributeSet := make(map[string]bool)
+	for _, attribute := range attributes {
+		attributeSet[attribute] = true
+	}
+	for _, expected := range expectedAttributes {
+		if !attributeSet[expected] {
+			t.Errorf("Expected attribute %s not found in %v", expected, attributes)
+		}
+	}
+
+	// Nested block attributes must not leak into the top-level list
+	if attributeSet["filters_config"] || attributeSet["type"] {
+		t.Errorf("Nested attributes should not be extracted: %v", attributes)
+	}
+
+	// Unknown struct types yield no attributes
+	if attrs := extractFrameworkSchemaAttributes(packageInfo, "unknownResource"); attrs != nil {
+		t.Errorf("Expected no attributes for unknown struct, got %v", attrs)
+	}
+}
+
+// TestSDKSchemaNestedAttributeExtraction tests that SDK nested blocks expose their child attributes one level deep
+func TestSDKSchemaNestedAttributeExtraction(t *testing.T) {
+	source := `package example
+
+// @SDKResource("aws_example_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+		Schema: map[string]*schema.Schema{
+			names.AttrName: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"priority": {Type: schema.TypeInt, Required: true},
+						names.AttrAction: {Type: schema.TypeString, Required: true},
+						"condition": {
+							Type: schema.TypeList,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"field": {Type: schema.TypeString, Required: true},
+								},
+							},
+						},
+					},
+				},
+			},
+			"zones": {
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+`
+	serviceReg := CreateTestServiceRegistration("example")
+	serviceReg.Package = &gophon.PackageInfo{
+		Files: []*gophon.FileInfo{{File: parseSourceForTest(t, source)}},
+	}
+
+	resource := NewTerraformResourceFromAWSSDK(AWSResource{TerraformType: "aws_example_widget", FactoryFunction: "resourceWidget"}, serviceReg)
+
+	expectedAttributes := []string{"name", "rule", "zones"}
+	if !reflect.DeepEqual(resource.Attributes, expectedAttributes) {
+		t.Errorf("Expected attributes %v, got %v", expectedAttributes, resource.Attributes)
+	}
+
+	// Only blocks get nested attributes, deeper blocks are listed by name but not expanded
+	expectedNested := map[string][]string{
+		"rule": {"action", "condition", "priority"},
+	}
+	if !reflect.DeepEqual(resource.NestedAttributes, expectedNested) {
+		t.Errorf("Expected nested attributes %v, got %v", expectedNested, resource.NestedAttributes)
+	}
+
+	// Without the package AST nothing is extracted
+	serviceReg.Package = nil
+	resource = NewTerraformResourceFromAWSSDK(AWSResource{TerraformType: "aws_example_widget", FactoryFunction: "resourceWidget"}, serviceReg)
+	if resource.Attributes != nil || resource.NestedAttributes != nil {
+		t.Errorf("Expected no attributes without package info, got %v and %v", resource.Attributes, resource.NestedAttributes)
+	}
+}
+
+// TestSDKSchemaDefaultFuncExtraction tests that package functions supplying attribute defaults are indexed
+func TestSDKSchemaDefaultFuncExtraction(t *testing.T) {
+	source := `package example
+
+// @SDKResource("aws_example_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+		Schema: map[string]*schema.Schema{
+			names.AttrRegion: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: regionDefault,
+			},
+			"endpoint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: envDefault("WIDGET_ENDPOINT"),
+			},
+			"label": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  defaultLabel(),
+			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("AWS_PROFILE", nil),
+			},
+			"size": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  defaultSize,
+			},
+		},
+	}
+}
+
+const defaultSize = 1
+
+func regionDefault() (interface{}, error) {
+	return "us-east-1", nil
+}
+
+func envDefault(name string) schema.SchemaDefaultFunc {
+	return nil
+}
+
+func defaultLabel() string {
+	return "widget"
+}
+`
+	serviceReg := CreateTestServiceRegistration("example")
+	serviceReg.Package = &gophon.PackageInfo{
+		Files: []*gophon.FileInfo{{File: parseSourceForTest(t, source)}},
+	}
+
+	resource := NewTerraformResourceFromAWSSDK(AWSResource{TerraformType: "aws_example_widget", FactoryFunction: "resourceWidget"}, serviceReg)
+
+	// Constants and functions of other packages have no function index
+	expected := map[string]string{
+		"region":   "func.regionDefault.goindex",
+		"endpoint": "func.envDefault.goindex",
+		"label":    "func.defaultLabel.goindex",
+	}
+	if !reflect.DeepEqual(resource.DefaultFuncIndexes, expected) {
+		t.Errorf("Expected default function indexes %v, got %v", expected, resource.DefaultFuncIndexes)
+	}
+
+	// Without the package AST nothing is extracted
+	serviceReg.Package = nil
+	resource = NewTerraformResourceFromAWSSDK(AWSResource{TerraformType: "aws_example_widget", FactoryFunction: "resourceWidget"}, serviceReg)
+	if resource.DefaultFuncIndexes != nil {
+		t.Errorf("Expected no default function indexes without package info, got %v", resource.DefaultFuncIndexes)
+	}
+}
+
+// TestCamelCaseToSnakeCase tests conversion of names.Attr* constant suffixes to attribute names
+func TestWriteOnlyAttributeExtraction(t *testing.T) {
+	source := `package example
+
+// @SDKResource("aws_example_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			names.AttrName: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"password_wo": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				WriteOnly: true,
+			},
+			"secret": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				WriteOnly: false,
+			},
+		},
+	}
+}
+
+func (r *gadgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			names.AttrID: framework.IDAttribute(),
+			"token_wo": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+		},
+	}
+}
+`
+	serviceReg := CreateTestServiceRegistration("example")
+	serviceReg.Package = &gophon.PackageInfo{
+		Files: []*gophon.FileInfo{{File: parseSourceForTest(t, source)}},
+	}
+
+	sdkResource := NewTerraformResourceFromAWSSDK(AWSResource{TerraformType: "aws_example_widget", FactoryFunction: "resourceWidget"}, serviceReg)
+	if !sdkResource.HasWriteOnlyAttributes {
+		t.Errorf("Expected SDK resource to have write-only attributes")
+	}
+	if expected := []string{"password_wo"}; !reflect.DeepEqual(sdkResource.WriteOnlyAttributes, expected) {
+		t.Errorf("Expected SDK write-only attributes %v, got %v", expected, sdkResource.WriteOnlyAttributes)
+	}
+
+	frameworkResource := NewTerraformResourceFromAWSFramework(AWSResource{TerraformType: "aws_example_gadget", StructType: "gadgetResource"}, serviceReg, serviceReg.Package)
+	if !frameworkResource.HasWriteOnlyAttributes {
+		t.Errorf("Expected framework resource to have write-only attributes")
+	}
+	if expected := []string{"token_wo"}; !reflect.DeepEqual(frameworkResource.WriteOnlyAttributes, expected) {
+		t.Errorf("Expected framework write-only attributes %v, got %v", expected, frameworkResource.WriteOnlyAttributes)
+	}
+
+	// A resource without write-only attributes leaves both fields unset
+	serviceReg.Package = nil
+	sdkResource = NewTerraformResourceFromAWSSDK(AWSResource{TerraformType: "aws_example_widget", FactoryFunction: "resourceWidget"}, serviceReg)
+	if sdkResource.HasWriteOnlyAttributes || sdkResource.WriteOnlyAttributes != nil {
+		t.Errorf("Expected no write-only attributes without the package AST, got %v", sdkResource.WriteOnlyAttributes)
+	}
+}
+
+func TestCamelCaseToSnakeCase(t *testing.T) {
+	testCases := map[string]string{
+		"CreatedAt": "created_at",
+		"KMSKeyARN": "kms_key_arn",
+		"TagsAll":   "tags_all",
+		"ID":        "id",
+		"Name":      "name",
+		"IPV6CIDR":  "ipv6_cidr",
+	}
+
+	for input, expected := range testCases {
+		if actual := camelCaseToSnakeCase(input); actual != expected {
+			t.Errorf("camelCaseToSnakeCase(%s): expected %s, got %s", input, expected, actual)
+		}
+	}
+}
+
+// TestNamesAttributeConstantConversion tests that names.Attr* constants resolve to snake_case attribute names
+// both as schema keys and as @Tags identifier attributes
+func TestNamesAttributeConstantConversion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		constant string
+		expected string
+	}{
+		{name: "Single word", constant: "AttrBucket", expected: "bucket"},
+		{name: "Two words", constant: "AttrBucketName", expected: "bucket_name"},
+		{name: "Acronym", constant: "AttrARN", expected: "arn"},
+		{name: "Acronym prefix", constant: "AttrKMSKeyID", expected: "kms_key_id"},
+		{name: "Acronym suffix", constant: "AttrResourceARN", expected: "resource_arn"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key := &ast.SelectorExpr{X: ast.NewIdent("names"), Sel: ast.NewIdent(tc.constant)}
+			if actual := schemaAttributeKeyName(key); actual != tc.expected {
+				t.Errorf("schemaAttributeKeyName(names.%s): expected %s, got %s", tc.constant, tc.expected, actual)
+			}
+
+			tags := parseTagsAnnotation("@Tags(identifierAttribute=names." + tc.constant + ")")
+			if tags == nil || tags.IdentifierAttribute != tc.expected {
+				t.Errorf("Expected identifier attribute %s, got %+v", tc.expected, tags)
+			}
+		})
+	}
+
+	// Quoted attribute names are kept as written
+	if tags := parseTagsAnnotation(`@Tags(identifierAttribute="bucket")`); tags == nil || tags.IdentifierAttribute != "bucket" {
+		t.Errorf("Expected identifier attribute bucket, got %+v", tags)
+	}
+}
+
+// TestTagsResourceTypeConversion tests that @Tags resource types given as names constants are resolved
+func TestTagsResourceTypeConversion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "Literal", text: `@Tags(identifierAttribute="arn", resourceType="WidgetPolicy")`, expected: "WidgetPolicy"},
+		{name: "Attr constant", text: `@Tags(identifierAttribute=names.AttrARN, resourceType=names.AttrVPC)`, expected: "VPC"},
+		{name: "Other constant", text: `@Tags(resourceType=names.WidgetResourceType)`, expected: "names.WidgetResourceType"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tags := parseTagsAnnotation(tc.text)
+			if tags == nil || tags.ResourceType != tc.expected {
+				t.Errorf("Expected resource type %s, got %+v", tc.expected, tags)
+			}
+		})
+	}
+}
+
+// TestFrameworkImportStateDetection tests detection of import support on framework resources
+// through an explicit ImportState method or an embedded framework import helper
+func TestFrameworkImportStateDetection(t *testing.T) {
+	guardrailContent, err := testHarnessFS.ReadFile("testharness/framework_resource_aws_bedrock_guardrail.gocode")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+
+	testCases := []struct {
+		name                    string
+		source                  string
+		structType              string
+		expectedImportMethod    string
+		expectedImportIndex     string
+		expectedImportSupported bool
+	}{
+		{
+			name:                    "Explicit ImportState method",
+			source:                  string(guardrailContent),
+			structType:              "guardrailResource",
+			expectedImportMethod:    "ImportState",
+			expectedImportIndex:     "method.guardrailResource.ImportState.goindex",
+			expectedImportSupported: true,
+		},
+		{
+			name: "Embedded WithImportByID helper",
+			source: `package example
+
+// @FrameworkResource("aws_example_widget", name="Widget")
+func newWidgetResource(_ context.Context) (resource.ResourceWithConfigure, error) {
+	return &widgetResource{}, nil
+}
+
+type widgetResource struct {
+	framework.ResourceWithModel[widgetResourceModel]
+	framework.WithImportByID
+}
+
+func (r *widgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+`,
+			structType:              "widgetResource",
+			expectedImportMethod:    "framework.WithImportByID",
+			expectedImportSupported: true,
+		},
+		{
+			name: "Embedded WithImportByIdentity helper",
+			source: `package example
+
+type widgetResource struct {
+	framework.ResourceWithModel[widgetResourceModel]
+	framework.WithImportByIdentity
+}
+
+func (r *widgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+`,
+			structType:              "widgetResource",
+			expectedImportMethod:    "framework.WithImportByIdentity",
+			expectedImportSupported: true,
+		},
+		{
+			name: "No import support",
+			source: `package example
+
+type widgetResource struct {
+	framework.ResourceWithModel[widgetResourceModel]
+	framework.WithTimeouts
+}
+
+func (r *widgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+`,
+			structType: "widgetResource",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			astFile, err := parser.ParseFile(fset, "test.go", tc.source, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse source: %v", err)
+			}
+
+			methods := extractFrameworkMethodsFromFile(astFile, tc.structType)
+			if methods == nil {
+				t.Fatalf("Expected framework methods, got nil")
+			}
+			if methods.ImportStateMethod != tc.expectedImportMethod {
+				t.Errorf("Expected import state method %q, got %q", tc.expectedImportMethod, methods.ImportStateMethod)
+			}
+			if methods.SchemaMethod != "Schema" {
+				t.Errorf("Expected Schema method to be detected, got %q", methods.SchemaMethod)
+			}
+
+			serviceReg := CreateTestServiceRegistration("example")
+			serviceReg.FrameworkResourceMethods["aws_example_widget"] = methods
+			awsResource := AWSResource{
+				TerraformType: "aws_example_widget",
+				SDKType:       "framework",
+				StructType:    tc.structType,
+			}
+
+			terraformResource := NewTerraformResourceFromAWSFramework(awsResource, serviceReg, nil)
+			if terraformResource.ImportSupported != tc.expectedImportSupported {
+				t.Errorf("Expected import supported %v, got %v", tc.expectedImportSupported, terraformResource.ImportSupported)
+			}
+			if terraformResource.ImportIndex != tc.expectedImportIndex {
+				t.Errorf("Expected import index %q, got %q", tc.expectedImportIndex, terraformResource.ImportIndex)
+			}
+		})
+	}
+}
+
+// TestSDKResourceNoopReadDetection tests that a schema.NoopContext read is reported as an intentional noop
+func TestSDKResourceNoopReadDetection(t *testing.T) {
+	content, err := testHarnessFS.ReadFile("testharness/sdk_resource_aws_lambda_invocation.gocode")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	astFile, err := parser.ParseFile(fset, "sdk_resource_aws_lambda_invocation.gocode", string(content), parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Failed to parse source: %v", err)
+	}
+
+	results, err := scanFileForAnnotations(&gophon.FileInfo{File: astFile, FilePath: "sdk_resource_aws_lambda_invocation.gocode"})
+	if err != nil {
+		t.Fatalf("Failed to scan annotations: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 annotation, got %d", len(results))
+	}
+
+	result := results[0]
+	if _, exists := result.CRUDMethods["read"]; exists {
+		t.Errorf("Noop read should not be recorded as a CRUD method, got %s", result.CRUDMethods["read"])
+	}
+	if len(result.NoopMethods) != 1 || result.NoopMethods[0] != "read" {
+		t.Errorf("Expected noop methods [read], got %v", result.NoopMethods)
+	}
+
+	// The emitted resource has no read index but flags the read as an intentional noop
+	serviceReg := CreateTestServiceRegistration("lambda")
+	results2 := NewAnnotationResults()
+	results2.Add(result)
+	convertAnnotationResultsToServiceRegistration(results2, &serviceReg)
+
+	crudMethods := serviceReg.ResourceCRUDMethods["aws_lambda_invocation"]
+	if crudMethods == nil || !crudMethods.NoopRead() {
+		t.Fatalf("Expected CRUD methods with a noop read, got %+v", crudMethods)
+	}
+
+	terraformResource := NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_lambda_invocation"], serviceReg)
+	if terraformResource.ReadIndex != "" {
+		t.Errorf("Expected no read index, got %s", terraformResource.ReadIndex)
+	}
+	if len(terraformResource.NoopMethods) != 1 || terraformResource.NoopMethods[0] != "read" {
+		t.Errorf("Expected emitted noop methods [read], got %v", terraformResource.NoopMethods)
+	}
+	if terraformResource.CreateIndex != "func.resourceInvocationCreate.goindex" {
+		t.Errorf("Expected create index to be preserved, got %s", terraformResource.CreateIndex)
+	}
+
+	// A resource without noops reports none
+	if noops := extractSDKResourceNoopMethodsFromFile(parseSourceForTest(t, `package test
+
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+`), nil); len(noops) != 0 {
+		t.Errorf("Expected no noop methods, got %v", noops)
+	}
+
+	// The noops of another resource in the same file aren't reported
+	astFile = parseSourceForTest(t, `package test
+
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+
+func resourceGadget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout:   schema.NoopContext,
+		DeleteWithoutTimeout: schema.NoopContext,
+	}
+}
+`)
+	if noops := extractSDKResourceNoopMethodsFromFile(astFile, findFileFuncDecl(astFile, "resourceWidget")); len(noops) != 0 {
+		t.Errorf("Expected no noop methods for resourceWidget, got %v", noops)
+	}
+	if noops := extractSDKResourceNoopMethodsFromFile(astFile, findFileFuncDecl(astFile, "resourceGadget")); !reflect.DeepEqual(noops, []string{"read", "delete"}) {
+		t.Errorf("Expected noop methods [read delete] for resourceGadget, got %v", noops)
+	}
+}
+
+// Helper function to parse Go source code for tests
+func parseSourceForTest(t *testing.T, source string) *ast.File {
+	fset := token.NewFileSet()
+	astFile, err := parser.ParseFile(fset, "test.go", source, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Failed to parse source: %v", err)
+	}
+	return astFile
+}
+
+// TestSDKResourceDocumentationExtraction tests extraction of Description and DeprecationMessage from SDK resources
+func TestSDKResourceDocumentationExtraction(t *testing.T) {
+	testCases := []struct {
+		name                string
+		source              string
+		expectedDescription string
+		expectedDeprecated  bool
+		expectedMessage     string
+	}{
+		{
+			name: "Deprecated resource with constant message",
+			source: `package example
+
+const widgetDeprecation = "use aws_widget_v2 " + "instead"
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Description:          "Manages a widget.",
+		DeprecationMessage:   widgetDeprecation,
+		ReadWithoutTimeout:   resourceWidgetRead,
+	}
+}
+`,
+			expectedDescription: "Manages a widget.",
+			expectedDeprecated:  true,
+			expectedMessage:     "use aws_widget_v2 instead",
+		},
+		{
+			name: "Non-deprecated resource with description",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Description:        "Manages a widget.",
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+`,
+			expectedDescription: "Manages a widget.",
+		},
+		{
+			name: "Deprecated resource referencing another package",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		DeprecationMessage: names.WidgetDeprecated,
+	}
+}
+`,
+			expectedDeprecated: true,
+			expectedMessage:    "names.WidgetDeprecated",
+		},
+		{
+			name: "Resource without documentation",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+`,
+		},
+		{
+			name: "Documentation of another resource in the same file",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Description:        "Manages a widget.",
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+
+// @SDKResource("aws_gadget", name="Gadget")
+func resourceGadget() *schema.Resource {
+	return &schema.Resource{
+		Description:        "Manages a gadget.",
+		DeprecationMessage: "use aws_gadget_v2 instead",
+		ReadWithoutTimeout: resourceGadgetRead,
+	}
+}
+`,
+			expectedDescription: "Manages a widget.",
+		},
+		{
+			name: "Description of a helper schema",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"tag": tagSchema(),
+		},
+	}
+}
+
+func tagSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "A tag.",
+	}
+}
+`,
+		},
+		{
+			name: "Description assigned from a local variable",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	description := describeWidget()
+	return &schema.Resource{
+		Description: description,
+	}
+}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			astFile := parseSourceForTest(t, tc.source)
+
+			results, err := scanFileForAnnotations(&gophon.FileInfo{File: astFile, FilePath: "widget.go"})
+			if err != nil {
+				t.Fatalf("Failed to scan annotations: %v", err)
+			}
+
+			annotationResults := NewAnnotationResults()
+			for _, result := range results {
+				annotationResults.Add(result)
+			}
+			serviceReg := CreateTestServiceRegistration("example")
+			convertAnnotationResultsToServiceRegistration(annotationResults, &serviceReg)
+
+			terraformResource := NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_widget"], serviceReg)
+			if terraformResource.Description != tc.expectedDescription {
+				t.Errorf("Expected description %q, got %q", tc.expectedDescription, terraformResource.Description)
+			}
+			if terraformResource.Deprecated != tc.expectedDeprecated {
+				t.Errorf("Expected deprecated %v, got %v", tc.expectedDeprecated, terraformResource.Deprecated)
+			}
+			if terraformResource.DeprecationMessage != tc.expectedMessage {
+				t.Errorf("Expected deprecation message %q, got %q", tc.expectedMessage, terraformResource.DeprecationMessage)
+			}
+		})
+	}
+}
+
+// TestIdentityAnnotationParsing tests that identity annotations are attached to the owning resource
+func TestIdentityAnnotationParsing(t *testing.T) {
+	source := `package example
+
+// @SDKResource("aws_widget_attachment", name="Widget Attachment")
+// @IdentityAttribute("widget_id")
+// @IdentityAttribute("region", optional="true")
+func resourceWidgetAttachment() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @FrameworkResource("aws_widget_policy", name="Widget Policy")
+// @ArnIdentity
+func newWidgetPolicyResource(context.Context) (resource.ResourceWithConfigure, error) {
+	return &widgetPolicyResource{}, nil
+}
+
+// @SDKResource("aws_widget_settings", name="Widget Settings")
+// @SingletonIdentity
+func resourceWidgetSettings() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{}
+}
+`
+	annotations := findAnnotationsInFile(parseSourceForTest(t, source))
+	if len(annotations) != 4 {
+		t.Fatalf("Expected 4 annotations, got %d", len(annotations))
+	}
+
+	attachment := annotations[0].Identity
+	if attachment == nil {
+		t.Fatalf("Expected identity on aws_widget_attachment")
+	}
+	expectedAttributes := []AWSIdentityAttribute{
+		{Name: "widget_id"},
+		{Name: "region", Optional: true},
+	}
+	if !reflect.DeepEqual(attachment.Attributes, expectedAttributes) {
+		t.Errorf("Expected identity attributes %+v, got %+v", expectedAttributes, attachment.Attributes)
+	}
+	if attachment.ARNAttribute != "" || attachment.Singleton {
+		t.Errorf("Expected only identity attributes, got %+v", attachment)
+	}
+
+	if policy := annotations[1].Identity; policy == nil || policy.ARNAttribute != "arn" {
+		t.Errorf("Expected ARN identity with default attribute 'arn', got %+v", policy)
+	}
+
+	if settings := annotations[2].Identity; settings == nil || !settings.Singleton {
+		t.Errorf("Expected singleton identity, got %+v", settings)
+	}
+
+	if annotations[3].Identity != nil {
+		t.Errorf("Expected no identity on aws_widget, got %+v", annotations[3].Identity)
+	}
+}
+
+// TestTagsConfigExtraction tests detection of transparent and manual tagging
+func TestTagsConfigExtraction(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected *AWSTagsConfig
+	}{
+		{
+			name: "SDK resource with transparent tagging",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+// @Tags(identifierAttribute="arn")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			names.AttrARN:     {Type: schema.TypeString, Computed: true},
+			names.AttrTags:    tftags.TagsSchema(),
+			names.AttrTagsAll: tftags.TagsSchemaComputed(),
+		},
+	}
+}
+`,
+			expected: &AWSTagsConfig{HasTags: true, IdentifierAttribute: "arn", TransparentTagging: true, TagsOnCreate: true, HasTagsAll: true},
+		},
+		{
+			name: "SDK resource with manual tagging",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"tags": tagsSchema(),
+		},
+	}
+}
+`,
+			expected: &AWSTagsConfig{HasTags: true, TagsOnCreate: true},
+		},
+		{
+			name: "SDK resource with settable tags schema literal",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"tags": {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		},
+	}
+}
+`,
+			expected: &AWSTagsConfig{HasTags: true, TagsOnCreate: true},
+		},
+		{
+			name: "SDK resource with computed-only tags",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+// @Tags(identifierAttribute="arn")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			names.AttrTags: tftags.TagsSchemaComputed(),
+		},
+	}
+}
+`,
+			expected: &AWSTagsConfig{HasTags: true, IdentifierAttribute: "arn", TransparentTagging: true},
+		},
+		{
+			name: "Framework resource with computed-only tags attribute",
+			source: `package example
+
+// @FrameworkResource("aws_widget_policy", name="Widget Policy")
+func newWidgetPolicyResource(context.Context) (resource.ResourceWithConfigure, error) {
+	return &widgetPolicyResource{}, nil
+}
+
+type widgetPolicyResource struct {
+	framework.ResourceWithModel[widgetPolicyModel]
+}
+
+func (r *widgetPolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			names.AttrTags: schema.MapAttribute{ElementType: types.StringType, Computed: true},
+		},
+	}
+}
+`,
+			expected: &AWSTagsConfig{HasTags: true},
+		},
+		{
+			name: "Framework resource with tags and tags_all attributes",
+			source: `package example
+
+// @FrameworkResource("aws_widget_policy", name="Widget Policy")
+// @Tags(identifierAttribute=names.AttrARN)
+func newWidgetPolicyResource(context.Context) (resource.ResourceWithConfigure, error) {
+	return &widgetPolicyResource{}, nil
+}
+
+type widgetPolicyResource struct {
+	framework.ResourceWithModel[widgetPolicyModel]
+}
+
+func (r *widgetPolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			names.AttrARN:     framework.ARNAttributeComputedOnly(),
+			names.AttrTags:    tftags.TagsAttribute(),
+			names.AttrTagsAll: tftags.TagsAttributeComputedOnly(),
+		},
+	}
+}
+`,
+			expected: &AWSTagsConfig{HasTags: true, IdentifierAttribute: "arn", TransparentTagging: true, TagsOnCreate: true, HasTagsAll: true},
+		},
+		{
+			name: "Framework resource with transparent tagging embed",
+			source: `package example
+
+// @FrameworkResource("aws_widget_policy", name="Widget Policy")
+// @Tags(identifierAttribute="arn", resourceType="WidgetPolicy")
+func newWidgetPolicyResource(context.Context) (resource.ResourceWithConfigure, error) {
+	return &widgetPolicyResource{}, nil
+}
+
+type widgetPolicyResource struct {
+	framework.ResourceWithModel[widgetPolicyModel]
+	framework.WithTransparentTagging
+}
+
+func (r *widgetPolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+`,
+			expected: &AWSTagsConfig{HasTags: true, IdentifierAttribute: "arn", ResourceType: "WidgetPolicy", TransparentTagging: true},
+		},
+		{
+			name: "Untagged resource",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			names.AttrName: {Type: schema.TypeString, Required: true},
+		},
+	}
+}
+`,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fileInfo := &gophon.FileInfo{File: parseSourceForTest(t, tc.source), FileName: "widget.go"}
+			results, err := scanFileForAnnotations(fileInfo)
+			if err != nil {
+				t.Fatalf("Failed to scan file: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("Expected 1 annotation result, got %d", len(results))
+			}
+
+			if !reflect.DeepEqual(results[0].Tags, tc.expected) {
+				t.Errorf("Expected tags config %+v, got %+v", tc.expected, results[0].Tags)
+			}
+		})
+	}
+}
+
+// TestSDKResourceWrappedFactoryCRUDExtraction tests CRUD extraction from factories returning through a wrapper
+func TestSDKResourceWrappedFactoryCRUDExtraction(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+	}{
+		{
+			name: "Single-level wrapper call",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return tfresource.NewResource(&schema.Resource{
+		CreateWithoutTimeout: resourceWidgetCreate,
+		ReadWithoutTimeout:   resourceWidgetRead,
+		DeleteWithoutTimeout: resourceWidgetDelete,
+	})
+}
+`,
+		},
+		{
+			name: "Wrapper applied to an assigned variable",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	r := &schema.Resource{
+		CreateWithoutTimeout: resourceWidgetCreate,
+		ReadWithoutTimeout:   resourceWidgetRead,
+		DeleteWithoutTimeout: resourceWidgetDelete,
+	}
+
+	return withRegionOverride(r)
+}
+`,
+		},
+		{
+			name: "Assigned variable returned directly",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	var r = &schema.Resource{
+		CreateWithoutTimeout: resourceWidgetCreate,
+		ReadWithoutTimeout:   resourceWidgetRead,
+		DeleteWithoutTimeout: resourceWidgetDelete,
+	}
+
+	return r
+}
+`,
+		},
+		{
+			name: "Fields assigned after the literal",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	r := &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+	r.CreateWithoutTimeout = resourceWidgetCreate
+	if deletable {
+		r.DeleteWithoutTimeout = resourceWidgetDelete
+	}
+
+	return r
+}
+`,
+		},
+		{
+			name: "Field assignment overriding the literal",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	r := &schema.Resource{
+		CreateWithoutTimeout: schema.NoopContext,
+		ReadWithoutTimeout:   resourceWidgetRead,
+	}
+	r.CreateWithoutTimeout = resourceWidgetCreate
+	r.DeleteWithoutTimeout = resourceWidgetDelete
+
+	return r
+}
+`,
+		},
+	}
+
+	expected := map[string]string{
+		"create": "resourceWidgetCreate",
+		"read":   "resourceWidgetRead",
+		"delete": "resourceWidgetDelete",
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			methods := extractSDKResourceCRUDFromFile(parseSourceForTest(t, tc.source))
+			if !reflect.DeepEqual(methods, expected) {
+				t.Errorf("Expected CRUD methods %v, got %v", expected, methods)
+			}
+		})
+	}
+}
+
+// TestWrapperCallIgnoresNonSchemaArguments tests that unrelated calls returning other composite literals are not unwrapped
+func TestWrapperCallIgnoresNonSchemaArguments(t *testing.T) {
+	source := `package example
+
+func findWidget(ctx context.Context, conn *widget.Client) (*widget.Widget, error) {
+	return conn.DescribeWidget(ctx, &widget.DescribeWidgetInput{
+		Description: aws.String("not a resource"),
+	})
+}
+`
+	description, _ := extractSDKResourceDocumentationFromFile(parseSourceForTest(t, source), nil)
+	if description != "" {
+		t.Errorf("Expected no description, got %q", description)
+	}
+}
+
+// TestSDKResourceSchemaVersionExtraction tests extraction of SchemaVersion and StateUpgraders from SDK resources
+func TestSDKResourceSchemaVersionExtraction(t *testing.T) {
+	testCases := []struct {
+		name              string
+		source            string
+		expectedVersion   int
+		expectedUpgraders []string
+	}{
+		{
+			name: "Literal schema version with one upgrader",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceWidgetV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceWidgetStateUpgradeV1,
+				Version: 1,
+			},
+		},
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+`,
+			expectedVersion:   2,
+			expectedUpgraders: []string{"resourceWidgetStateUpgradeV1"},
+		},
+		{
+			name: "Named constant schema version",
+			source: `package example
+
+const widgetSchemaVersion = 2
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: widgetSchemaVersion,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceWidgetV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: widget.UpgradeV1,
+				Version: 1,
+			},
+		},
+	}
+}
+`,
+			expectedVersion:   2,
+			expectedUpgraders: []string{"widget.UpgradeV1"},
+		},
+		{
+			name: "No schema version",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+`,
+		},
+		{
+			name: "Schema version of another resource in the same file",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+	}
+}
+
+// @SDKResource("aws_gadget", name="Gadget")
+func resourceGadget() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 3,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Upgrade: resourceGadgetStateUpgradeV2,
+				Version: 2,
+			},
+		},
+	}
+}
+`,
+			expectedVersion: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			astFile := parseSourceForTest(t, tc.source)
+			version, upgraders := extractSDKResourceSchemaVersionFromFile(astFile, findFileFuncDecl(astFile, "resourceWidget"))
+			if version != tc.expectedVersion {
+				t.Errorf("Expected SchemaVersion %d, got %d", tc.expectedVersion, version)
+			}
+			if !reflect.DeepEqual(upgraders, tc.expectedUpgraders) {
+				t.Errorf("Expected StateUpgraders %v, got %v", tc.expectedUpgraders, upgraders)
+			}
+		})
+	}
+}
+
+// TestSDKResourceImporterExtraction tests passthrough and custom Importer StateContext functions
+func TestSDKResourceImporterExtraction(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected *AWSImportConfig
+	}{
+		{
+			name: "Passthrough importer",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+`,
+			expected: &AWSImportConfig{Passthrough: true},
+		},
+		{
+			name: "Custom importer",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceWidgetImport,
+		},
+	}
+}
+`,
+			expected: &AWSImportConfig{ImportStateFunc: "resourceWidgetImport"},
+		},
+		{
+			name: "No importer",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+`,
+		},
+		{
+			name: "Importer of another resource in the same file",
+			source: `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+
+// @SDKResource("aws_gadget", name="Gadget")
+func resourceGadget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceGadgetRead,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGadgetImport,
+		},
+	}
+}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			astFile := parseSourceForTest(t, tc.source)
+			importConfig := extractSDKResourceImporterFromFile(astFile, findFileFuncDecl(astFile, "resourceWidget"))
+			if !reflect.DeepEqual(importConfig, tc.expected) {
+				t.Errorf("Expected Importer %+v, got %+v", tc.expected, importConfig)
+			}
+		})
+	}
+}
+
+// TestFrameworkPlanMethodDetection tests detection of ModifyPlan, ValidateConfig and ConfigValidators on framework structs
+func TestFrameworkPlanMethodDetection(t *testing.T) {
+	testCases := []struct {
+		name                          string
+		source                        string
+		expectedModifyPlanIndex       string
+		expectedValidateConfigIndex   string
+		expectedConfigValidatorsIndex string
+	}{
+		{
+			name: "Struct implementing ModifyPlan and ConfigValidators",
+			source: `package example
+
+type widgetResource struct {
+	framework.ResourceWithModel[widgetResourceModel]
+}
+
+func (r *widgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+
+func (r *widgetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {}
+
+func (r *widgetResource) ConfigValidators(context.Context) []resource.ConfigValidator {
+	return nil
+}
+
+func (r *otherResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {}
+`,
+			expectedModifyPlanIndex:       "method.widgetResource.ModifyPlan.goindex",
+			expectedConfigValidatorsIndex: "method.widgetResource.ConfigValidators.goindex",
+		},
+		{
+			name: "Struct without plan methods",
+			source: `package example
+
+type widgetResource struct {
+	framework.ResourceWithModel[widgetResourceModel]
+}
+
+func (r *widgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+
+func (r *widgetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			methods := extractFrameworkMethodsFromFile(parseSourceForTest(t, tc.source), "widgetResource")
+			if methods == nil {
+				t.Fatalf("Expected framework methods, got nil")
+			}
+
+			serviceReg := CreateTestServiceRegistration("example")
+			serviceReg.FrameworkResourceMethods["aws_example_widget"] = methods
+			awsResource := AWSResource{
+				TerraformType: "aws_example_widget",
+				SDKType:       "framework",
+				StructType:    "widgetResource",
+			}
+
+			terraformResource := NewTerraformResourceFromAWSFramework(awsResource, serviceReg, nil)
+			if terraformResource.ModifyPlanIndex != tc.expectedModifyPlanIndex {
+				t.Errorf("Expected ModifyPlan index %q, got %q", tc.expectedModifyPlanIndex, terraformResource.ModifyPlanIndex)
+			}
+			if terraformResource.ValidateConfigIndex != tc.expectedValidateConfigIndex {
+				t.Errorf("Expected ValidateConfig index %q, got %q", tc.expectedValidateConfigIndex, terraformResource.ValidateConfigIndex)
+			}
+			if terraformResource.ConfigValidatorsIndex != tc.expectedConfigValidatorsIndex {
+				t.Errorf("Expected ConfigValidators index %q, got %q", tc.expectedConfigValidatorsIndex, terraformResource.ConfigValidatorsIndex)
+			}
+		})
+	}
+}
+
+// TestFrameworkEmbeddedMethodDetection tests that core methods provided by embedded framework helpers are recorded
+func TestFrameworkEmbeddedMethodDetection(t *testing.T) {
+	source := `package example
+
+type widgetResource struct {
+	framework.ResourceWithModel[widgetResourceModel]
+	framework.WithNoOpUpdate[widgetResourceModel]
+	framework.WithNoOpDelete
+	framework.WithImportByID
+}
+
+func (r *widgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+
+func (r *widgetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {}
+
+func (r *widgetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {}
+`
+	methods := extractFrameworkMethodsFromFile(parseSourceForTest(t, source), "widgetResource")
+	if methods == nil {
+		t.Fatalf("Expected framework methods, got nil")
+	}
+
+	// Declared methods are reported directly
+	if methods.CreateMethod != "Create" || methods.ReadMethod != "Read" {
+		t.Errorf("Expected declared Create and Read, got %q and %q", methods.CreateMethod, methods.ReadMethod)
+	}
+
+	// Missing methods are provided by embedding rather than absent
+	if methods.UpdateMethod != "" || methods.DeleteMethod != "" {
+		t.Errorf("Expected Update and Delete not to be declared, got %q and %q", methods.UpdateMethod, methods.DeleteMethod)
+	}
+	expected := map[string]string{
+		"Update":      "framework.WithNoOpUpdate",
+		"Delete":      "framework.WithNoOpDelete",
+		"ImportState": "framework.WithImportByID",
+	}
+	if !reflect.DeepEqual(methods.EmbeddedMethods, expected) {
+		t.Errorf("Expected embedded methods %v, got %v", expected, methods.EmbeddedMethods)
+	}
+}
+
+// TestFrameworkEmbeddedMethodDetection_DeclaredMethodsWin tests that declared methods are not reported as embedded
+func TestFrameworkEmbeddedMethodDetection_DeclaredMethodsWin(t *testing.T) {
+	source := `package example
+
+type widgetResource struct {
+	framework.ResourceWithModel[widgetResourceModel]
+	framework.WithNoOpUpdate[widgetResourceModel]
+}
+
+func (r *widgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+
+func (r *widgetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {}
+`
+	methods := extractFrameworkMethodsFromFile(parseSourceForTest(t, source), "widgetResource")
+	if methods == nil {
+		t.Fatalf("Expected framework methods, got nil")
+	}
+	if methods.UpdateMethod != "Update" {
+		t.Errorf("Expected declared Update, got %q", methods.UpdateMethod)
+	}
+	if len(methods.EmbeddedMethods) != 0 {
+		t.Errorf("Expected no embedded methods, got %v", methods.EmbeddedMethods)
+	}
+}
+
+// TestFrameworkImplementedMethods tests that inferred framework methods are split by what the struct provides
+func TestFrameworkImplementedMethods(t *testing.T) {
+	testCases := []struct {
+		name                string
+		embeds              string
+		expectedImplemented []string
+		expectedMissing     []string
+	}{
+		{
+			name:                "Resource without Update",
+			expectedImplemented: []string{"Create", "Read", "Delete", "Metadata", "Schema"},
+			expectedMissing:     []string{"Update"},
+		},
+		{
+			name:                "Update provided by embedded helper",
+			embeds:              "\tframework.WithNoOpUpdate[widgetResourceModel]\n",
+			expectedImplemented: []string{"Create", "Read", "Update", "Delete", "Metadata", "Schema"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package example
+
+// @FrameworkResource("aws_example_widget", name="Widget")
+func newWidgetResource(_ context.Context) (resource.ResourceWithConfigure, error) {
+	return &widgetResource{}, nil
+}
+
+type widgetResource struct {
+	framework.ResourceWithModel[widgetResourceModel]
+` + tc.embeds + `}
+
+func (r *widgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+
+func (r *widgetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {}
+
+func (r *widgetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {}
+
+func (r *widgetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {}
+`
+			results, err := scanFileForAnnotations(&gophon.FileInfo{File: parseSourceForTest(t, source), FilePath: "widget.go"})
+			if err != nil {
+				t.Fatalf("Failed to scan annotations: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("Expected 1 annotation, got %d", len(results))
+			}
+
+			result := results[0]
+			if len(result.FrameworkMethods) != 6 {
+				t.Errorf("Expected 6 inferred framework methods, got %v", result.FrameworkMethods)
+			}
+			if !reflect.DeepEqual(result.ImplementedFrameworkMethods, tc.expectedImplemented) {
+				t.Errorf("Expected implemented methods %v, got %v", tc.expectedImplemented, result.ImplementedFrameworkMethods)
+			}
+			if !reflect.DeepEqual(result.MissingFrameworkMethods, tc.expectedMissing) {
+				t.Errorf("Expected missing methods %v, got %v", tc.expectedMissing, result.MissingFrameworkMethods)
+			}
+		})
+	}
+}
+
+// TestFrameworkValueReceiverDetection tests that value and generic receivers are matched like pointer receivers
+func TestFrameworkValueReceiverDetection(t *testing.T) {
+	testCases := []struct {
+		name           string
+		source         string
+		expectedStruct string
+	}{
+		{
+			name: "Value receiver Schema",
+			source: `package example
+
+type widgetResource struct {
+	framework.ResourceWithModel[widgetResourceModel]
+}
+
+func (w widgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+
+func (w *widgetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {}
+
+func (w widgetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {}
+`,
+			expectedStruct: "widgetResource",
+		},
+		{
+			name: "Generic receiver Schema",
+			source: `package example
+
+type widgetResource[T any] struct {
+	framework.ResourceWithModel[T]
+}
+
+func (w *widgetResource[T]) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+
+func (w *widgetResource[T]) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {}
+
+func (w widgetResource[T]) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {}
+`,
+			expectedStruct: "widgetResource",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file := parseSourceForTest(t, tc.source)
+
+			structType := extractFrameworkStructTypeBySchemaMethod(file)
+			if structType != tc.expectedStruct {
+				t.Fatalf("Expected struct type %s, got %s", tc.expectedStruct, structType)
+			}
+
+			methods := extractFrameworkMethodsFromFile(file, structType)
+			if methods == nil {
+				t.Fatalf("Expected framework methods, got nil")
+			}
+			if methods.SchemaMethod != "Schema" || methods.CreateMethod != "Create" || methods.ReadMethod != "Read" {
+				t.Errorf("Expected Schema, Create and Read, got %q, %q and %q", methods.SchemaMethod, methods.CreateMethod, methods.ReadMethod)
+			}
+			if methods.ReceiverName != "w" {
+				t.Errorf("Expected receiver name w, got %q", methods.ReceiverName)
+			}
+		})
+	}
+}
+
+// TestTypeDeclarationAnnotations tests annotations placed on a struct's type declaration instead of its factory
+func TestTypeDeclarationAnnotations(t *testing.T) {
+	testCases := []struct {
+		name           string
+		source         string
+		expectedType   string
+		expectedStruct string
+	}{
+		{
+			name: "Annotation on type declaration",
+			source: `package example
+
+func newWidgetResource(context.Context) (resource.ResourceWithConfigure, error) {
+	return &widgetResource{}, nil
+}
+
+// @FrameworkResource("aws_widget", name="Widget")
+type widgetResource struct {
+	framework.ResourceWithModel[widgetResourceModel]
+}
+
+func (r *otherResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+`,
+			expectedType:   "aws_widget",
+			expectedStruct: "widgetResource",
+		},
+		{
+			name: "Annotation on grouped type spec",
+			source: `package example
+
+type (
+	// @EphemeralResource("aws_widget_token", name="Widget Token")
+	widgetTokenEphemeralResource struct {
+		framework.EphemeralResourceWithModel[widgetTokenModel]
+	}
+
+	widgetTokenModel struct{}
+)
+`,
+			expectedType:   "aws_widget_token",
+			expectedStruct: "widgetTokenEphemeralResource",
+		},
+		{
+			name: "Annotation on both factory and type is counted once",
+			source: `package example
+
+// @FrameworkResource("aws_widget", name="Widget")
+func newWidgetResource(context.Context) (resource.ResourceWithConfigure, error) {
+	return &widgetResource{}, nil
+}
+
+// @FrameworkResource("aws_widget", name="Widget")
+type widgetResource struct {
+	framework.ResourceWithModel[widgetResourceModel]
+}
+
+func (r *widgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+`,
+			expectedType:   "aws_widget",
+			expectedStruct: "widgetResource",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fileInfo := &gophon.FileInfo{File: parseSourceForTest(t, tc.source), FileName: "widget.go"}
+			results, err := scanFileForAnnotations(fileInfo)
+			if err != nil {
+				t.Fatalf("Failed to scan file: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("Expected 1 annotation result, got %d", len(results))
+			}
+
+			if results[0].TerraformType != tc.expectedType {
+				t.Errorf("Expected terraform type %s, got %s", tc.expectedType, results[0].TerraformType)
+			}
+			if results[0].StructType != tc.expectedStruct {
+				t.Errorf("Expected struct type %s, got %s", tc.expectedStruct, results[0].StructType)
+			}
+		})
+	}
+}
+
+// TestTypeDeclarationAnnotations_IgnoresSDKAnnotations tests that SDK registrations are only read from functions
+func TestTypeDeclarationAnnotations_IgnoresSDKAnnotations(t *testing.T) {
+	source := `package example
+
+// @SDKResource("aws_widget", name="Widget")
+type widgetConfig struct{}
+`
+	if annotations := findAnnotationsInFile(parseSourceForTest(t, source)); len(annotations) != 0 {
+		t.Errorf("Expected no annotations, got %+v", annotations)
+	}
+}
+
+// TestScanPackageForAnnotationsFunc_StopsEarly tests that the streaming scanner stops at the first callback error
+// without scanning later files
+func TestScanPackageForAnnotationsFunc_StopsEarly(t *testing.T) {
+	source := `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+
+// @SDKResource("aws_gadget", name="Gadget")
+func resourceGadget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceGadgetRead,
+	}
+}
+`
+	// The second file has no AST, scanning it logs a warning
+	packageInfo := &gophon.PackageInfo{
+		Files: []*gophon.FileInfo{
+			{File: parseSourceForTest(t, source), FilePath: "widget.go"},
+			{FilePath: "broken.go"},
+		},
+	}
+
+	errStop := errors.New("stop")
+	logger := newCapturingLogger()
+	var seen []string
+	err := scanPackageForAnnotationsFunc(packageInfo, ScanOptions{Logger: logger}, func(result AnnotationResult) error {
+		seen = append(seen, result.TerraformType)
+		return errStop
+	})
+
+	if !errors.Is(err, errStop) {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+	if !reflect.DeepEqual(seen, []string{"aws_widget"}) {
+		t.Errorf("Expected only aws_widget to be streamed, got %v", seen)
+	}
+	if len(logger.messages["warn"]) != 0 {
+		t.Errorf("Expected broken.go not to be scanned, got warnings %v", logger.messages["warn"])
+	}
+
+	// Without stopping every result is streamed and the broken file is reached
+	seen = nil
+	err = scanPackageForAnnotationsFunc(packageInfo, ScanOptions{Logger: logger}, func(result AnnotationResult) error {
+		seen = append(seen, result.TerraformType)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(seen, []string{"aws_widget", "aws_gadget"}) {
+		t.Errorf("Expected both resources to be streamed in order, got %v", seen)
+	}
+	if len(logger.messages["warn"]) != 1 {
+		t.Errorf("Expected one warning for broken.go, got %v", logger.messages["warn"])
+	}
+}
+
+// TestScanPackageForAnnotationsFunc_Categories tests that the streaming and the eager scan both only report the
+// categories the options include
+func TestScanPackageForAnnotationsFunc_Categories(t *testing.T) {
+	packageInfo := &gophon.PackageInfo{
+		Files: []*gophon.FileInfo{{File: parseSourceForTest(t, `package example
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+
+// @SDKDataSource("aws_widget", name="Widget")
+func dataSourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceWidgetRead,
+	}
+}
+`), FilePath: "widget.go"}},
+	}
+	options := ScanOptions{Categories: []string{WalkCategoryDataSource}}
+
+	var streamed []AnnotationType
+	err := scanPackageForAnnotationsFunc(packageInfo, options, func(result AnnotationResult) error {
+		streamed = append(streamed, result.Type)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(streamed, []AnnotationType{AnnotationSDKDataSource}) {
+		t.Errorf("Expected only the data source to be streamed, got %v", streamed)
+	}
+
+	results, err := scanPackageForAnnotations(packageInfo, options)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results.SDKResources) != 0 || len(results.SDKDataSources) != 1 {
+		t.Errorf("Expected only the data source to be collected, got %+v", results.GetAll())
+	}
+}
+
+// TestScanPackageForAnnotations_ManyFiles tests that large packages scanned in parallel yield complete results
+// in package order, the same as scanning the files one at a time
+func TestScanPackageForAnnotations_ManyFiles(t *testing.T) {
+	packageInfo := &gophon.PackageInfo{}
+	var mapEntries string
+	for i := 0; i < 3*parallelScanMinFiles; i++ {
+		source := fmt.Sprintf(`package example
+
+// @SDKResource("aws_widget_%[1]d", name="Widget %[1]d")
+func resourceWidget%[1]d() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidget%[1]dRead,
+	}
+}
+
+// @FrameworkDataSource("aws_gadget_%[1]d", name="Gadget %[1]d")
+func newGadget%[1]dDataSource(context.Context) (datasource.DataSourceWithConfigure, error) {
+	return &gadget%[1]dDataSource{}, nil
+}
+
+func dataSourceLegacy%[1]d() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceLegacy%[1]dRead,
+	}
+}
+`, i)
+		packageInfo.Files = append(packageInfo.Files, &gophon.FileInfo{File: parseSourceForTest(t, source), FilePath: fmt.Sprintf("widget_%d.go", i)})
+		mapEntries += fmt.Sprintf("\t\t\"aws_legacy_%[1]d\": dataSourceLegacy%[1]d(),\n", i)
+	}
+	registrations := "package example\n\nfunc SupportedDataSources() map[string]*schema.Resource {\n\treturn map[string]*schema.Resource{\n" + mapEntries + "\t}\n}\n"
+	packageInfo.Files = append(packageInfo.Files, &gophon.FileInfo{File: parseSourceForTest(t, registrations), FilePath: "service_package.go"})
+
+	// Within each category the eager scan keeps the order of the sequential streaming scan
+	expected := make(map[AnnotationType][]string)
+	total := 0
+	err := scanPackageForAnnotationsFunc(packageInfo, ScanOptions{}, func(result AnnotationResult) error {
+		expected[result.Type] = append(expected[result.Type], result.TerraformType)
+		total++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if total != 3*3*parallelScanMinFiles {
+		t.Fatalf("Expected %d results from the sequential scan, got %d", 3*3*parallelScanMinFiles, total)
+	}
+
+	// Repeated scans must agree with each other and with the sequential scan
+	for run := 0; run < 5; run++ {
+		results, err := scanPackageForAnnotations(packageInfo, ScanOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if results.TotalAnnotations != total {
+			t.Errorf("Expected %d annotations, got %d", total, results.TotalAnnotations)
+		}
+
+		actual := make(map[AnnotationType][]string)
+		for _, result := range results.GetAll() {
+			actual[result.Type] = append(actual[result.Type], result.TerraformType)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Run %d: expected results %v, got %v", run, expected, actual)
+		}
+	}
+}
+
+func TestTestingAnnotationExtraction(t *testing.T) {
+	source := `package example
+
+// @SDKResource("aws_widget", name="Widget")
+// @Testing(existsType="github.com/aws/aws-sdk-go-v2/service/example/types;awstypes.Widget", importIgnore="password;user")
+// @Testing(tagsTest=false, serialize=true)
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @SDKResource("aws_gadget", name="Gadget")
+func resourceGadget() *schema.Resource {
+	return &schema.Resource{}
+}
+`
+	fileInfo := &gophon.FileInfo{File: parseSourceForTest(t, source), FilePath: "widget.go"}
+	results, err := scanFileForRegistrations(fileInfo, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"existsType":   "github.com/aws/aws-sdk-go-v2/service/example/types;awstypes.Widget",
+		"importIgnore": "password;user",
+		"tagsTest":     "false",
+		"serialize":    "true",
+	}
+	found := 0
+	for _, result := range results {
+		switch result.TerraformType {
+		case "aws_widget":
+			found++
+			if !reflect.DeepEqual(result.TestingConfig, expected) {
+				t.Errorf("Expected testing config %v, got %v", expected, result.TestingConfig)
+			}
+			awsResource := AWSResource{TerraformType: result.TerraformType, FactoryFunction: result.FunctionName, TestingConfig: result.TestingConfig}
+			entry := NewTerraformResourceFromAWSSDK(awsResource, ServiceRegistration{})
+			if !reflect.DeepEqual(entry.TestingConfig, expected) {
+				t.Errorf("Expected entry testing config %v, got %v", expected, entry.TestingConfig)
+			}
+		case "aws_gadget":
+			found++
+			if result.TestingConfig != nil {
+				t.Errorf("Expected no testing config for aws_gadget, got %v", result.TestingConfig)
+			}
+		}
+	}
+	if found != 2 {
+		t.Fatalf("Expected 2 annotated resources, found %d", found)
+	}
+}
@@ -13,26 +13,73 @@ const (
 
 // AnnotationResult represents a parsed annotation with its context and extracted info
 type AnnotationResult struct {
-	Type           AnnotationType `json:"type"`            // The annotation type
-	TerraformType  string         `json:"terraform_type"`  // e.g., "aws_key_pair"
-	Name           string         `json:"name"`            // e.g., "Key Pair"
-	FilePath       string         `json:"file_path"`       // Source file path
-	RawAnnotation  string         `json:"raw_annotation"`  // The raw annotation text for debugging
-	
+	Type          AnnotationType `json:"type"`           // The annotation type
+	TerraformType string         `json:"terraform_type"` // e.g., "aws_key_pair"
+	Name          string         `json:"name"`           // e.g., "Key Pair"
+	FilePath      string         `json:"file_path"`      // Source file path
+	RawAnnotation string         `json:"raw_annotation"` // The raw annotation text for debugging
+	FunctionName  string         `json:"function_name"`  // Annotated function, e.g., "resourceKeyPair"
+
+	// First declared result of the annotated function, e.g. "*schema.Resource". Empty for annotations on a type
+	FactoryReturnType string `json:"factory_return_type,omitempty"`
+
+	// SHA-256 of the annotated function's code, see funcSourceHash. Empty for annotations on a type declaration
+	SourceHash string `json:"source_hash,omitempty"`
+
+	// Build tags of the //go:build constraint of FilePath, nil when the file is part of every build
+	BuildTags []string `json:"build_tags,omitempty"`
+
+	// Line of FilePath declaring the annotated function or type, 0 when the package carries no positions
+	SourceLine int `json:"source_line,omitempty"`
+
+	// Map-style registration function listing the factory, e.g. "SupportedResources", empty when annotated
+	RegistrationMethod string `json:"registration_method,omitempty"`
+
+	// Deprecated terraform types registered by further annotations on the same function
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Resource identity declared alongside the registration annotation
+	Identity *AWSIdentityConfig `json:"identity,omitempty"`
+
+	// Region behaviour declared by @Region alongside the registration annotation
+	Region *AWSRegionConfig `json:"region,omitempty"`
+
+	// Tagging declared by @Tags or a tags schema attribute, nil for untagged resources
+	Tags *AWSTagsConfig `json:"tags,omitempty"`
+
+	// Acceptance test generation hints declared by @Testing, e.g. {"existsType": "...", "importIgnore": "..."}
+	TestingConfig map[string]string `json:"testing_config,omitempty"`
+
+	// For SDK resources: Importer of the schema.Resource, nil when absent
+	Import *AWSImportConfig `json:"import,omitempty"`
+
 	// Extracted information from the file
-	StructType     string            `json:"struct_type,omitempty"`     // For framework resources: "guardrailResource"
-	CRUDMethods    map[string]string `json:"crud_methods,omitempty"`    // For SDK resources: "create" -> "resourceFunctionCreate"
-	FrameworkMethods []string        `json:"framework_methods,omitempty"` // For framework: ["Create", "Read", "Update", "Delete"]
+	StructType           string            `json:"struct_type,omitempty"`            // For framework resources: "guardrailResource"
+	CRUDMethods          map[string]string `json:"crud_methods,omitempty"`           // For SDK resources: "create" -> "resourceFunctionCreate"
+	NoopMethods          []string          `json:"noop_methods,omitempty"`           // For SDK resources: CRUD methods set to schema.NoopContext, e.g. ["read"]
+	Description          string            `json:"description,omitempty"`            // For SDK resources: schema.Resource Description
+	DeprecationMessage   string            `json:"deprecation_message,omitempty"`    // For SDK resources: schema.Resource DeprecationMessage
+	SchemaVersion        int               `json:"schema_version,omitempty"`         // For SDK resources: schema.Resource SchemaVersion
+	StateUpgraders       []string          `json:"state_upgraders,omitempty"`        // For SDK resources: Upgrade functions of StateUpgraders
+	FrameworkMethods     []string          `json:"framework_methods,omitempty"`      // For framework: ["Create", "Read", "Update", "Delete"]
+	FrameworkCRUDMethods *AWSCRUDMethods   `json:"framework_crud_methods,omitempty"` // For framework: methods declared on the struct type
+
+	// FrameworkMethods split by what the struct actually provides, either declared or through an embedded
+	// framework helper. Both are empty when the struct type is unknown
+	ImplementedFrameworkMethods []string `json:"implemented_framework_methods,omitempty"` // e.g. ["Create", "Read", "Delete", "Metadata", "Schema"]
+	MissingFrameworkMethods     []string `json:"missing_framework_methods,omitempty"`     // e.g. ["Update"]
 }
 
-// AnnotationResults contains all annotation results found in a package
+// AnnotationResults contains all annotation results found in a package. It is not safe for concurrent use,
+// scanPackageForAnnotations parallelises by scanning each file into its own slice and adding the results
+// from a single goroutine afterwards
 type AnnotationResults struct {
 	SDKResources         []AnnotationResult `json:"sdk_resources"`
 	SDKDataSources       []AnnotationResult `json:"sdk_data_sources"`
 	FrameworkResources   []AnnotationResult `json:"framework_resources"`
 	FrameworkDataSources []AnnotationResult `json:"framework_data_sources"`
 	EphemeralResources   []AnnotationResult `json:"ephemeral_resources"`
-	
+
 	// Summary statistics
 	TotalAnnotations int `json:"total_annotations"`
 }
@@ -48,7 +95,7 @@ func (ar *AnnotationResults) GetAll() []AnnotationResult {
 	return all
 }
 
-// Add adds an annotation result to the appropriate collection
+// Add adds an annotation result to the appropriate collection, it must not be called concurrently
 func (ar *AnnotationResults) Add(result AnnotationResult) {
 	switch result.Type {
 	case AnnotationSDKResource:
@@ -65,6 +112,19 @@ func (ar *AnnotationResults) Add(result AnnotationResult) {
 	ar.TotalAnnotations++
 }
 
+// annotationCategory returns the walk category entries of the annotation type are written to
+func annotationCategory(annotationType AnnotationType) string {
+	switch annotationType {
+	case AnnotationSDKResource, AnnotationFrameworkResource:
+		return WalkCategoryResource
+	case AnnotationSDKDataSource, AnnotationFrameworkDataSource:
+		return WalkCategoryDataSource
+	case AnnotationEphemeralResource:
+		return WalkCategoryEphemeral
+	}
+	return ""
+}
+
 // NewAnnotationResults creates a new empty AnnotationResults
 func NewAnnotationResults() *AnnotationResults {
 	return &AnnotationResults{
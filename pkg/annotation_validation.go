@@ -0,0 +1,154 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"sort"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// validateAnnotationResults flags annotations whose implementation could not be discovered, which usually
+// means the annotation and the code it describes drifted apart in a refactor. Framework and ephemeral
+// annotations need a struct with a Schema method, SDK annotations need at least one CRUD method.
+// Annotations whose factory returns a type of another kind, e.g. an @SDKResource returning a framework
+// resource.ResourceWithConfigure, are flagged as mismatched
+func validateAnnotationResults(results *AnnotationResults, serviceName string) []string {
+	var warnings []string
+
+	for _, annotation := range results.GetAll() {
+		if kinds := factoryReturnTypeKinds(annotation.FactoryReturnType); kinds != nil && !kinds[annotation.Type] {
+			warnings = append(warnings, fmt.Sprintf("mismatched @%s %s in %s: factory %s returns %s",
+				annotation.Type, annotation.TerraformType, annotationLocation(annotation, serviceName),
+				annotation.FunctionName, annotation.FactoryReturnType))
+		}
+
+		var problem string
+		switch annotation.Type {
+		case AnnotationSDKResource, AnnotationSDKDataSource:
+			if len(annotation.CRUDMethods) == 0 && len(annotation.NoopMethods) == 0 {
+				problem = "no CRUD methods found in schema.Resource"
+			}
+		case AnnotationFrameworkResource, AnnotationFrameworkDataSource, AnnotationEphemeralResource:
+			if annotation.StructType == "" {
+				problem = "no struct with a Schema method found"
+			}
+		}
+
+		if problem == "" {
+			continue
+		}
+
+		warnings = append(warnings, fmt.Sprintf("unresolved @%s %s in %s: %s",
+			annotation.Type, annotation.TerraformType, annotationLocation(annotation, serviceName), problem))
+	}
+
+	return warnings
+}
+
+// annotationLocation formats where an annotation is declared for warnings, e.g. "s3 (bucket.go:12)"
+func annotationLocation(annotation AnnotationResult, serviceName string) string {
+	switch {
+	case annotation.FilePath != "" && annotation.SourceLine > 0:
+		return fmt.Sprintf("%s (%s:%d)", serviceName, annotation.FilePath, annotation.SourceLine)
+	case annotation.FilePath != "":
+		return fmt.Sprintf("%s (%s)", serviceName, annotation.FilePath)
+	}
+	return serviceName
+}
+
+// factoryReturnType renders the first declared result of a factory function, e.g. "*schema.Resource",
+// or "" when there is no function or it declares no result
+func factoryReturnType(funcDecl *ast.FuncDecl) string {
+	if funcDecl == nil || funcDecl.Type.Results == nil || len(funcDecl.Type.Results.List) == 0 {
+		return ""
+	}
+	return types.ExprString(funcDecl.Type.Results.List[0].Type)
+}
+
+// frameworkReturnPackages maps the packages of the framework interfaces a factory returns,
+// e.g. resource.ResourceWithConfigure, to the annotation type registering such a factory
+var frameworkReturnPackages = map[string]AnnotationType{
+	"resource":   AnnotationFrameworkResource,
+	"datasource": AnnotationFrameworkDataSource,
+	"ephemeral":  AnnotationEphemeralResource,
+}
+
+// factoryReturnTypeKinds returns the annotation types consistent with a factory's declared return type:
+// SDK annotations for *schema.Resource, the matching framework annotation for a resource., datasource. or
+// ephemeral. interface. It returns nil when the return type is absent or not recognised
+func factoryReturnTypeKinds(returnType string) map[AnnotationType]bool {
+	expr, err := parser.ParseExpr(returnType)
+	if returnType == "" || err != nil {
+		return nil
+	}
+
+	if star, ok := expr.(*ast.StarExpr); ok {
+		if selector, ok := star.X.(*ast.SelectorExpr); ok && selector.Sel.Name == "Resource" {
+			return map[AnnotationType]bool{AnnotationSDKResource: true, AnnotationSDKDataSource: true}
+		}
+		return nil
+	}
+	if selector, ok := expr.(*ast.SelectorExpr); ok {
+		if pkg, ok := selector.X.(*ast.Ident); ok {
+			if annotationType, ok := frameworkReturnPackages[pkg.Name]; ok {
+				return map[AnnotationType]bool{annotationType: true}
+			}
+		}
+	}
+	return nil
+}
+
+// verifyFactoryFunctions flags factory functions the package does not declare. Map-style registrations calling
+// an undeclared function are a typo or generator bug that would otherwise silently drop the terraform type from
+// the index. The factory_function emitted for every converted entry of serviceReg is checked too, it is only
+// derived from the terraform type for annotated type declarations and dangles whenever the package names the
+// factory differently. Warnings are reported in a stable order
+func verifyFactoryFunctions(packageInfo *gophon.PackageInfo, serviceReg *ServiceRegistration) []string {
+	if packageInfo == nil {
+		return nil
+	}
+	serviceName := serviceReg.ServiceName
+
+	declared := make(map[string]bool)
+	for _, fileInfo := range packageInfo.Files {
+		if fileInfo == nil || fileInfo.File == nil {
+			continue
+		}
+		for _, decl := range fileInfo.File.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil {
+				declared[funcDecl.Name.Name] = true
+			}
+		}
+	}
+
+	registrations := findMapRegistrations(packageInfo)
+	functionNames := make([]string, 0, len(registrations))
+	for functionName := range registrations {
+		if !declared[functionName] {
+			functionNames = append(functionNames, functionName)
+		}
+	}
+	sort.Strings(functionNames)
+
+	var warnings []string
+	for _, functionName := range functionNames {
+		for _, registration := range registrations[functionName] {
+			warnings = append(warnings, fmt.Sprintf("dangling factory function %s of %s %s in %s: not declared in the package",
+				functionName, registration.RegistrationMethod, registration.TerraformType, serviceName))
+		}
+	}
+
+	_ = walkServiceEntries(serviceReg, func(category string, _ bool, _ *ServiceRegistration, resource AWSResource) error {
+		if resource.FactoryFunction == "" || declared[resource.FactoryFunction] {
+			return nil
+		}
+		warnings = append(warnings, fmt.Sprintf("dangling factory function %s of %s %s in %s: not declared in the package",
+			resource.FactoryFunction, category, resource.TerraformType, serviceName))
+		return nil
+	})
+
+	return warnings
+}
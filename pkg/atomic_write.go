@@ -0,0 +1,113 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/afero"
+)
+
+// writeIndexFilesAtomically writes the index into a temporary sibling of outputDir and only moves it into place
+// once every file has been written, so a failed or interrupted run leaves any previous output untouched
+func (index *TerraformProviderIndex) writeIndexFilesAtomically(outputDir string, progressCallback ProgressCallback) error {
+	outputDir, err := cleanOutputDir(outputDir)
+	if err != nil {
+		return err
+	}
+
+	// A sibling directory keeps the final rename on the same filesystem in the common case
+	parentDir := filepath.Dir(outputDir)
+	if err := outputFs.MkdirAll(parentDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", parentDir, err)
+	}
+	tempDir, err := afero.TempDir(outputFs, parentDir, "."+filepath.Base(outputDir)+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary output directory: %w", err)
+	}
+	// TempDir creates the directory private to the user, the output must keep the permissions of a regular one
+	if err := outputFs.Chmod(tempDir, 0755); err != nil {
+		_ = outputFs.RemoveAll(tempDir)
+		return fmt.Errorf("failed to set permissions of temporary output directory %s: %w", tempDir, err)
+	}
+
+	if err := index.WriteIndexFiles(tempDir, progressCallback); err != nil {
+		_ = outputFs.RemoveAll(tempDir)
+		return err
+	}
+
+	if err := replaceDirectory(tempDir, outputDir); err != nil {
+		_ = outputFs.RemoveAll(tempDir)
+		return fmt.Errorf("failed to move output into %s: %w", outputDir, err)
+	}
+	return nil
+}
+
+// replaceDirectory replaces dst with src. An existing dst is moved aside first, restored if src cannot be
+// moved into its place and removed once it has been
+func replaceDirectory(src, dst string) error {
+	exists, err := afero.Exists(outputFs, dst)
+	if err != nil {
+		return err
+	}
+
+	backupDir := ""
+	if exists {
+		backupDir = src + ".previous"
+		if err := outputFs.Rename(dst, backupDir); err != nil {
+			return err
+		}
+	}
+
+	if err := moveDirectory(src, dst); err != nil {
+		if backupDir != "" {
+			_ = outputFs.Rename(backupDir, dst)
+		}
+		return err
+	}
+
+	if backupDir != "" {
+		return outputFs.RemoveAll(backupDir)
+	}
+	return nil
+}
+
+// moveDirectory renames src to dst, falling back to copying and removing src when they are on different devices
+func moveDirectory(src, dst string) error {
+	err := outputFs.Rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	if err := copyDirectory(src, dst); err != nil {
+		_ = outputFs.RemoveAll(dst)
+		return err
+	}
+	return outputFs.RemoveAll(src)
+}
+
+// copyDirectory copies every directory and file under src to the same relative path under dst
+func copyDirectory(src, dst string) error {
+	return afero.Walk(outputFs, src, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(src, filePath)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, relPath)
+
+		if info.IsDir() {
+			return outputFs.MkdirAll(target, 0755)
+		}
+		data, err := afero.ReadFile(outputFs, filePath)
+		if err != nil {
+			return err
+		}
+		return afero.WriteFile(outputFs, target, data, info.Mode().Perm())
+	})
+}
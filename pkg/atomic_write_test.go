@@ -0,0 +1,144 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingPathFs fails opening any file for writing whose path contains fragment
+type failingPathFs struct {
+	afero.Fs
+	fragment string
+}
+
+func (f *failingPathFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if (flag&os.O_WRONLY != 0 || flag&os.O_RDWR != 0) && strings.Contains(name, f.fragment) {
+		return nil, &os.PathError{Op: "open", Path: name, Err: syscall.EROFS}
+	}
+	return f.Fs.OpenFile(name, flag, perm)
+}
+
+// crossDeviceFs fails renaming anything onto target as if it were on another device
+type crossDeviceFs struct {
+	afero.Fs
+	target string
+}
+
+func (f *crossDeviceFs) Rename(oldname, newname string) error {
+	if newname == f.target {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: syscall.EXDEV}
+	}
+	return f.Fs.Rename(oldname, newname)
+}
+
+// writePreviousOutput creates the output of an earlier run in /output
+func writePreviousOutput(t *testing.T, fs afero.Fs) {
+	require.NoError(t, afero.WriteFile(fs, "/output/terraform-provider-aws-index.json", []byte("previous"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/output/resources/aws_stale.json", []byte("stale"), 0644))
+}
+
+// outputSiblings lists the entries of the output directory's parent
+func outputSiblings(t *testing.T, fs afero.Fs) []string {
+	entries, err := afero.ReadDir(fs, "/")
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_AtomicReplacesPreviousOutput(t *testing.T) {
+	// Setup
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	writePreviousOutput(t, fs)
+
+	// Execute
+	err := createTestTerraformProviderIndex().WriteIndexFilesWithOptions("/output", nil, OutputOptions{Atomic: true})
+
+	// Verify - the new output fully replaced the previous one and no temporary directory is left
+	require.NoError(t, err)
+	content, err := afero.ReadFile(fs, "/output/terraform-provider-aws-index.json")
+	require.NoError(t, err)
+	assert.NotEqual(t, "previous", string(content))
+	for _, name := range []string{"/output/resources/aws_s3_bucket.json", "/output/datasources/aws_s3_bucket.json", "/output/manifest.json"} {
+		exists, err := afero.Exists(fs, name)
+		require.NoError(t, err)
+		assert.True(t, exists, name)
+	}
+	exists, err := afero.Exists(fs, "/output/resources/aws_stale.json")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.Equal(t, []string{"output"}, outputSiblings(t, fs))
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_AtomicFailureKeepsPreviousOutput(t *testing.T) {
+	// Setup - writes fail once the data source files are reached
+	memFs := afero.NewMemMapFs()
+	writePreviousOutput(t, memFs)
+	stub := gostub.Stub(&outputFs, afero.Fs(&failingPathFs{Fs: memFs, fragment: "/datasources/"}))
+	defer stub.Reset()
+
+	// Execute
+	err := createTestTerraformProviderIndex().WriteIndexFilesWithOptions("/output", nil, OutputOptions{Atomic: true})
+
+	// Verify - the previous output is untouched and the partial output was discarded
+	require.Error(t, err)
+	content, err := afero.ReadFile(memFs, "/output/terraform-provider-aws-index.json")
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(content))
+	exists, err := afero.Exists(memFs, "/output/resources/aws_stale.json")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = afero.Exists(memFs, "/output/resources/aws_s3_bucket.json")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.Equal(t, []string{"output"}, outputSiblings(t, memFs))
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_AtomicCrossDeviceCopies(t *testing.T) {
+	// Setup
+	memFs := afero.NewMemMapFs()
+	writePreviousOutput(t, memFs)
+	stub := gostub.Stub(&outputFs, afero.Fs(&crossDeviceFs{Fs: memFs, target: "/output"}))
+	defer stub.Reset()
+
+	// Execute
+	err := createTestTerraformProviderIndex().WriteIndexFilesWithOptions("/output", nil, OutputOptions{Atomic: true})
+
+	// Verify - the output was copied into place and the temporary and previous directories removed
+	require.NoError(t, err)
+	exists, err := afero.Exists(memFs, "/output/resources/aws_s3_bucket.json")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = afero.Exists(memFs, "/output/resources/aws_stale.json")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.Equal(t, []string{"output"}, outputSiblings(t, memFs))
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_AtomicOutputDirectoryMode(t *testing.T) {
+	// Setup - the temporary directory is created private to the user on a real filesystem
+	stub := gostub.Stub(&outputFs, afero.NewOsFs())
+	defer stub.Reset()
+	outputDir := filepath.Join(t.TempDir(), "output")
+
+	// Execute
+	err := createTestTerraformProviderIndex().WriteIndexFilesWithOptions(outputDir, nil, OutputOptions{Atomic: true})
+
+	// Verify - the output directory has the permissions of one created by a non-atomic run
+	require.NoError(t, err)
+	info, err := os.Stat(outputDir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
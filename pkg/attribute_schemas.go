@@ -0,0 +1,307 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// AttributeSchema describes a schema attribute or block in the files written by WriteAttributeSchemas
+type AttributeSchema struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`         // "string", "list", "list_nested", ..., empty when built by a function call
+	ElementType string `json:"element_type,omitempty"` // Element type of lists, sets and maps of primitives, e.g. "string"
+	Required    bool   `json:"required,omitempty"`
+	Optional    bool   `json:"optional,omitempty"`
+	Computed    bool   `json:"computed,omitempty"`
+	Sensitive   bool   `json:"sensitive,omitempty"`
+
+	// Attributes and blocks nested in a block or nested attribute, sorted by name
+	Attributes []AttributeSchema `json:"attributes,omitempty"`
+}
+
+// ResourceAttributeSchemas is the content of attributes/<terraform_type>.json written by WriteAttributeSchemas
+type ResourceAttributeSchemas struct {
+	TerraformType string            `json:"terraform_type"`
+	Namespace     string            `json:"namespace"`
+	SDKType       string            `json:"sdk_type"`   // "aws_sdk" or "aws_framework"
+	Attributes    []AttributeSchema `json:"attributes"` // Top-level attributes and blocks, sorted by name
+}
+
+// WriteAttributeSchemas writes one attributes/<terraform_type>.json file per SDK and framework resource, describing
+// its schema attributes with their types, required, optional and computed flags and nested attributes.
+// Files are named like the resource files, so OutputOptions.FileNameTemplate applies to them as well.
+// Attributes can only be described when the service's package AST is available, i.e. for a freshly scanned index
+func (index *TerraformProviderIndex) WriteAttributeSchemas(outputDir string) error {
+	return index.writeAttributeSchemas(outputDir, nil)
+}
+
+// writeAttributeSchemas is WriteAttributeSchemas reporting each written file to progressTracker
+func (index *TerraformProviderIndex) writeAttributeSchemas(outputDir string, progressTracker *ProgressTracker) error {
+	schemasDir := filepath.Join(outputDir, attributeSchemasDir)
+
+	// Ensure attributes directory exists even if no files will be written
+	if err := outputFs.MkdirAll(schemasDir, 0755); err != nil {
+		return fmt.Errorf("failed to create attributes directory %s: %w", schemasDir, err)
+	}
+
+	var tasks []func() error
+
+	for i := range index.Services {
+		// Capture variables for closure
+		svc := &index.Services[i]
+
+		for _, framework := range []bool{false, true} {
+			resources := svc.AWSSDKResources
+			if framework {
+				resources = svc.AWSFrameworkResources
+			}
+			for terraformType, resource := range resources {
+				tasks = append(tasks, func() error {
+					fileName, err := index.entryFileName(WalkCategoryResource, svc.ServiceName, terraformType)
+					if err != nil {
+						return err
+					}
+					schemas := newResourceAttributeSchemas(svc, resource, framework)
+					if err := index.WriteJSONFile(filepath.Join(schemasDir, filepath.FromSlash(fileName)), schemas); err != nil {
+						return fmt.Errorf("failed to write attribute schema file %s: %w", fileName, err)
+					}
+
+					if progressTracker != nil {
+						progressTracker.UpdateProgress(fmt.Sprintf("attributes %s", terraformType))
+					}
+					return nil
+				})
+			}
+		}
+	}
+
+	return processCallbacksParallel(tasks)
+}
+
+// newResourceAttributeSchemas describes the schema of an SDK or framework resource of service
+func newResourceAttributeSchemas(service *ServiceRegistration, resource AWSResource, framework bool) ResourceAttributeSchemas {
+	schemas := ResourceAttributeSchemas{
+		TerraformType: resource.TerraformType,
+		Namespace:     service.PackagePath,
+		SDKType:       "aws_sdk",
+	}
+	if framework {
+		schemas.SDKType = "aws_framework"
+		schemas.Attributes = extractFrameworkAttributeSchemas(service.Package, resource.StructType)
+	} else {
+		schemas.Attributes = extractSDKAttributeSchemas(service.Package, resource.declaredFactory())
+	}
+	if schemas.Attributes == nil {
+		schemas.Attributes = []AttributeSchema{}
+	}
+	return schemas
+}
+
+// extractSDKAttributeSchemas describes the attributes of the schema.Resource returned by an SDK factory function.
+// Blocks declared through Elem: &schema.Resource{...} are described with their nested attributes
+func extractSDKAttributeSchemas(packageInfo *gophon.PackageInfo, factoryFunction string) []AttributeSchema {
+	resourceLit := sdkSchemaResourceLit(packageInfo, factoryFunction)
+	if resourceLit == nil {
+		return nil
+	}
+	return sdkAttributeSchemas(resourceLit)
+}
+
+// sdkAttributeSchemas describes every attribute of the Schema map of a schema.Resource literal, sorted by name
+func sdkAttributeSchemas(resourceLit *ast.CompositeLit) []AttributeSchema {
+	var attributes []AttributeSchema
+	for _, elt := range resourceLit.Elts {
+		keyValue, ok := elt.(*ast.KeyValueExpr)
+		if !ok || !isIdentNamed(keyValue.Key, "Schema") {
+			continue
+		}
+		mapLit, ok := keyValue.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+
+		for _, attr := range mapLit.Elts {
+			attrKeyValue, ok := attr.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			name := schemaAttributeKeyName(attrKeyValue.Key)
+			if name == "" {
+				continue
+			}
+			attribute := AttributeSchema{Name: name}
+			attrLit, ok := attrKeyValue.Value.(*ast.CompositeLit)
+			if !ok {
+				attrLit = addressOfCompositeLit(attrKeyValue.Value)
+			}
+			if attrLit != nil {
+				describeSDKAttribute(&attribute, attrLit)
+			}
+			attributes = append(attributes, attribute)
+		}
+	}
+	sortAttributeSchemas(attributes)
+	return attributes
+}
+
+// describeSDKAttribute fills the type, flags and nested attributes of attribute from its schema.Schema literal
+func describeSDKAttribute(attribute *AttributeSchema, attrLit *ast.CompositeLit) {
+	setAttributeFlags(attribute, attrLit)
+	for _, elt := range attrLit.Elts {
+		keyValue, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		switch {
+		case isIdentNamed(keyValue.Key, "Type"):
+			attribute.Type = schemaTypeName(keyValue.Value, "Type", "")
+		case isIdentNamed(keyValue.Key, "Elem"):
+			elemLit := addressOfCompositeLit(keyValue.Value)
+			if elemLit == nil {
+				continue
+			}
+			if blockLit := sdkSchemaElemResource(attrLit); blockLit != nil {
+				attribute.Attributes = sdkAttributeSchemas(blockLit)
+				continue
+			}
+			for _, elemElt := range elemLit.Elts {
+				if elemKeyValue, ok := elemElt.(*ast.KeyValueExpr); ok && isIdentNamed(elemKeyValue.Key, "Type") {
+					attribute.ElementType = schemaTypeName(elemKeyValue.Value, "Type", "")
+				}
+			}
+		}
+	}
+}
+
+// extractFrameworkAttributeSchemas describes the attributes and blocks declared in the Schema method of a
+// framework struct type, along with the attributes of nested attributes and blocks
+func extractFrameworkAttributeSchemas(packageInfo *gophon.PackageInfo, structType string) []AttributeSchema {
+	schemaMethod := findMethodDecl(packageInfo, structType, "Schema")
+	if schemaMethod == nil || schemaMethod.Body == nil {
+		return nil
+	}
+
+	var attributes []AttributeSchema
+	ast.Inspect(schemaMethod.Body, func(n ast.Node) bool {
+		compositeLit, ok := n.(*ast.CompositeLit)
+		if !ok || !isSchemaSchemaType(compositeLit.Type) {
+			return true
+		}
+		attributes = append(attributes, frameworkAttributeSchemas(compositeLit)...)
+
+		// Nested attributes belong to their parent block, don't descend further
+		return false
+	})
+
+	sortAttributeSchemas(attributes)
+	return attributes
+}
+
+// frameworkAttributeSchemas describes the Attributes and Blocks maps of a schema, block or nested object literal.
+// The attributes of a NestedObject are described as if declared on the literal itself
+func frameworkAttributeSchemas(lit *ast.CompositeLit) []AttributeSchema {
+	var attributes []AttributeSchema
+	for _, elt := range lit.Elts {
+		keyValue, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := keyValue.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		valueLit, ok := keyValue.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+
+		switch key.Name {
+		case "NestedObject":
+			attributes = append(attributes, frameworkAttributeSchemas(valueLit)...)
+		case "Attributes", "Blocks":
+			for _, attr := range valueLit.Elts {
+				attrKeyValue, ok := attr.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				name := schemaAttributeKeyName(attrKeyValue.Key)
+				if name == "" {
+					continue
+				}
+				attribute := AttributeSchema{Name: name}
+				if attrLit, ok := attrKeyValue.Value.(*ast.CompositeLit); ok {
+					describeFrameworkAttribute(&attribute, attrLit)
+				}
+				attributes = append(attributes, attribute)
+			}
+		}
+	}
+	sortAttributeSchemas(attributes)
+	return attributes
+}
+
+// describeFrameworkAttribute fills the type, flags and nested attributes of attribute from its attribute or block
+// literal, e.g. schema.StringAttribute{Required: true} has type "string" and schema.ListNestedBlock{} "list_nested_block"
+func describeFrameworkAttribute(attribute *AttributeSchema, attrLit *ast.CompositeLit) {
+	attribute.Type = schemaTypeName(attrLit.Type, "", "Attribute")
+	setAttributeFlags(attribute, attrLit)
+	for _, elt := range attrLit.Elts {
+		if keyValue, ok := elt.(*ast.KeyValueExpr); ok && isIdentNamed(keyValue.Key, "ElementType") {
+			attribute.ElementType = schemaTypeName(keyValue.Value, "", "Type")
+		}
+	}
+	attribute.Attributes = frameworkAttributeSchemas(attrLit)
+}
+
+// setAttributeFlags sets the Required, Optional, Computed and Sensitive flags declared `true` in an attribute literal
+func setAttributeFlags(attribute *AttributeSchema, attrLit *ast.CompositeLit) {
+	for _, elt := range attrLit.Elts {
+		keyValue, ok := elt.(*ast.KeyValueExpr)
+		if !ok || !isIdentNamed(keyValue.Value, "true") {
+			continue
+		}
+		switch {
+		case isIdentNamed(keyValue.Key, "Required"):
+			attribute.Required = true
+		case isIdentNamed(keyValue.Key, "Optional"):
+			attribute.Optional = true
+		case isIdentNamed(keyValue.Key, "Computed"):
+			attribute.Computed = true
+		case isIdentNamed(keyValue.Key, "Sensitive"):
+			attribute.Sensitive = true
+		}
+	}
+}
+
+// schemaTypeName converts a package-qualified type expression to snake_case without the given prefix and suffix,
+// e.g. schema.TypeString with prefix "Type" yields "string" and types.StringType with suffix "Type" yields "string".
+// Expressions that are not a qualified identifier yield an empty name
+func schemaTypeName(expr ast.Expr, prefix, suffix string) string {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(selector.Sel.Name, prefix), suffix)
+	if name == "" {
+		return ""
+	}
+	return camelCaseToSnakeCase(name)
+}
+
+// isIdentNamed reports whether expr is the identifier name
+func isIdentNamed(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+// sortAttributeSchemas sorts attributes by name so files are stable across scans
+func sortAttributeSchemas(attributes []AttributeSchema) {
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Name < attributes[j].Name
+	})
+}
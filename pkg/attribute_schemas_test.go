@@ -0,0 +1,248 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const attributeSchemasTestSource = `package s3
+
+func resourceBucketPolicy() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			names.AttrBucket: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			names.AttrPolicy: {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"principals": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"condition": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"test": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			names.AttrTags: tftags.TagsSchema(),
+		},
+	}
+}
+
+func (r *bucketResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			names.AttrID: framework.IDAttribute(),
+			names.AttrBucket: schema.StringAttribute{
+				Required: true,
+			},
+			"labels": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"lifecycle_rule": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"days": schema.Int64Attribute{
+							Optional: true,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+`
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_AttributeSchemas(t *testing.T) {
+	// Setup
+	sut := createTestTerraformProviderIndex()
+	sut.Services[0].Package = &gophon.PackageInfo{
+		Files: []*gophon.FileInfo{{File: parseSourceForTest(t, attributeSchemasTestSource)}},
+	}
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+	options := OutputOptions{AttributeSchemas: true}
+
+	// Execute
+	err := sut.WriteIndexFilesWithOptions(outputDir, nil, options)
+
+	// Verify - SDK resource
+	require.NoError(t, err)
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "attributes", "aws_s3_bucket_policy.json"))
+	require.NoError(t, err)
+	var sdkSchemas ResourceAttributeSchemas
+	require.NoError(t, json.Unmarshal(data, &sdkSchemas))
+	assert.Equal(t, ResourceAttributeSchemas{
+		TerraformType: "aws_s3_bucket_policy",
+		Namespace:     sut.Services[0].PackagePath,
+		SDKType:       "aws_sdk",
+		Attributes: []AttributeSchema{
+			{Name: "bucket", Type: "string", Required: true},
+			{Name: "condition", Type: "list", Optional: true, Attributes: []AttributeSchema{
+				{Name: "test", Type: "string", Required: true},
+			}},
+			{Name: "policy", Type: "string", Optional: true, Computed: true, Sensitive: true},
+			{Name: "principals", Type: "set", ElementType: "string", Optional: true},
+			{Name: "tags"}, // Built by a function call, only the name is known
+		},
+	}, sdkSchemas)
+
+	// Verify - framework resource, blocks are described with their nested object's attributes
+	data, err = afero.ReadFile(fs, filepath.Join(outputDir, "attributes", "aws_s3_bucket.json"))
+	require.NoError(t, err)
+	var frameworkSchemas ResourceAttributeSchemas
+	require.NoError(t, json.Unmarshal(data, &frameworkSchemas))
+	assert.Equal(t, "aws_framework", frameworkSchemas.SDKType)
+	assert.Equal(t, []AttributeSchema{
+		{Name: "bucket", Type: "string", Required: true},
+		{Name: "id"},
+		{Name: "labels", Type: "map", ElementType: "string", Optional: true},
+		{Name: "lifecycle_rule", Type: "list_nested_block", Attributes: []AttributeSchema{
+			{Name: "days", Type: "int64", Optional: true, Computed: true},
+		}},
+	}, frameworkSchemas.Attributes)
+
+	// Data sources have no attribute schema file, and the plan and manifest list the written files
+	exists, err := afero.Exists(fs, filepath.Join(outputDir, "attributes", "aws_s3_bucket_data_source.json"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+	plan, err := sut.PlanIndexFilesWithOptions(outputDir, options)
+	require.NoError(t, err)
+	assert.Equal(t, 2, plan.Totals["attributes"])
+	data, err = afero.ReadFile(fs, filepath.Join(outputDir, manifestFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"attributes/aws_s3_bucket_policy.json"`)
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_AttributeSchemasFollowFileNameTemplate(t *testing.T) {
+	// Setup
+	sut := createTestTerraformProviderIndex()
+	sut.Services[0].Package = &gophon.PackageInfo{
+		Files: []*gophon.FileInfo{{File: parseSourceForTest(t, attributeSchemasTestSource)}},
+	}
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+	options := OutputOptions{AttributeSchemas: true, FileNameTemplate: "{{.Service}}/{{.TerraformType}}.json"}
+
+	// Execute
+	err := sut.WriteIndexFilesWithOptions(outputDir, nil, options)
+
+	// Verify - attribute schema files mirror the resource files
+	require.NoError(t, err)
+	for _, name := range []string{"aws_s3_bucket_policy.json", "aws_s3_bucket.json"} {
+		exists, err := afero.Exists(fs, filepath.Join(outputDir, "resources", "s3", name))
+		require.NoError(t, err)
+		assert.True(t, exists, "resources/s3/%s", name)
+		exists, err = afero.Exists(fs, filepath.Join(outputDir, "attributes", "s3", name))
+		require.NoError(t, err)
+		assert.True(t, exists, "attributes/s3/%s", name)
+	}
+	plan, err := sut.PlanIndexFilesWithOptions(outputDir, options)
+	require.NoError(t, err)
+	var planned []string
+	for _, file := range plan.Files {
+		if file.Category == WritePlanCategoryAttributeSchema {
+			planned = append(planned, file.Path)
+		}
+	}
+	assert.ElementsMatch(t, []string{"attributes/s3/aws_s3_bucket_policy.json", "attributes/s3/aws_s3_bucket.json"}, planned)
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, manifestFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"attributes/s3/aws_s3_bucket_policy.json"`)
+}
+
+func TestTerraformProviderIndex_WriteIndexFiles_NoAttributeSchemasByDefault(t *testing.T) {
+	sut := createTestTerraformProviderIndex()
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+
+	require.NoError(t, sut.WriteIndexFiles("/test/output", nil))
+
+	exists, err := afero.DirExists(fs, filepath.Join("/test/output", "attributes"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_AttributeSchemasOfScannedService(t *testing.T) {
+	// Setup - the SDK factory is named differently from resourceExampleWidget
+	serviceReg, err := ParseServiceSource([]byte(`package example
+
+// @SDKResource("aws_example_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			names.AttrName: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+}
+
+// @FrameworkResource("aws_example_gadget", name="Gadget")
+func newGadget(context.Context) (resource.ResourceWithConfigure, error) {
+	return &gadgetResource{}, nil
+}
+
+type gadgetResource struct{}
+
+func (r *gadgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"size": schema.Int64Attribute{
+				Optional: true,
+			},
+		},
+	}
+}
+`), "github.com/hashicorp/terraform-provider-aws/internal/service/example")
+	require.NoError(t, err)
+	sut := &TerraformProviderIndex{Version: "v1.0.0", Services: []ServiceRegistration{*serviceReg}}
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+
+	// Execute
+	require.NoError(t, sut.WriteIndexFilesWithOptions("/test/output", nil, OutputOptions{AttributeSchemas: true}))
+
+	// Verify
+	readAttributes := func(terraformType string) []AttributeSchema {
+		data, err := afero.ReadFile(fs, filepath.Join("/test/output", "attributes", terraformType+".json"))
+		require.NoError(t, err)
+		var schemas ResourceAttributeSchemas
+		require.NoError(t, json.Unmarshal(data, &schemas))
+		return schemas.Attributes
+	}
+	assert.Equal(t, []AttributeSchema{{Name: "name", Type: "string", Required: true}}, readAttributes("aws_example_widget"))
+	assert.Equal(t, []AttributeSchema{{Name: "size", Type: "int64", Optional: true}}, readAttributes("aws_example_gadget"))
+}
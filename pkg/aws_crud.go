@@ -14,4 +14,19 @@ type AWSCRUDMethods struct {
 	OpenMethod  string `json:"open_method,omitempty"`  // "Open"
 	RenewMethod string `json:"renew_method,omitempty"` // "Renew"
 	CloseMethod string `json:"close_method,omitempty"` // "Close"
+
+	// Import support, either an explicit method or an embedded framework helper
+	ImportStateMethod string `json:"import_state_method,omitempty"` // "ImportState" or "framework.WithImportByID"
+
+	// Plan-time and validation methods of framework resources
+	ModifyPlanMethod       string `json:"modify_plan_method,omitempty"`       // "ModifyPlan"
+	ValidateConfigMethod   string `json:"validate_config_method,omitempty"`   // "ValidateConfig"
+	ConfigValidatorsMethod string `json:"config_validators_method,omitempty"` // "ConfigValidators"
+
+	// Core methods not declared on the struct but provided by an embedded framework helper,
+	// keyed by method name, e.g. "Update" -> "framework.WithNoOpUpdate"
+	EmbeddedMethods map[string]string `json:"embedded_methods,omitempty"`
+
+	// Receiver variable name of the struct's methods, taken from Schema when declared, e.g. "r"
+	ReceiverName string `json:"receiver_name,omitempty"`
 }
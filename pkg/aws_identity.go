@@ -0,0 +1,18 @@
+package pkg
+
+// AWSIdentityConfig describes the resource identity declared by @IdentityAttribute,
+// @ArnIdentity and @SingletonIdentity annotations on a resource registration function
+type AWSIdentityConfig struct {
+	Attributes   []AWSIdentityAttribute `json:"attributes,omitempty"`    // From @IdentityAttribute, in declaration order
+	ARNAttribute string                 `json:"arn_attribute,omitempty"` // From @ArnIdentity, e.g. "arn"
+	Singleton    bool                   `json:"singleton,omitempty"`     // From @SingletonIdentity
+}
+
+// AWSIdentityAttribute represents a single @IdentityAttribute("name") annotation
+type AWSIdentityAttribute struct {
+	Name     string `json:"name"`
+	Optional bool   `json:"optional,omitempty"` // optional="true"
+}
+
+// defaultARNIdentityAttribute is used when @ArnIdentity omits the attribute name
+const defaultARNIdentityAttribute = "arn"
@@ -0,0 +1,8 @@
+package pkg
+
+// AWSImportConfig describes the Importer declared on an SDK schema.Resource literal,
+// e.g. Importer: &schema.ResourceImporter{StateContext: resourceWidgetImport}
+type AWSImportConfig struct {
+	Passthrough     bool   `json:"passthrough,omitempty"`       // StateContext is schema.ImportStatePassthroughContext
+	ImportStateFunc string `json:"import_state_func,omitempty"` // Custom StateContext function, e.g. "resourceWidgetImport"
+}
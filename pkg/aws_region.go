@@ -0,0 +1,8 @@
+package pkg
+
+// AWSRegionConfig describes the per-resource region behaviour declared by a @Region annotation on a registration
+// function, e.g. @Region(global=true) or @Region(overrideEnabled=false)
+type AWSRegionConfig struct {
+	Global          bool `json:"global,omitempty"` // global=true, the resource is not regional
+	OverrideEnabled bool `json:"override_enabled"` // The region argument may override the provider region, false for overrideEnabled=false
+}
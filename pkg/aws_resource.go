@@ -1,10 +1,88 @@
 package pkg
 
+import "strings"
+
 // AWSResource represents information about an AWS resource extracted from service package
 type AWSResource struct {
 	TerraformType   string `json:"terraform_type"`
-	FactoryFunction string `json:"factory_function"`
+	FactoryFunction string `json:"factory_function"`        // Declared factory, see annotatedFactoryFunction, e.g. "resourceWidget"
+	FunctionName    string `json:"function_name,omitempty"` // Annotated or map-registered function as declared, e.g. "resourceWidget"
 	Name            string `json:"name"`
 	SDKType         string `json:"sdk_type"`              // "sdk", "framework", "ephemeral"
 	StructType      string `json:"struct_type,omitempty"` // For framework resources: "customModelsDataSource"
+
+	// Framework methods expected of the struct split by whether it declares them or gets them from an embedded
+	// helper, nil for SDK entries and entries built by hand
+	ImplementedFrameworkMethods []string `json:"implemented_framework_methods,omitempty"` // ["Create", "Read", "Delete", "Metadata", "Schema"]
+	MissingFrameworkMethods     []string `json:"missing_framework_methods,omitempty"`     // ["Update"]
+
+	// Source file declaring the resource, relative to the scanned base directory
+	RegistrationFile string `json:"registration_file,omitempty"` // "lambda/function.go"
+	RawAnnotation    string `json:"raw_annotation,omitempty"`    // `@SDKResource("aws_lambda_function", name="Function")`
+
+	// Map-style registration function of older service packages, empty for annotated resources
+	RegistrationMethod string `json:"registration_method,omitempty"` // "SupportedResources"
+
+	// Deprecated terraform types registered by the same function, not counted as entries of their own
+	Aliases []string `json:"aliases,omitempty"` // ["aws_lambda_function_old"]
+
+	// Documentation declared on SDK resources
+	Description        string `json:"description,omitempty"`
+	DeprecationMessage string `json:"deprecation_message,omitempty"`
+
+	// State migration metadata declared on SDK resources
+	SchemaVersion  int      `json:"schema_version,omitempty"`
+	StateUpgraders []string `json:"state_upgraders,omitempty"` // Upgrade functions, e.g. ["resourceWidgetStateUpgradeV0"]
+
+	// Importer declared on SDK resources, nil when the resource cannot be imported
+	Import *AWSImportConfig `json:"import,omitempty"`
+
+	// Resource identity declared via @IdentityAttribute, @ArnIdentity or @SingletonIdentity
+	Identity *AWSIdentityConfig `json:"identity,omitempty"`
+
+	// Region behaviour declared via @Region, nil when the annotation is absent
+	Region *AWSRegionConfig `json:"region,omitempty"`
+
+	// SHA-256 of the registration function's code, changing whenever the function does
+	SourceHash string `json:"source_hash,omitempty"`
+
+	// Build tags of the //go:build constraint of the registration file, nil when it is part of every build
+	BuildTags []string `json:"build_tags,omitempty"`
+
+	// Tagging configuration, nil when the resource is not tagged
+	Tags *AWSTagsConfig `json:"tags,omitempty"`
+
+	// Acceptance test generation hints declared via @Testing, keyed by annotation argument
+	TestingConfig map[string]string `json:"testing_config,omitempty"`
+
+	// Set when a data source (for resources) or resource (for data sources) shares the terraform type,
+	// in any service
+	HasDataSourceTwin bool `json:"has_data_source_twin,omitempty"`
+	HasResourceTwin   bool `json:"has_resource_twin,omitempty"`
+}
+
+// declaredFactory returns the factory function declared in the package, the annotated function when known and
+// FactoryFunction otherwise, e.g. for entries built by hand or loaded back from entry files. Schema lookups and
+// indexes go through it
+func (r AWSResource) declaredFactory() string {
+	if r.FunctionName != "" {
+		return r.FunctionName
+	}
+	return r.FactoryFunction
+}
+
+// displayName returns the registration name of the resource or, when the annotation omits name=, one derived
+// from the terraform type by dropping the "aws_" prefix and capitalizing each word, e.g. "S3 Bucket Policy"
+func displayName(awsResource AWSResource) string {
+	if awsResource.Name != "" {
+		return awsResource.Name
+	}
+
+	words := strings.Split(strings.TrimPrefix(awsResource.TerraformType, "aws_"), "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
 }
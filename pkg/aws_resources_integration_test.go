@@ -398,7 +398,7 @@ func TestAWSResourcesIntegration_TerraformResourceConversion(t *testing.T) {
 				assert.Empty(t, terraformDataSource.StructType, "SDK data sources should not have struct types")
 				assert.Contains(t, terraformDataSource.SchemaIndex, tt.awsResource.FactoryFunction)
 			case "framework_resource":
-				terraformResource := NewTerraformResourceFromAWSFramework(tt.awsResource, service)
+				terraformResource := NewTerraformResourceFromAWSFramework(tt.awsResource, service, nil)
 				assert.Equal(t, tt.awsResource.TerraformType, terraformResource.TerraformType)
 				assert.Equal(t, tt.expectedType, terraformResource.SDKType)
 				assert.Equal(t, tt.expectedReg, terraformResource.RegistrationMethod)
@@ -565,21 +565,8 @@ func TestAWSResourcesIntegration_CRUDMethodExtraction(t *testing.T) {
 	}
 }
 
-// TestAWSResourcesIntegration_ProcessServiceFile tests that annotation-based parsing
-// correctly extracts all AWS resource types from service files
-func TestAWSResourcesIntegration_ProcessServiceFile(t *testing.T) {
-	tests := []struct {
-		name                   string
-		sourceCode             string
-		expectedSDKResources   int
-		expectedSDKDataSources int
-		expectedFrameworkRes   int
-		expectedFrameworkDS    int
-		expectedEphemeral      int
-	}{
-		{
-			name: "Mixed resource types in single service file",
-			sourceCode: `package example
+// mixedResourceServiceSource registers one entry of each of the five categories
+const mixedResourceServiceSource = `package example
 
 import (
 	"context"
@@ -635,7 +622,23 @@ type exampleEphemeralResource struct {
 }
 
 func (e *exampleEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {}
-`,
+`
+
+// TestAWSResourcesIntegration_ProcessServiceFile tests that annotation-based parsing
+// correctly extracts all AWS resource types from service files
+func TestAWSResourcesIntegration_ProcessServiceFile(t *testing.T) {
+	tests := []struct {
+		name                   string
+		sourceCode             string
+		expectedSDKResources   int
+		expectedSDKDataSources int
+		expectedFrameworkRes   int
+		expectedFrameworkDS    int
+		expectedEphemeral      int
+	}{
+		{
+			name:                   "Mixed resource types in single service file",
+			sourceCode:             mixedResourceServiceSource,
 			expectedSDKResources:   1,
 			expectedSDKDataSources: 1,
 			expectedFrameworkRes:   1,
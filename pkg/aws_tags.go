@@ -0,0 +1,183 @@
+package pkg
+
+import (
+	"go/ast"
+	"regexp"
+	"strings"
+)
+
+// AWSTagsConfig describes how a resource or data source handles tags
+type AWSTagsConfig struct {
+	HasTags             bool   `json:"has_tags"`
+	IdentifierAttribute string `json:"identifier_attribute,omitempty"` // From @Tags(identifierAttribute="arn")
+	ResourceType        string `json:"resource_type,omitempty"`        // From @Tags(resourceType="Widget")
+
+	// TransparentTagging is true when tags are handled by the provider's transparent tagging interceptor,
+	// i.e. the resource is annotated with @Tags and exposes a names.AttrTags attribute, or embeds the
+	// framework tagging helper. Resources with a tags attribute but no @Tags annotation manage tags manually
+	TransparentTagging bool `json:"transparent_tagging"`
+
+	// TagsOnCreate is true when the tags attribute is settable in configuration, so tags can be applied when the
+	// resource is created. Inferred from the schema: TagsSchema() or an Optional/Required attribute is settable,
+	// TagsSchemaComputed() or a Computed-only attribute is not. False when no tags attribute was found
+	TagsOnCreate bool `json:"tags_on_create,omitempty"`
+
+	// HasTagsAll is true when the schema also declares the computed tags_all attribute, which merges the resource's
+	// tags with the provider's default_tags after ignore_tags is applied, as transparent tagging does
+	HasTagsAll bool `json:"has_tags_all,omitempty"`
+}
+
+// tagsAnnotationRegex matches the @Tags annotation, the argument list is optional
+var tagsAnnotationRegex = regexp.MustCompile(`@Tags\b`)
+
+// frameworkTransparentTaggingHelpers lists embeddable framework helpers that opt into transparent tagging
+var frameworkTransparentTaggingHelpers = map[string]bool{
+	"WithTransparentTagging": true,
+}
+
+// parseTagsAnnotation parses a @Tags annotation in a function's doc comment
+// It returns nil when the comment has no @Tags annotation
+func parseTagsAnnotation(text string) *AWSTagsConfig {
+	loc := tagsAnnotationRegex.FindStringIndex(text)
+	if loc == nil {
+		return nil
+	}
+
+	tags := &AWSTagsConfig{HasTags: true}
+	if rest := text[loc[1]:]; strings.HasPrefix(rest, "(") {
+		args, _, ok := parseAnnotationArguments(rest[1:])
+		if ok {
+			tags.IdentifierAttribute = namesAttributeName(args.Keywords["identifierAttribute"])
+			tags.ResourceType = namesResourceType(args.Keywords["resourceType"])
+		}
+	}
+
+	return tags
+}
+
+// namesAttributeName resolves an attribute given as a names.Attr* constant to its snake_case attribute name,
+// e.g. names.AttrBucketName -> "bucket_name". Plain attribute names are returned unchanged
+func namesAttributeName(value string) string {
+	if constant, ok := strings.CutPrefix(value, "names.Attr"); ok && constant != "" {
+		return camelCaseToSnakeCase(constant)
+	}
+	return value
+}
+
+// namesResourceType resolves a resource type given as a names.Attr* constant to the CamelCase resource type its
+// name spells, e.g. names.AttrBucket -> "Bucket", since resource types keep the constant's casing. Other constant
+// references, e.g. names.WidgetResourceType, can't be resolved without the names package and keep their selector
+// text. Plain resource types are returned unchanged
+func namesResourceType(value string) string {
+	if constant, ok := strings.CutPrefix(value, "names.Attr"); ok && constant != "" {
+		return constant
+	}
+	return value
+}
+
+// extractAWSTagsConfig completes the tags configuration of an annotated registration by looking for a tags
+// schema attribute, in the annotated function for SDK types or the struct's Schema method for framework types,
+// and for the transparent tagging embed. It returns nil when the registration has no tags at all
+func extractAWSTagsConfig(file *ast.File, annotation basicAnnotation, structType string) *AWSTagsConfig {
+	var schemaNode ast.Node
+	switch annotation.Type {
+	case AnnotationSDKResource, AnnotationSDKDataSource:
+		if annotation.Decl != nil {
+			schemaNode = annotation.Decl
+		}
+	default:
+		if method := findFileMethodDecl(file, structType, "Schema"); method != nil {
+			schemaNode = method
+		}
+	}
+
+	var tagsAttribute, tagsAllAttribute ast.Expr
+	if schemaNode != nil {
+		tagsAttribute = findSchemaAttribute(schemaNode, "tags")
+		tagsAllAttribute = findSchemaAttribute(schemaNode, "tags_all")
+	}
+	hasTagsAttribute := tagsAttribute != nil
+	hasTaggingEmbed := structType != "" && findEmbeddedFrameworkHelper(file, structType, frameworkTransparentTaggingHelpers) != ""
+
+	if annotation.Tags == nil && !hasTagsAttribute && !hasTaggingEmbed {
+		return nil
+	}
+
+	tags := &AWSTagsConfig{HasTags: true}
+	if annotation.Tags != nil {
+		*tags = *annotation.Tags
+	}
+	tags.TransparentTagging = hasTaggingEmbed || (annotation.Tags != nil && hasTagsAttribute)
+	tags.TagsOnCreate = hasTagsAttribute && isSettableTagsAttribute(tagsAttribute)
+	tags.HasTagsAll = tagsAllAttribute != nil
+
+	return tags
+}
+
+// findSchemaAttribute returns the schema of the attribute named name declared in node, e.g. the
+// `tftags.TagsSchema()` of `names.AttrTags: tftags.TagsSchema()` for "tags", or nil when node declares none
+func findSchemaAttribute(node ast.Node, name string) ast.Expr {
+	var found ast.Expr
+	ast.Inspect(node, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		keyValue, ok := n.(*ast.KeyValueExpr)
+		if ok && schemaAttributeKeyName(keyValue.Key) == name {
+			found = keyValue.Value
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// isSettableTagsAttribute reports whether a tags attribute schema can be set in configuration. Helpers such as
+// tftags.TagsSchemaComputed() or tftags.TagsAttributeComputedOnly() are computed-only, a literal schema is settable
+// when it is Optional or Required. Anything else, e.g. a provider-specific helper, is assumed settable
+func isSettableTagsAttribute(expr ast.Expr) bool {
+	if unary, ok := expr.(*ast.UnaryExpr); ok {
+		expr = unary.X
+	}
+
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		name := ""
+		switch fun := e.Fun.(type) {
+		case *ast.Ident:
+			name = fun.Name
+		case *ast.SelectorExpr:
+			name = fun.Sel.Name
+		}
+		return !strings.HasSuffix(name, "Computed") && !strings.HasSuffix(name, "ComputedOnly")
+	case *ast.CompositeLit:
+		flags := make(map[string]bool)
+		for _, elt := range e.Elts {
+			keyValue, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := keyValue.Key.(*ast.Ident)
+			value, isIdent := keyValue.Value.(*ast.Ident)
+			if ok && isIdent && value.Name == "true" {
+				flags[key.Name] = true
+			}
+		}
+		return flags["Optional"] || flags["Required"]
+	}
+	return true
+}
+
+// findFileMethodDecl finds the method declaration named methodName on structType within a single file
+func findFileMethodDecl(file *ast.File, structType, methodName string) *ast.FuncDecl {
+	if structType == "" {
+		return nil
+	}
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if ok && funcDecl.Name.Name == methodName && receiverTypeName(funcDecl) == structType {
+			return funcDecl
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"sort"
+)
+
+// fileBuildTags returns the sorted, distinct build tags referenced by the //go:build constraint of file, e.g.
+// ["experimental"] for //go:build experimental. It returns nil when the file has no constraint and is part of
+// every build. Legacy // +build lines are only read when there is no //go:build line, as the go command does
+func fileBuildTags(file *ast.File) []string {
+	var goBuild, plusBuild []constraint.Expr
+	for _, group := range file.Comments {
+		// Build constraints must appear before the package clause
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			expr, err := constraint.Parse(comment.Text)
+			if err != nil {
+				continue
+			}
+			if constraint.IsGoBuild(comment.Text) {
+				goBuild = append(goBuild, expr)
+			} else {
+				plusBuild = append(plusBuild, expr)
+			}
+		}
+	}
+
+	exprs := goBuild
+	if len(exprs) == 0 {
+		exprs = plusBuild
+	}
+
+	tagSet := make(map[string]bool)
+	for _, expr := range exprs {
+		expr.Eval(func(tag string) bool {
+			tagSet[tag] = true
+			return true
+		})
+	}
+	if len(tagSet) == 0 {
+		return nil
+	}
+
+	tags := make([]string, 0, len(tagSet))
+	for tag := range tagSet {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,75 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileBuildTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected []string
+	}{
+		{
+			name:     "go:build constraint",
+			source:   "//go:build experimental && !windows\n\npackage widget\n",
+			expected: []string{"experimental", "windows"},
+		},
+		{
+			name:     "legacy plus build line",
+			source:   "// +build experimental\n\npackage widget\n",
+			expected: []string{"experimental"},
+		},
+		{
+			name:   "no constraint",
+			source: "// Package widget manages widgets\npackage widget\n",
+		},
+		{
+			name:   "constraint-like comment after the package clause",
+			source: "package widget\n\n//go:build experimental\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, fileBuildTags(parseSourceForTest(t, tt.source)))
+		})
+	}
+}
+
+func TestParseServiceSource_BuildConstrainedEntries(t *testing.T) {
+	// Setup
+	src := `//go:build experimental
+
+package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+
+// @SDKDataSource("aws_widget", name="Widget")
+func dataSourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceWidgetRead,
+	}
+}
+`
+
+	// Execute
+	serviceReg, err := ParseServiceSource([]byte(src), "example.com/provider/widget")
+	require.NoError(t, err)
+	resource := NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_widget"], *serviceReg)
+	dataSource := NewTerraformDataSourceFromAWSSDK(serviceReg.AWSSDKDataSources["aws_widget"], *serviceReg)
+
+	// Verify
+	assert.True(t, resource.BuildConstrained)
+	assert.Equal(t, []string{"experimental"}, resource.BuildTags)
+	assert.True(t, dataSource.BuildConstrained)
+	assert.Equal(t, []string{"experimental"}, dataSource.BuildTags)
+}
@@ -0,0 +1,13 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// entryID returns a stable identifier for an index entry derived from its namespace, terraform type and
+// walk category, e.g. WalkCategoryResource. It does not depend on file names or output layout
+func entryID(namespace, terraformType, category string) string {
+	sum := sha256.Sum256([]byte(namespace + "\x00" + terraformType + "\x00" + category))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,44 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryID_StableAcrossConversions(t *testing.T) {
+	// Setup - identical input converted twice, the second time from a fresh copy
+	newService := func() ServiceRegistration {
+		service := CreateTestServiceRegistration("s3")
+		service.PackagePath = "github.com/hashicorp/terraform-provider-aws/internal/service/s3"
+		return service
+	}
+	awsResource := AWSResource{TerraformType: "aws_s3_bucket", FactoryFunction: "resourceBucket", SDKType: "sdk"}
+	awsEphemeral := AWSResource{TerraformType: "aws_s3_bucket", StructType: "bucketEphemeralResource", SDKType: "ephemeral"}
+
+	// Execute
+	first := NewTerraformResourceFromAWSSDK(awsResource, newService())
+	second := NewTerraformResourceFromAWSSDK(awsResource, newService())
+	dataSource := NewTerraformDataSourceFromAWSSDK(awsResource, newService())
+	ephemeral := NewTerraformEphemeralFromAWS(awsEphemeral, newService())
+
+	// Verify - same inputs give the same ID, a different category gives a different one
+	require.NotEmpty(t, first.ID)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, entryID(first.Namespace, "aws_s3_bucket", WalkCategoryResource), first.ID)
+	assert.NotEqual(t, first.ID, dataSource.ID)
+	assert.NotEqual(t, first.ID, ephemeral.ID)
+	assert.NotEqual(t, dataSource.ID, ephemeral.ID)
+
+	// The framework conversion of the same terraform type is still the same resource
+	framework := NewTerraformResourceFromAWSFramework(AWSResource{TerraformType: "aws_s3_bucket", StructType: "bucketResource", SDKType: "framework"}, newService(), nil)
+	assert.Equal(t, first.ID, framework.ID)
+}
+
+func TestEntryID_DependsOnNamespace(t *testing.T) {
+	assert.NotEqual(t,
+		entryID("example.com/provider/s3", "aws_widget", WalkCategoryResource),
+		entryID("example.com/provider/s3control", "aws_widget", WalkCategoryResource))
+	assert.Len(t, entryID("example.com/provider/s3", "aws_widget", WalkCategoryResource), 32)
+}
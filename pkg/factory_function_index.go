@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// FactoryFunctionIndex builds a reverse index from declared factory function name, e.g. the annotated function,
+// to the terraform types it registers across all services and categories. The value is a slice because the same function name may be
+// reused, e.g. by a resource and data source in different services. Terraform types are sorted and unique
+func (index *TerraformProviderIndex) FactoryFunctionIndex() map[string][]string {
+	typeSets := make(map[string]map[string]bool)
+
+	for _, service := range index.Services {
+		for _, resources := range []map[string]AWSResource{
+			service.AWSSDKResources,
+			service.AWSSDKDataSources,
+			service.AWSFrameworkResources,
+			service.AWSFrameworkDataSources,
+			service.AWSEphemeralResources,
+		} {
+			for terraformType, resource := range resources {
+				factoryFunction := resource.declaredFactory()
+				if factoryFunction == "" {
+					continue
+				}
+				if typeSets[factoryFunction] == nil {
+					typeSets[factoryFunction] = make(map[string]bool)
+				}
+				typeSets[factoryFunction][terraformType] = true
+			}
+		}
+	}
+
+	result := make(map[string][]string, len(typeSets))
+	for factoryFunction, terraformTypes := range typeSets {
+		types := make([]string, 0, len(terraformTypes))
+		for terraformType := range terraformTypes {
+			types = append(types, terraformType)
+		}
+		sort.Strings(types)
+		result[factoryFunction] = types
+	}
+
+	return result
+}
+
+// WriteFactoryIndexFile writes the factory-index.json reverse index file
+func (index *TerraformProviderIndex) WriteFactoryIndexFile(outputDir string) error {
+	factoryIndexPath := filepath.Join(outputDir, "factory-index.json")
+	return index.WriteJSONFile(factoryIndexPath, index.FactoryFunctionIndex())
+}
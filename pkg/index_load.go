@@ -0,0 +1,231 @@
+package pkg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// loadedEntry holds the fields shared by the TerraformResource, TerraformDataSource and TerraformEphemeral files
+type loadedEntry struct {
+	TerraformType      string `json:"terraform_type"`
+	StructType         string `json:"struct_type"`
+	Namespace          string `json:"namespace"`
+	PackageName        string `json:"package_name"`
+	RegistrationMethod string `json:"registration_method"`
+	SDKType            string `json:"sdk_type"`
+	SchemaIndex        string `json:"schema_index"`
+	RegistrationFile   string `json:"registration_file"`
+}
+
+// LoadIndexFromDir reads an output tree written by WriteIndexFiles back into a TerraformProviderIndex, so tooling
+// can get statistics and type lists without scanning source again. The main index is authoritative when present.
+// Entries only found under resources/, datasources/ or ephemeral/ are rebuilt from their files, grouped into services
+// by namespace, and the statistics are recomputed. Rebuilt entries carry only what the entry files record.
+// Gzip-compressed trees are read transparently
+func LoadIndexFromDir(dir string) (*TerraformProviderIndex, error) {
+	return LoadIndexFromDirWithOptions(dir, OutputOptions{})
+}
+
+// LoadIndexFromDirWithOptions is like LoadIndexFromDir for a tree written by WriteIndexFilesWithOptions with options,
+// it reads the main index and the category directories under their configured names
+func LoadIndexFromDirWithOptions(dir string, options OutputOptions) (*TerraformProviderIndex, error) {
+	if err := options.validateCategoryDirs(); err != nil {
+		return nil, err
+	}
+	if err := options.validateMainIndexFileName(); err != nil {
+		return nil, err
+	}
+	index := &TerraformProviderIndex{}
+
+	mainFound, err := readOutputJSONFile(filepath.Join(dir, options.mainIndexFileName()), index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load main index: %w", err)
+	}
+
+	// Entries already in the main index, keyed by category and terraform type
+	known := make(map[[2]string]bool)
+	err = index.walkEntries(func(category string, _ bool, _ *ServiceRegistration, resource AWSResource) error {
+		known[[2]string{category, resource.TerraformType}] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Legacy ephemeral mappings are written to ephemeral/ too but are not walked
+	for _, service := range index.Services {
+		for _, terraformType := range service.EphemeralTerraformTypes {
+			known[[2]string{WalkCategoryEphemeral, terraformType}] = true
+		}
+	}
+
+	rebuilt := 0
+	for _, category := range walkCategories {
+		entries, err := readEntryFiles(filepath.Join(dir, options.categoryDir(category)))
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if known[[2]string{category, entry.TerraformType}] {
+				continue
+			}
+			index.addLoadedEntry(category, entry)
+			rebuilt++
+		}
+	}
+
+	if !mainFound && rebuilt == 0 {
+		return nil, fmt.Errorf("no index files found in %s", dir)
+	}
+
+	if rebuilt > 0 {
+		for i := range index.Services {
+			index.Services[i].MixedImplementation = index.Services[i].isMixedImplementation()
+		}
+		sortServicesByName(index.Services)
+		linkResourceTwins(index.Services)
+		index.Statistics = newProviderStatistics(index.Services)
+	}
+
+	return index, nil
+}
+
+// addLoadedEntry adds an entry read from a category directory to the service owning its namespace
+func (index *TerraformProviderIndex) addLoadedEntry(category string, entry loadedEntry) {
+	serviceName := path.Base(entry.Namespace)
+	var service *ServiceRegistration
+	for i := range index.Services {
+		if index.Services[i].ServiceName == serviceName {
+			service = &index.Services[i]
+			break
+		}
+	}
+	if service == nil {
+		index.Services = append(index.Services, ServiceRegistration{
+			ServiceName: serviceName,
+			PackagePath: entry.Namespace,
+			PackageName: entry.PackageName,
+		})
+		service = &index.Services[len(index.Services)-1]
+	}
+
+	resource := AWSResource{
+		TerraformType:    entry.TerraformType,
+		StructType:       entry.StructType,
+		RegistrationFile: entry.RegistrationFile,
+	}
+
+	var target *map[string]AWSResource
+	switch category {
+	case WalkCategoryResource, WalkCategoryDataSource:
+		framework := entry.RegistrationMethod == "FrameworkResources" || entry.RegistrationMethod == "FrameworkDataSources"
+		if framework {
+			resource.SDKType = "framework"
+		} else {
+			// SDK entries record their factory function as the schema index, e.g. "func.resourceBucket.goindex"
+			resource.SDKType = "sdk"
+			resource.FactoryFunction = strings.TrimSuffix(strings.TrimPrefix(entry.SchemaIndex, "func."), ".goindex")
+		}
+		switch {
+		case category == WalkCategoryResource && framework:
+			target = &service.AWSFrameworkResources
+		case category == WalkCategoryResource:
+			target = &service.AWSSDKResources
+		case framework:
+			target = &service.AWSFrameworkDataSources
+		default:
+			target = &service.AWSSDKDataSources
+		}
+	case WalkCategoryEphemeral:
+		// Ephemeral entries record their factory function as the registration method
+		resource.SDKType = entry.SDKType
+		resource.FactoryFunction = entry.RegistrationMethod
+		target = &service.AWSEphemeralResources
+	}
+
+	if *target == nil {
+		*target = make(map[string]AWSResource)
+	}
+	(*target)[entry.TerraformType] = resource
+}
+
+// readEntryFiles reads every entry file below dir, a missing directory yields no entries
+func readEntryFiles(dir string) ([]loadedEntry, error) {
+	exists, err := afero.DirExists(outputFs, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", dir, err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	var entries []loadedEntry
+	err = afero.Walk(outputFs, dir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !(strings.HasSuffix(filePath, ".json") || strings.HasSuffix(filePath, ".json"+compressedFileSuffix)) {
+			return nil
+		}
+
+		var entry loadedEntry
+		if err := decodeOutputJSONFile(filePath, &entry); err != nil {
+			return err
+		}
+		if entry.TerraformType != "" {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entries in %s: %w", dir, err)
+	}
+
+	return entries, nil
+}
+
+// readOutputJSONFile decodes filePath, or its compressed variant, into v. It reports false when neither exists
+func readOutputJSONFile(filePath string, v interface{}) (bool, error) {
+	for _, candidate := range []string{filePath, filePath + compressedFileSuffix} {
+		exists, err := afero.Exists(outputFs, candidate)
+		if err != nil {
+			return false, fmt.Errorf("failed to stat %s: %w", candidate, err)
+		}
+		if exists {
+			return true, decodeOutputJSONFile(candidate, v)
+		}
+	}
+	return false, nil
+}
+
+// decodeOutputJSONFile decodes a JSON file written by WriteJSONFile, decompressing ".gz" files
+func decodeOutputJSONFile(filePath string, v interface{}) error {
+	data, err := afero.ReadFile(outputFs, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	if strings.HasSuffix(filePath, compressedFileSuffix) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to decompress %s: %w", filePath, err)
+		}
+		defer zr.Close()
+		if data, err = io.ReadAll(zr); err != nil {
+			return fmt.Errorf("failed to decompress %s: %w", filePath, err)
+		}
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package pkg
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadIndexFromDir_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		options OutputOptions
+	}{
+		{name: "plain"},
+		{name: "compressed", options: OutputOptions{Compress: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			sut := createTestTerraformProviderIndex()
+			fs := afero.NewMemMapFs()
+			stub := gostub.Stub(&outputFs, fs)
+			defer stub.Reset()
+			outputDir := "/test/output"
+			require.NoError(t, sut.WriteIndexFilesWithOptions(outputDir, nil, tt.options))
+
+			// Execute
+			loaded, err := LoadIndexFromDir(outputDir)
+
+			// Verify
+			require.NoError(t, err)
+			assert.Equal(t, sut.Version, loaded.Version)
+			assert.Equal(t, sut.Statistics, loaded.Statistics)
+			assert.Equal(t, walkedTypes(t, sut), walkedTypes(t, loaded))
+		})
+	}
+}
+
+func TestLoadIndexFromDir_WithoutMainIndex(t *testing.T) {
+	// Setup - only the per-category directories are left
+	sut := createTestTerraformProviderIndex()
+	sut.Statistics = newProviderStatistics(sut.Services)
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+	require.NoError(t, sut.WriteIndexFiles(outputDir, nil))
+	require.NoError(t, fs.Remove(filepath.Join(outputDir, "terraform-provider-aws-index.json")))
+
+	// Execute
+	loaded, err := LoadIndexFromDir(outputDir)
+
+	// Verify - statistics and type sets are rebuilt from the entry files
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Version)
+	assert.Equal(t, sut.Statistics.TotalResources, loaded.Statistics.TotalResources)
+	assert.Equal(t, sut.Statistics.TotalDataSources, loaded.Statistics.TotalDataSources)
+	assert.Equal(t, sut.Statistics.EphemeralResources, loaded.Statistics.EphemeralResources)
+	require.Len(t, walkedTypes(t, sut), 3)
+	assert.Equal(t, walkedTypes(t, sut), walkedTypes(t, loaded))
+
+	require.Len(t, loaded.Services, 1)
+	assert.Equal(t, "s3", loaded.Services[0].ServiceName)
+	assert.Equal(t, "resourceBucketPolicy", loaded.Services[0].AWSSDKResources["aws_s3_bucket_policy"].FactoryFunction)
+	assert.Equal(t, "bucketResource", loaded.Services[0].AWSFrameworkResources["aws_s3_bucket"].StructType)
+}
+
+func TestLoadIndexFromDirWithOptions_CustomMainIndexFileName(t *testing.T) {
+	// Setup
+	sut := createTestTerraformProviderIndex()
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+	options := OutputOptions{MainIndexFileName: "terraform-provider-awscc-index.json"}
+	require.NoError(t, sut.WriteIndexFilesWithOptions(outputDir, nil, options))
+
+	// Execute
+	loaded, err := LoadIndexFromDirWithOptions(outputDir, options)
+
+	// Verify - the main index is only written under the custom name and listed in the manifest
+	require.NoError(t, err)
+	exists, err := afero.Exists(fs, filepath.Join(outputDir, "terraform-provider-aws-index.json"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.Equal(t, sut.Version, loaded.Version)
+	assert.Equal(t, walkedTypes(t, sut), walkedTypes(t, loaded))
+
+	var manifest map[string]ManifestEntry
+	found, err := readOutputJSONFile(filepath.Join(outputDir, manifestFileName), &manifest)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Contains(t, manifest, "terraform-provider-awscc-index.json")
+}
+
+func TestWriteIndexFilesWithOptions_InvalidMainIndexFileName(t *testing.T) {
+	for _, name := range []string{"nested/index.json", "factory-index.json", manifestFileName, ".."} {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			stub := gostub.Stub(&outputFs, fs)
+			defer stub.Reset()
+
+			err := createTestTerraformProviderIndex().WriteIndexFilesWithOptions("/test/output", nil, OutputOptions{MainIndexFileName: name})
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadIndexFromDir_EmptyDir(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	require.NoError(t, fs.MkdirAll("/test/output", 0755))
+
+	_, err := LoadIndexFromDir("/test/output")
+
+	assert.Error(t, err)
+}
+
+// walkedTypes returns the sorted "category/terraform_type" pairs of every entry in index
+func walkedTypes(t *testing.T, index *TerraformProviderIndex) []string {
+	var types []string
+	require.NoError(t, index.Walk(func(category string, entry interface{}) error {
+		types = append(types, category+"/"+entry.(AWSResource).TerraformType)
+		return nil
+	}))
+	sort.Strings(types)
+	return types
+}
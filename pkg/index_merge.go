@@ -0,0 +1,165 @@
+package pkg
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// Merge combines other into index, e.g. to join the results of partial scans run separately.
+// Services present in both indexes have their resource and method maps merged, with entries from other
+// winning on collision, and partial entries and warnings both indexes report are kept once. Services only in other
+// are copied, so index never shares their maps with other. Resource/data source twins, aliases and statistics are
+// recomputed from the merged services. Indexes for different provider versions are only merged when force is set,
+// in which case the version of index is kept
+func (index *TerraformProviderIndex) Merge(other *TerraformProviderIndex, force bool) error {
+	if other == nil {
+		return nil
+	}
+	if index.Version != other.Version && !force {
+		return fmt.Errorf("cannot merge index for version %s into index for version %s", other.Version, index.Version)
+	}
+
+	serviceIndexes := make(map[string]int, len(index.Services))
+	for i, service := range index.Services {
+		serviceIndexes[service.ServiceName] = i
+	}
+
+	for _, service := range other.Services {
+		if i, exists := serviceIndexes[service.ServiceName]; exists {
+			mergeServiceRegistration(&index.Services[i], service)
+			continue
+		}
+		serviceIndexes[service.ServiceName] = len(index.Services)
+		index.Services = append(index.Services, cloneServiceRegistration(service))
+	}
+
+	index.ScanErrors = mergeScanErrors(index.ScanErrors, other.ScanErrors)
+	index.PartialEntries = appendMissing(index.PartialEntries, other.PartialEntries)
+	sort.Slice(index.PartialEntries, func(i, j int) bool {
+		if index.PartialEntries[i].ServiceName != index.PartialEntries[j].ServiceName {
+			return index.PartialEntries[i].ServiceName < index.PartialEntries[j].ServiceName
+		}
+		return index.PartialEntries[i].TerraformType < index.PartialEntries[j].TerraformType
+	})
+
+	linkResourceTwins(index.Services)
+	index.Aliases = collectTypeAliases(index)
+	index.Statistics = newProviderStatistics(index.Services)
+
+	return nil
+}
+
+// mergeServiceRegistration copies every registration of src into dst, overwriting entries dst already has
+func mergeServiceRegistration(dst *ServiceRegistration, src ServiceRegistration) {
+	if dst.PackagePath == "" {
+		dst.PackagePath = src.PackagePath
+	}
+	if dst.PackageName == "" {
+		dst.PackageName = src.PackageName
+	}
+
+	dst.AWSSDKResources = mergeResourceMap(dst.AWSSDKResources, src.AWSSDKResources)
+	dst.AWSSDKDataSources = mergeResourceMap(dst.AWSSDKDataSources, src.AWSSDKDataSources)
+	dst.AWSFrameworkResources = mergeResourceMap(dst.AWSFrameworkResources, src.AWSFrameworkResources)
+	dst.AWSFrameworkDataSources = mergeResourceMap(dst.AWSFrameworkDataSources, src.AWSFrameworkDataSources)
+	dst.AWSEphemeralResources = mergeResourceMap(dst.AWSEphemeralResources, src.AWSEphemeralResources)
+
+	dst.ResourceTerraformTypes = mergeStringMap(dst.ResourceTerraformTypes, src.ResourceTerraformTypes)
+	dst.DataSourceTerraformTypes = mergeStringMap(dst.DataSourceTerraformTypes, src.DataSourceTerraformTypes)
+	dst.EphemeralTerraformTypes = mergeStringMap(dst.EphemeralTerraformTypes, src.EphemeralTerraformTypes)
+
+	if len(src.ResourceCRUDMethods) > 0 {
+		if dst.ResourceCRUDMethods == nil {
+			dst.ResourceCRUDMethods = make(map[string]*LegacyResourceCRUDFunctions)
+		}
+		maps.Copy(dst.ResourceCRUDMethods, src.ResourceCRUDMethods)
+	}
+	if len(src.DataSourceMethods) > 0 {
+		if dst.DataSourceMethods == nil {
+			dst.DataSourceMethods = make(map[string]*LegacyDataSourceMethods)
+		}
+		maps.Copy(dst.DataSourceMethods, src.DataSourceMethods)
+	}
+	if len(src.FrameworkResourceMethods) > 0 {
+		if dst.FrameworkResourceMethods == nil {
+			dst.FrameworkResourceMethods = make(map[string]*AWSCRUDMethods)
+		}
+		maps.Copy(dst.FrameworkResourceMethods, src.FrameworkResourceMethods)
+	}
+
+	dst.PartialEntries = appendMissing(dst.PartialEntries, src.PartialEntries)
+	dst.Warnings = appendMissing(dst.Warnings, src.Warnings)
+
+	dst.MixedImplementation = dst.isMixedImplementation()
+}
+
+// cloneServiceRegistration copies service along with its maps and slices, the values of the method maps are shared
+func cloneServiceRegistration(service ServiceRegistration) ServiceRegistration {
+	clone := service
+
+	clone.AWSSDKResources = maps.Clone(service.AWSSDKResources)
+	clone.AWSSDKDataSources = maps.Clone(service.AWSSDKDataSources)
+	clone.AWSFrameworkResources = maps.Clone(service.AWSFrameworkResources)
+	clone.AWSFrameworkDataSources = maps.Clone(service.AWSFrameworkDataSources)
+	clone.AWSEphemeralResources = maps.Clone(service.AWSEphemeralResources)
+
+	clone.ResourceTerraformTypes = maps.Clone(service.ResourceTerraformTypes)
+	clone.DataSourceTerraformTypes = maps.Clone(service.DataSourceTerraformTypes)
+	clone.EphemeralTerraformTypes = maps.Clone(service.EphemeralTerraformTypes)
+
+	clone.ResourceCRUDMethods = maps.Clone(service.ResourceCRUDMethods)
+	clone.DataSourceMethods = maps.Clone(service.DataSourceMethods)
+	clone.FrameworkResourceMethods = maps.Clone(service.FrameworkResourceMethods)
+
+	clone.PartialEntries = slices.Clone(service.PartialEntries)
+	clone.Warnings = slices.Clone(service.Warnings)
+	return clone
+}
+
+// appendMissing appends the elements of src that dst does not hold yet, e.g. when both indexes scanned a service
+func appendMissing[T comparable](dst, src []T) []T {
+	for _, element := range src {
+		if !slices.Contains(dst, element) {
+			dst = append(dst, element)
+		}
+	}
+	return dst
+}
+
+// mergeResourceMap copies src into dst, allocating dst if needed, and returns dst
+func mergeResourceMap(dst, src map[string]AWSResource) map[string]AWSResource {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]AWSResource, len(src))
+	}
+	maps.Copy(dst, src)
+	return dst
+}
+
+// mergeStringMap copies src into dst, allocating dst if needed, and returns dst
+func mergeStringMap(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	maps.Copy(dst, src)
+	return dst
+}
+
+// mergeScanErrors combines scan errors from both indexes sorted by service name
+func mergeScanErrors(a, b []ServiceScanError) []ServiceScanError {
+	merged := append(append([]ServiceScanError{}, a...), b...)
+	if len(merged) == 0 {
+		return nil
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].ServiceName < merged[j].ServiceName
+	})
+	return merged
+}
@@ -0,0 +1,122 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMergeTestService(serviceName string, sdkResources, dataSources []string) ServiceRegistration {
+	service := CreateTestServiceRegistration(serviceName)
+	service.PackagePath = "internal/service/" + serviceName
+	for _, terraformType := range sdkResources {
+		service.AWSSDKResources[terraformType] = AWSResource{TerraformType: terraformType, FactoryFunction: "resource" + terraformType, SDKType: "sdk"}
+	}
+	for _, terraformType := range dataSources {
+		service.AWSSDKDataSources[terraformType] = AWSResource{TerraformType: terraformType, FactoryFunction: "dataSource" + terraformType, SDKType: "sdk"}
+	}
+	return service
+}
+
+func TestTerraformProviderIndex_Merge(t *testing.T) {
+	// Setup - s3 overlaps, ec2 and lambda are disjoint
+	s3First := newMergeTestService("s3", []string{"aws_s3_bucket"}, []string{"aws_s3_bucket"})
+	ec2 := newMergeTestService("ec2", []string{"aws_instance"}, nil)
+	index := &TerraformProviderIndex{
+		Version:    "v5.0.0",
+		Services:   []ServiceRegistration{s3First, ec2},
+		Statistics: newProviderStatistics([]ServiceRegistration{s3First, ec2}),
+	}
+
+	s3Second := newMergeTestService("s3", []string{"aws_s3_bucket_policy"}, nil)
+	lambda := newMergeTestService("lambda", []string{"aws_lambda_function"}, []string{"aws_lambda_function"})
+	other := &TerraformProviderIndex{
+		Version:    "v5.0.0",
+		Services:   []ServiceRegistration{s3Second, lambda},
+		ScanErrors: []ServiceScanError{{ServiceName: "broken", Error: "boom"}},
+	}
+
+	// Execute
+	err := index.Merge(other, false)
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, index.Services, 3)
+
+	var s3 ServiceRegistration
+	for _, service := range index.Services {
+		if service.ServiceName == "s3" {
+			s3 = service
+		}
+	}
+	assert.Contains(t, s3.AWSSDKResources, "aws_s3_bucket")
+	assert.Contains(t, s3.AWSSDKResources, "aws_s3_bucket_policy")
+	assert.Contains(t, s3.AWSSDKDataSources, "aws_s3_bucket")
+
+	assert.Equal(t, 3, index.Statistics.ServiceCount)
+	assert.Equal(t, 4, index.Statistics.TotalResources)
+	assert.Equal(t, 2, index.Statistics.TotalDataSources)
+	assert.Equal(t, ServiceStats{Resources: 2, DataSources: 1, SDK: 3}, index.Statistics.PerService["s3"])
+	assert.Equal(t, ServiceStats{Resources: 1, DataSources: 1, SDK: 2}, index.Statistics.PerService["lambda"])
+	assert.Equal(t, []ServiceScanError{{ServiceName: "broken", Error: "boom"}}, index.ScanErrors)
+	assert.Empty(t, index.Statistics.Warnings)
+}
+
+func TestTerraformProviderIndex_Merge_VersionMismatch(t *testing.T) {
+	index := &TerraformProviderIndex{Version: "v5.0.0", Services: []ServiceRegistration{newMergeTestService("s3", []string{"aws_s3_bucket"}, nil)}}
+	other := &TerraformProviderIndex{Version: "v5.1.0", Services: []ServiceRegistration{newMergeTestService("ec2", []string{"aws_instance"}, nil)}}
+
+	err := index.Merge(other, false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot merge index for version v5.1.0")
+	assert.Len(t, index.Services, 1)
+}
+
+func TestTerraformProviderIndex_Merge_Force(t *testing.T) {
+	index := &TerraformProviderIndex{Version: "v5.0.0", Services: []ServiceRegistration{newMergeTestService("s3", []string{"aws_s3_bucket"}, nil)}}
+	other := &TerraformProviderIndex{Version: "v5.1.0", Services: []ServiceRegistration{newMergeTestService("ec2", []string{"aws_instance"}, nil)}}
+
+	err := index.Merge(other, true)
+
+	require.NoError(t, err)
+	assert.Equal(t, "v5.0.0", index.Version)
+	assert.Equal(t, 2, index.Statistics.ServiceCount)
+	assert.Equal(t, 2, index.Statistics.TotalResources)
+}
+
+func TestTerraformProviderIndex_Merge_OverlappingScans(t *testing.T) {
+	// Setup - both indexes scanned s3 and report the same partial entry and warning
+	partial := PartialEntry{ServiceName: "s3", TerraformType: "aws_s3_thing", AnnotationType: AnnotationFrameworkResource, FilePath: "s3/thing.go", Reason: "struct type unresolved"}
+	warning := "unresolved @FrameworkResource aws_s3_thing in s3 (s3/thing.go): no struct with a Schema method found"
+	newScan := func(services ...ServiceRegistration) *TerraformProviderIndex {
+		s3 := newMergeTestService("s3", []string{"aws_s3_bucket"}, nil)
+		s3.PartialEntries = []PartialEntry{partial}
+		s3.Warnings = []string{warning}
+		return &TerraformProviderIndex{
+			Version:        "v5.0.0",
+			Services:       append([]ServiceRegistration{s3}, services...),
+			PartialEntries: []PartialEntry{partial},
+		}
+	}
+	index := newScan()
+	other := newScan(newMergeTestService("lambda", []string{"aws_lambda_function"}, []string{"aws_lambda_function"}))
+
+	// Execute
+	err := index.Merge(other, false)
+
+	// Verify - nothing is reported twice
+	require.NoError(t, err)
+	require.Len(t, index.Services, 2)
+	assert.Equal(t, []PartialEntry{partial}, index.Services[0].PartialEntries)
+	assert.Equal(t, []string{warning}, index.Services[0].Warnings)
+	assert.Equal(t, []PartialEntry{partial}, index.PartialEntries)
+	assert.Equal(t, []string{warning}, index.Statistics.Warnings)
+
+	// The copied lambda service is linked to its twin without touching other, and later changes stay local
+	assert.True(t, index.Services[1].AWSSDKResources["aws_lambda_function"].HasDataSourceTwin)
+	assert.False(t, other.Services[1].AWSSDKResources["aws_lambda_function"].HasDataSourceTwin)
+	index.Services[1].AWSSDKResources["aws_lambda_alias"] = AWSResource{TerraformType: "aws_lambda_alias"}
+	assert.NotContains(t, other.Services[1].AWSSDKResources, "aws_lambda_alias")
+}
@@ -0,0 +1,127 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// EntryMergePatch is an RFC 7386 JSON merge patch turning the content of one entry file of an older index into
+// the content of the same entry in a newer one
+type EntryMergePatch struct {
+	Category      string          `json:"category"` // Walk category, e.g. "resource"
+	TerraformType string          `json:"terraform_type"`
+	Patch         json.RawMessage `json:"patch"` // The whole entry when added, null when removed, changed fields otherwise
+}
+
+// MergePatches returns a merge patch for every entry that was added, removed or changed between the old and the
+// new index, comparing the content each entry's file in the index tree has. Unchanged entries get no patch.
+// Applying each patch to the cached entry of an old tree yields the new tree's entry, so consumers can update
+// incrementally instead of downloading the whole tree. Patches are sorted by category, then terraform type
+func MergePatches(oldIndex, newIndex *TerraformProviderIndex) ([]EntryMergePatch, error) {
+	oldEntries, err := entryDocuments(oldIndex)
+	if err != nil {
+		return nil, err
+	}
+	newEntries, err := entryDocuments(newIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[[2]string]bool)
+	for key := range oldEntries {
+		keys[key] = true
+	}
+	for key := range newEntries {
+		keys[key] = true
+	}
+	sortedKeys := make([][2]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Slice(sortedKeys, func(i, j int) bool {
+		if sortedKeys[i][0] != sortedKeys[j][0] {
+			return sortedKeys[i][0] < sortedKeys[j][0]
+		}
+		return sortedKeys[i][1] < sortedKeys[j][1]
+	})
+
+	var patches []EntryMergePatch
+	for _, key := range sortedKeys {
+		oldEntry, inOld := oldEntries[key]
+		newEntry, inNew := newEntries[key]
+
+		var patch interface{}
+		switch {
+		case !inOld:
+			patch = newEntry
+		case !inNew:
+			patch = nil
+		default:
+			diff := createMergePatch(oldEntry, newEntry)
+			if len(diff) == 0 {
+				continue
+			}
+			patch = diff
+		}
+
+		data, err := json.Marshal(patch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode merge patch for %s %s: %w", key[0], key[1], err)
+		}
+		patches = append(patches, EntryMergePatch{Category: key[0], TerraformType: key[1], Patch: data})
+	}
+
+	return patches, nil
+}
+
+// entryDocuments returns the decoded JSON content of every entry file of the index, keyed by category and
+// terraform type. A terraform type registered twice within a category keeps its first entry in Walk order
+func entryDocuments(index *TerraformProviderIndex) (map[[2]string]map[string]interface{}, error) {
+	documents := make(map[[2]string]map[string]interface{})
+	err := index.walkEntries(func(category string, framework bool, service *ServiceRegistration, resource AWSResource) error {
+		key := [2]string{category, resource.TerraformType}
+		if _, exists := documents[key]; exists {
+			return nil
+		}
+
+		data, err := json.Marshal(convertWalkedEntry(category, framework, service, resource))
+		if err != nil {
+			return fmt.Errorf("failed to encode %s %s: %w", category, resource.TerraformType, err)
+		}
+		var document map[string]interface{}
+		if err := json.Unmarshal(data, &document); err != nil {
+			return fmt.Errorf("failed to decode %s %s: %w", category, resource.TerraformType, err)
+		}
+		documents[key] = document
+		return nil
+	})
+	return documents, err
+}
+
+// createMergePatch returns the RFC 7386 merge patch turning oldDoc into newDoc: removed members become null,
+// objects present in both are patched recursively and any other changed value is replaced whole
+func createMergePatch(oldDoc, newDoc map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{})
+	for name := range oldDoc {
+		if _, exists := newDoc[name]; !exists {
+			patch[name] = nil
+		}
+	}
+	for name, newValue := range newDoc {
+		oldValue, exists := oldDoc[name]
+		if exists && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		oldObject, oldIsObject := oldValue.(map[string]interface{})
+		newObject, newIsObject := newValue.(map[string]interface{})
+		if exists && oldIsObject && newIsObject {
+			patch[name] = createMergePatch(oldObject, newObject)
+			continue
+		}
+		patch[name] = newValue
+	}
+	return patch
+}
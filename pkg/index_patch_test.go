@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePatches_ApplyToOldIndexYieldsNewIndex(t *testing.T) {
+	// Setup - the new index renames the bucket, documents the policy, drops the data source and adds a resource
+	// and lets the region of an ephemeral resource be overridden
+	oldIndex := createTestTerraformProviderIndex()
+	newIndex := createTestTerraformProviderIndex()
+	for i, index := range []*TerraformProviderIndex{oldIndex, newIndex} {
+		kms := CreateTestServiceRegistration("kms")
+		kms.AWSEphemeralResources["aws_kms_secrets"] = AWSResource{TerraformType: "aws_kms_secrets", SDKType: "ephemeral",
+			Region: &AWSRegionConfig{Global: true, OverrideEnabled: i == 1}}
+		index.Services = append(index.Services, kms)
+	}
+	s3 := &newIndex.Services[0]
+	bucket := s3.AWSFrameworkResources["aws_s3_bucket"]
+	bucket.Name = "Bucket (Renamed)"
+	s3.AWSFrameworkResources["aws_s3_bucket"] = bucket
+	policy := s3.AWSSDKResources["aws_s3_bucket_policy"]
+	policy.Description = "Attaches a policy to a bucket"
+	s3.AWSSDKResources["aws_s3_bucket_policy"] = policy
+	delete(s3.AWSSDKDataSources, "aws_s3_bucket")
+	s3.AWSSDKResources["aws_s3_object"] = AWSResource{TerraformType: "aws_s3_object", FactoryFunction: "resourceObject", SDKType: "sdk"}
+
+	// Execute
+	patches, err := MergePatches(oldIndex, newIndex)
+	require.NoError(t, err)
+
+	// Verify - one patch per changed entry, in category then terraform type order
+	var keys []string
+	for _, patch := range patches {
+		keys = append(keys, patch.Category+"/"+patch.TerraformType)
+	}
+	assert.Equal(t, []string{"datasource/aws_s3_bucket", "ephemeral/aws_kms_secrets", "resource/aws_s3_bucket", "resource/aws_s3_bucket_policy", "resource/aws_s3_object"}, keys)
+	assert.JSONEq(t, "null", string(patches[0].Patch))
+	assert.JSONEq(t, `{"region": {"override_enabled": true}}`, string(patches[1].Patch))
+	assert.JSONEq(t, `{"display_name": "Bucket (Renamed)"}`, string(patches[2].Patch))
+
+	// Applying the patches to the old entries yields the new entries
+	entries, err := entryDocuments(oldIndex)
+	require.NoError(t, err)
+	for _, patch := range patches {
+		key := [2]string{patch.Category, patch.TerraformType}
+		var document interface{}
+		require.NoError(t, json.Unmarshal(patch.Patch, &document))
+		if patched, ok := applyMergePatch(entries[key], document).(map[string]interface{}); ok {
+			entries[key] = patched
+		} else {
+			delete(entries, key)
+		}
+	}
+	expected, err := entryDocuments(newIndex)
+	require.NoError(t, err)
+	assert.Equal(t, expected, entries)
+}
+
+func TestMergePatches_UnchangedIndex(t *testing.T) {
+	patches, err := MergePatches(createTestTerraformProviderIndex(), createTestTerraformProviderIndex())
+	require.NoError(t, err)
+	assert.Empty(t, patches)
+}
+
+// applyMergePatch applies an RFC 7386 merge patch to target
+func applyMergePatch(target map[string]interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	if target == nil {
+		target = make(map[string]interface{})
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(target, name)
+			continue
+		}
+		current, _ := target[name].(map[string]interface{})
+		target[name] = applyMergePatch(current, value)
+	}
+	return target
+}
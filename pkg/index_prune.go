@@ -0,0 +1,16 @@
+package pkg
+
+// Prune removes services that register no resource, data source or ephemeral resource, e.g. after filtering
+// an index, and updates the service count and per-service statistics to match
+func (index *TerraformProviderIndex) Prune() {
+	services := index.Services[:0]
+	for _, service := range index.Services {
+		if !service.hasRegistrations() {
+			delete(index.Statistics.PerService, service.ServiceName)
+			continue
+		}
+		services = append(services, service)
+	}
+	index.Services = services
+	index.Statistics.ServiceCount = len(index.Services)
+}
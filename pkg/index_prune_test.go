@@ -0,0 +1,38 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerraformProviderIndex_Prune(t *testing.T) {
+	// Setup - s3 and lambda keep entries, ec2 and kms were emptied
+	s3 := newMergeTestService("s3", []string{"aws_s3_bucket"}, nil)
+	ec2 := newMergeTestService("ec2", nil, nil)
+	lambda := newMergeTestService("lambda", nil, []string{"aws_lambda_function"})
+	kms := newMergeTestService("kms", nil, nil)
+	services := []ServiceRegistration{s3, ec2, lambda, kms}
+	index := &TerraformProviderIndex{
+		Version:    "v5.0.0",
+		Services:   services,
+		Statistics: newProviderStatistics(services),
+	}
+	assert.Equal(t, 4, index.Statistics.ServiceCount)
+
+	// Execute
+	index.Prune()
+
+	// Verify
+	var names []string
+	for _, service := range index.Services {
+		names = append(names, service.ServiceName)
+	}
+	assert.Equal(t, []string{"s3", "lambda"}, names)
+	assert.Equal(t, 2, index.Statistics.ServiceCount)
+	assert.Equal(t, 1, index.Statistics.TotalResources)
+	assert.Equal(t, 1, index.Statistics.TotalDataSources)
+	assert.Contains(t, index.Statistics.PerService, "s3")
+	assert.NotContains(t, index.Statistics.PerService, "ec2")
+	assert.NotContains(t, index.Statistics.PerService, "kms")
+}
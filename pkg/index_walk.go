@@ -0,0 +1,91 @@
+package pkg
+
+import "sort"
+
+// Walk categories passed to the Walk callback
+const (
+	WalkCategoryResource   = "resource"
+	WalkCategoryDataSource = "datasource"
+	WalkCategoryEphemeral  = "ephemeral"
+)
+
+// walkCategories lists the walk categories in Walk order
+var walkCategories = []string{WalkCategoryResource, WalkCategoryDataSource, WalkCategoryEphemeral}
+
+// Walk visits every SDK and framework resource, data source and ephemeral resource in the index and calls fn
+// with its category and the AWSResource entry. Services are visited in name order; within a service resources
+// come first, then data sources, then ephemeral resources, each SDK before framework and sorted by terraform type.
+// Walk stops and returns the first error returned by fn
+func (index *TerraformProviderIndex) Walk(fn func(category string, entry interface{}) error) error {
+	return index.walkEntries(func(category string, _ bool, _ *ServiceRegistration, resource AWSResource) error {
+		return fn(category, resource)
+	})
+}
+
+// walkEntries implements Walk, additionally passing the owning service and whether the entry
+// comes from a framework map so callers can convert it like the index file writers do
+func (index *TerraformProviderIndex) walkEntries(fn func(category string, framework bool, service *ServiceRegistration, resource AWSResource) error) error {
+	services := make([]*ServiceRegistration, 0, len(index.Services))
+	for i := range index.Services {
+		services = append(services, &index.Services[i])
+	}
+	sort.SliceStable(services, func(i, j int) bool {
+		return services[i].ServiceName < services[j].ServiceName
+	})
+
+	for _, service := range services {
+		if err := walkServiceEntries(service, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// walkServiceEntries visits the entries of a single service in Walk order
+func walkServiceEntries(service *ServiceRegistration, fn func(category string, framework bool, service *ServiceRegistration, resource AWSResource) error) error {
+	categories := []struct {
+		category  string
+		framework bool
+		resources map[string]AWSResource
+	}{
+		{WalkCategoryResource, false, service.AWSSDKResources},
+		{WalkCategoryResource, true, service.AWSFrameworkResources},
+		{WalkCategoryDataSource, false, service.AWSSDKDataSources},
+		{WalkCategoryDataSource, true, service.AWSFrameworkDataSources},
+		{WalkCategoryEphemeral, true, service.AWSEphemeralResources},
+	}
+
+	for _, c := range categories {
+		terraformTypes := make([]string, 0, len(c.resources))
+		for terraformType := range c.resources {
+			terraformTypes = append(terraformTypes, terraformType)
+		}
+		sort.Strings(terraformTypes)
+
+		for _, terraformType := range terraformTypes {
+			if err := fn(c.category, c.framework, service, c.resources[terraformType]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// convertWalkedEntry converts a walked entry into the TerraformResource, TerraformDataSource or TerraformEphemeral
+// written to its index file
+func convertWalkedEntry(category string, framework bool, service *ServiceRegistration, resource AWSResource) interface{} {
+	switch {
+	case category == WalkCategoryResource && framework:
+		return NewTerraformResourceFromAWSFramework(resource, *service, service.Package)
+	case category == WalkCategoryResource:
+		return NewTerraformResourceFromAWSSDK(resource, *service)
+	case category == WalkCategoryDataSource && framework:
+		return NewTerraformDataSourceFromAWSFramework(resource, *service)
+	case category == WalkCategoryDataSource:
+		return NewTerraformDataSourceFromAWSSDK(resource, *service)
+	default:
+		return NewTerraformEphemeralFromAWS(resource, *service)
+	}
+}
@@ -0,0 +1,58 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_Walk(t *testing.T) {
+	// Setup - add a second service listed before s3 alphabetically
+	index := CreateTestTerraformProviderIndex()
+	ec2 := CreateTestServiceRegistration("ec2")
+	ec2.AWSSDKResources["aws_instance"] = CreateTestAWSResourceInfo("sdk_resource", "aws_instance", "resourceInstance", "Instance")
+	ec2.AWSEphemeralResources["aws_ec2_key"] = CreateTestAWSResourceInfo("ephemeral_resource", "aws_ec2_key", "newKeyEphemeralResource", "Key")
+	index.Services = append(index.Services, ec2)
+
+	var visited []string
+	counts := make(map[string]int)
+
+	// Execute
+	err := index.Walk(func(category string, entry interface{}) error {
+		resource, ok := entry.(AWSResource)
+		require.True(t, ok)
+		visited = append(visited, category+":"+resource.TerraformType+":"+resource.FactoryFunction)
+		counts[category]++
+		return nil
+	})
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"resource:aws_instance:resourceInstance",
+		"ephemeral:aws_ec2_key:newKeyEphemeralResource",
+		"resource:aws_s3_bucket_policy:resourceBucketPolicy",
+		"resource:aws_s3_bucket:newBucketResource",
+		"datasource:aws_s3_bucket:dataSourceS3Bucket",
+	}, visited)
+	assert.Equal(t, map[string]int{WalkCategoryResource: 3, WalkCategoryDataSource: 1, WalkCategoryEphemeral: 1}, counts)
+}
+
+func TestTerraformProviderIndex_Walk_StopsOnError(t *testing.T) {
+	index := CreateTestTerraformProviderIndex()
+	errStop := errors.New("stop")
+
+	visits := 0
+	err := index.Walk(func(category string, entry interface{}) error {
+		visits++
+		if visits == 2 {
+			return errStop
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 2, visits)
+}
@@ -0,0 +1,30 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// jsonLine is a single line emitted by WriteJSONLines
+type jsonLine struct {
+	Category string      `json:"category"` // "resource", "datasource" or "ephemeral"
+	Entry    interface{} `json:"entry"`    // The same content as the entry's file in the index tree
+}
+
+// WriteJSONLines writes one compact JSON object per line for every resource, data source and ephemeral
+// resource, in Walk order. Each line holds the entry's category and the content of its index file.
+// It writes to w directly and does not use the output filesystem
+func (index *TerraformProviderIndex) WriteJSONLines(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+
+	return index.walkEntries(func(category string, framework bool, service *ServiceRegistration, resource AWSResource) error {
+		entry := convertWalkedEntry(category, framework, service, resource)
+
+		// Encode writes a trailing newline after each compact object
+		if err := encoder.Encode(jsonLine{Category: category, Entry: entry}); err != nil {
+			return fmt.Errorf("failed to write JSON line for %s %s: %w", category, resource.TerraformType, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,57 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_WriteJSONLines(t *testing.T) {
+	// Setup
+	index := CreateTestTerraformProviderIndex()
+	kms := CreateTestServiceRegistration("kms")
+	kms.AWSEphemeralResources["aws_kms_secrets"] = CreateTestAWSResourceInfo("ephemeral_resource", "aws_kms_secrets", "newSecretsEphemeralResource", "Secrets")
+	index.Services = append(index.Services, kms)
+	var buf bytes.Buffer
+
+	// Execute
+	err := index.WriteJSONLines(&buf)
+
+	// Verify
+	require.NoError(t, err)
+
+	var lines []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, lines, 4) // kms ephemeral, s3 SDK resource, s3 framework resource, s3 SDK data source
+
+	var ephemeral struct {
+		Category string             `json:"category"`
+		Entry    TerraformEphemeral `json:"entry"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &ephemeral))
+	assert.Equal(t, WalkCategoryEphemeral, ephemeral.Category)
+	assert.Equal(t, "aws_kms_secrets", ephemeral.Entry.TerraformType)
+
+	var resource struct {
+		Category string            `json:"category"`
+		Entry    TerraformResource `json:"entry"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &resource))
+	assert.Equal(t, WalkCategoryResource, resource.Category)
+	assert.Equal(t, "aws_s3_bucket_policy", resource.Entry.TerraformType)
+	assert.Equal(t, "func.resourceBucketPolicyCreate.goindex", resource.Entry.CreateIndex)
+
+	// Output is deterministic
+	var again bytes.Buffer
+	require.NoError(t, index.WriteJSONLines(&again))
+	assert.Equal(t, strings.Join(lines, "\n")+"\n", again.String())
+}
@@ -6,4 +6,18 @@ type LegacyResourceCRUDFunctions struct {
 	ReadMethod   string `json:"read_method,omitempty"`   // "keyVaultReadFunc"
 	UpdateMethod string `json:"update_method,omitempty"` // "keyVaultUpdateFunc"
 	DeleteMethod string `json:"delete_method,omitempty"` // "keyVaultDeleteFunc"
+
+	// CRUD method types intentionally set to a schema noop such as schema.NoopContext, e.g. ["read"]
+	// These have no method index, this distinguishes them from methods that are simply absent
+	NoopMethods []string `json:"noop_methods,omitempty"`
+}
+
+// NoopRead reports whether the read method is an intentional noop
+func (c *LegacyResourceCRUDFunctions) NoopRead() bool {
+	for _, methodType := range c.NoopMethods {
+		if methodType == "read" {
+			return true
+		}
+	}
+	return false
 }
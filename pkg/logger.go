@@ -0,0 +1,18 @@
+package pkg
+
+// Logger receives diagnostic messages emitted while scanning a provider
+// Implementations must be safe for concurrent use because services are scanned in parallel
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// noopLogger discards all messages, it is used when no Logger is configured
+type noopLogger struct{}
+
+func (noopLogger) Debugf(string, ...interface{}) {}
+func (noopLogger) Infof(string, ...interface{})  {}
+func (noopLogger) Warnf(string, ...interface{})  {}
+func (noopLogger) Errorf(string, ...interface{}) {}
@@ -0,0 +1,103 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+const manifestFileName = "manifest.json"
+
+// ManifestEntry describes a single generated file in the output tree
+type ManifestEntry struct {
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// BuildManifest computes checksums for the files produced by WriteIndexFiles: the top-level index files
+// plus everything under the category directories, resources/, datasources/ and ephemeral/ by default, services/
+// and attributes/.
+// Keys are slash-separated paths relative to outputDir
+func (index *TerraformProviderIndex) BuildManifest(outputDir string) (map[string]ManifestEntry, error) {
+	manifest := make(map[string]ManifestEntry)
+
+	for _, name := range []string{index.outputOptions.mainIndexFileName(), "factory-index.json", "statistics.json"} {
+		filePath := filepath.Join(outputDir, index.outputFileName(name))
+		exists, err := afero.Exists(outputFs, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", filePath, err)
+		}
+		if !exists {
+			continue
+		}
+		if err := addManifestEntry(manifest, outputDir, filePath); err != nil {
+			return nil, err
+		}
+	}
+
+	dirs := []string{
+		index.outputOptions.categoryDir(WalkCategoryResource),
+		index.outputOptions.categoryDir(WalkCategoryDataSource),
+		index.outputOptions.categoryDir(WalkCategoryEphemeral),
+		serviceFilesDir,
+		attributeSchemasDir,
+	}
+	for _, dir := range dirs {
+		root := filepath.Join(outputDir, dir)
+		exists, err := afero.DirExists(outputFs, root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", root, err)
+		}
+		if !exists {
+			continue
+		}
+		err = afero.Walk(outputFs, root, func(filePath string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			return addManifestEntry(manifest, outputDir, filePath)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+		}
+	}
+
+	return manifest, nil
+}
+
+// WriteManifest writes manifest.json mapping each generated file to its SHA-256 checksum and size.
+// It must run after all other index files have been written
+func (index *TerraformProviderIndex) WriteManifest(outputDir string) error {
+	manifest, err := index.BuildManifest(outputDir)
+	if err != nil {
+		return err
+	}
+	return index.WriteJSONFile(filepath.Join(outputDir, manifestFileName), manifest)
+}
+
+// addManifestEntry hashes filePath and records it under its path relative to outputDir
+func addManifestEntry(manifest map[string]ManifestEntry, outputDir, filePath string) error {
+	data, err := afero.ReadFile(outputFs, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	relPath, err := filepath.Rel(outputDir, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to compute relative path for %s: %w", filePath, err)
+	}
+
+	sum := sha256.Sum256(data)
+	manifest[filepath.ToSlash(relPath)] = ManifestEntry{
+		SHA256: hex.EncodeToString(sum[:]),
+		Size:   int64(len(data)),
+	}
+	return nil
+}
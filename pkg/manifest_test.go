@@ -0,0 +1,75 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_WriteIndexFiles_WritesManifest(t *testing.T) {
+	// Setup
+	sut := createTestTerraformProviderIndex()
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+
+	// Execute
+	err := sut.WriteIndexFiles(outputDir, nil)
+	require.NoError(t, err)
+
+	// Verify
+	manifestData, err := afero.ReadFile(fs, filepath.Join(outputDir, "manifest.json"))
+	require.NoError(t, err)
+	var manifest map[string]ManifestEntry
+	require.NoError(t, json.Unmarshal(manifestData, &manifest))
+
+	assert.Contains(t, manifest, "terraform-provider-aws-index.json")
+	assert.Contains(t, manifest, "factory-index.json")
+	assert.Contains(t, manifest, "resources/aws_s3_bucket_policy.json")
+	assert.NotContains(t, manifest, "manifest.json")
+
+	for relPath, entry := range manifest {
+		data, err := afero.ReadFile(fs, filepath.Join(outputDir, filepath.FromSlash(relPath)))
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), entry.Size, relPath)
+		assert.Equal(t, sha256Hex(data), entry.SHA256, relPath)
+	}
+}
+
+func TestTerraformProviderIndex_WriteManifest_DetectsCorruption(t *testing.T) {
+	// Setup
+	sut := createTestTerraformProviderIndex()
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+	require.NoError(t, sut.WriteIndexFiles(outputDir, nil))
+
+	manifestData, err := afero.ReadFile(fs, filepath.Join(outputDir, "manifest.json"))
+	require.NoError(t, err)
+	var manifest map[string]ManifestEntry
+	require.NoError(t, json.Unmarshal(manifestData, &manifest))
+
+	// Execute - corrupt one generated file after the manifest was written
+	resourcePath := filepath.Join(outputDir, "resources", "aws_s3_bucket_policy.json")
+	require.NoError(t, afero.WriteFile(fs, resourcePath, []byte(`{"corrupted": true}`), 0644))
+
+	// Verify
+	current, err := sut.BuildManifest(outputDir)
+	require.NoError(t, err)
+	assert.NotEqual(t, manifest["resources/aws_s3_bucket_policy.json"].SHA256, current["resources/aws_s3_bucket_policy.json"].SHA256)
+	assert.Equal(t, manifest["terraform-provider-aws-index.json"], current["terraform-provider-aws-index.json"])
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,165 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// mapRegistrationMethods lists the functions of older service packages that register SDK resources and data
+// sources through a map literal instead of annotations, mapped to the annotation type they stand in for, e.g.
+//
+//	func SupportedResources() map[string]*schema.Resource {
+//		return map[string]*schema.Resource{
+//			"aws_widget": resourceWidget(),
+//		}
+//	}
+var mapRegistrationMethods = map[string]AnnotationType{
+	"SupportedResources":   AnnotationSDKResource,
+	"SupportedDataSources": AnnotationSDKDataSource,
+}
+
+// findMapRegistrations collects the map-style registrations of the package, keyed by the factory function
+// called for each terraform type. Either a function or a method of the given name is recognised, as is one that
+// returns the map built by another method of its receiver, see delegatedRegistrationDecl
+func findMapRegistrations(packageInfo *gophon.PackageInfo) map[string][]basicAnnotation {
+	registrations := make(map[string][]basicAnnotation)
+	for _, fileInfo := range packageInfo.Files {
+		if fileInfo == nil || fileInfo.File == nil {
+			continue
+		}
+		for _, decl := range fileInfo.File.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+			annotationType, ok := mapRegistrationMethods[funcDecl.Name.Name]
+			if !ok {
+				continue
+			}
+			entries := mapRegistrationEntries(funcDecl.Body)
+			if len(entries) == 0 {
+				if delegate := delegatedRegistrationDecl(packageInfo, funcDecl); delegate != nil {
+					entries = mapRegistrationEntries(delegate.Body)
+				}
+			}
+			for _, entry := range entries {
+				registrations[entry.FunctionName] = append(registrations[entry.FunctionName], basicAnnotation{
+					Type:               annotationType,
+					TerraformType:      entry.TerraformType,
+					FunctionName:       entry.FunctionName,
+					RegistrationMethod: funcDecl.Name.Name,
+				})
+			}
+		}
+	}
+	return registrations
+}
+
+// delegatedRegistrationDecl returns the declaration a registration function delegates to by returning its result,
+// e.g. `return p.supportedResources()` on the same receiver or `return supportedResources()` from a function.
+// Only one level of delegation is followed, nil when funcDecl returns anything else or the callee is not declared
+func delegatedRegistrationDecl(packageInfo *gophon.PackageInfo, funcDecl *ast.FuncDecl) *ast.FuncDecl {
+	if len(funcDecl.Body.List) == 0 {
+		return nil
+	}
+	returnStmt, ok := funcDecl.Body.List[len(funcDecl.Body.List)-1].(*ast.ReturnStmt)
+	if !ok || len(returnStmt.Results) != 1 {
+		return nil
+	}
+	call, ok := returnStmt.Results[0].(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+
+	var delegate *ast.FuncDecl
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		receiver, ok := fun.X.(*ast.Ident)
+		if !ok || funcDecl.Recv == nil || receiver.Name != receiverVarName(funcDecl) {
+			return nil
+		}
+		delegate = findMethodDecl(packageInfo, receiverTypeName(funcDecl), fun.Sel.Name)
+	case *ast.Ident:
+		if funcDecl.Recv != nil {
+			return nil
+		}
+		delegate = findFuncDecl(packageInfo, fun.Name)
+	}
+	if delegate == nil || delegate == funcDecl || delegate.Body == nil {
+		return nil
+	}
+	return delegate
+}
+
+// mapRegistrationEntry is a single `"aws_widget": resourceWidget()` element of a registration map
+type mapRegistrationEntry struct {
+	TerraformType string
+	FunctionName  string
+}
+
+// mapRegistrationEntries returns the entries of the map literals in body keyed by a string literal and valued by
+// a call to a package function, in source order. Other elements, e.g. resources built inline, are skipped
+func mapRegistrationEntries(body *ast.BlockStmt) []mapRegistrationEntry {
+	var entries []mapRegistrationEntry
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		compositeLit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		if _, ok := compositeLit.Type.(*ast.MapType); !ok {
+			return true
+		}
+		for _, elt := range compositeLit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.BasicLit)
+			if !ok || key.Kind != token.STRING {
+				continue
+			}
+			terraformType, err := strconv.Unquote(key.Value)
+			if err != nil || terraformType == "" {
+				continue
+			}
+			call, ok := kv.Value.(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			fun, ok := call.Fun.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			entries = append(entries, mapRegistrationEntry{TerraformType: terraformType, FunctionName: fun.Name})
+		}
+		return false
+	})
+	return entries
+}
+
+// mapRegistrationsInFile returns the map-style registrations whose factory function is declared in file,
+// attached to the declaration so the factory can be inspected like an annotated one
+func mapRegistrationsInFile(file *ast.File, registrations map[string][]basicAnnotation) []basicAnnotation {
+	if len(registrations) == 0 {
+		return nil
+	}
+
+	var annotations []basicAnnotation
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv != nil {
+			continue
+		}
+		for _, annotation := range registrations[funcDecl.Name.Name] {
+			annotation.Decl = funcDecl
+			annotations = append(annotations, annotation)
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,195 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAWSServiceFileWithAnnotations_MapRegistrations(t *testing.T) {
+	// Setup - an older service package listing its resources in maps, next to one annotated resource
+	fs := afero.NewMemMapFs()
+	files := map[string]string{
+		"/services/widget/service_package.go": `package widget
+
+func SupportedResources() map[string]*schema.Resource {
+	return map[string]*schema.Resource{
+		"aws_widget":        resourceWidget(),
+		"aws_widget_policy": resourceWidgetPolicy(),
+		"aws_widget_inline": &schema.Resource{},
+	}
+}
+
+func (p *servicePackage) SupportedDataSources() map[string]*schema.Resource {
+	dataSources := map[string]*schema.Resource{
+		"aws_widget": dataSourceWidget(),
+	}
+	return dataSources
+}
+`,
+		"/services/widget/widget.go": `package widget
+
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceWidgetCreate,
+		ReadWithoutTimeout:   resourceWidgetRead,
+		DeleteWithoutTimeout: resourceWidgetDelete,
+	}
+}
+
+func dataSourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceWidgetRead,
+	}
+}
+`,
+		"/services/widget/widget_policy.go": `package widget
+
+// @SDKResource("aws_widget_policy", name="Widget Policy")
+func resourceWidgetPolicy() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetPolicyRead,
+	}
+}
+`,
+	}
+	for filePath, content := range files {
+		require.NoError(t, afero.WriteFile(fs, filePath, []byte(content), 0644))
+	}
+	packageInfo, err := scanPackageFromFs(fs, "/services/widget", "example.com/provider")
+	require.NoError(t, err)
+	serviceReg := newServiceRegistration(packageInfo, "widget")
+
+	// Execute
+	require.NoError(t, parseAWSServiceFileWithAnnotations(packageInfo, &serviceReg))
+
+	// Verify - inline resources cannot be resolved to a factory and are skipped
+	assert.Len(t, serviceReg.AWSSDKResources, 2)
+	widget := serviceReg.AWSSDKResources["aws_widget"]
+	assert.Equal(t, "SupportedResources", widget.RegistrationMethod)
+	assert.Equal(t, "/services/widget/widget.go", widget.RegistrationFile)
+	assert.Empty(t, widget.RawAnnotation)
+	assert.Equal(t, "resourceWidgetCreate", serviceReg.ResourceCRUDMethods["aws_widget"].CreateMethod)
+	assert.Equal(t, "resourceWidgetDelete", serviceReg.ResourceCRUDMethods["aws_widget"].DeleteMethod)
+
+	// An annotation wins over the map entry of the same terraform type
+	policy := serviceReg.AWSSDKResources["aws_widget_policy"]
+	assert.Empty(t, policy.RegistrationMethod)
+	assert.Equal(t, "Widget Policy", policy.Name)
+
+	require.Contains(t, serviceReg.AWSSDKDataSources, "aws_widget")
+	assert.Equal(t, "SupportedDataSources", serviceReg.AWSSDKDataSources["aws_widget"].RegistrationMethod)
+	assert.Equal(t, "dataSourceWidget", serviceReg.AWSSDKDataSources["aws_widget"].FactoryFunction)
+	assert.Equal(t, "dataSourceWidgetRead", serviceReg.DataSourceMethods["aws_widget"].ReadMethod)
+
+	resource := NewTerraformResourceFromAWSSDK(widget, serviceReg)
+	assert.Equal(t, "SupportedResources", resource.RegistrationMethod)
+	assert.Equal(t, "Widget", resource.DisplayName)
+	dataSource := NewTerraformDataSourceFromAWSSDK(serviceReg.AWSSDKDataSources["aws_widget"], serviceReg)
+	assert.Equal(t, "SupportedDataSources", dataSource.RegistrationMethod)
+}
+
+func TestParseAWSServiceFileWithAnnotations_DelegatedMapRegistrations(t *testing.T) {
+	// Setup - the registration methods return maps built by other methods of the service package
+	fs := afero.NewMemMapFs()
+	files := map[string]string{
+		"/services/widget/service_package.go": `package widget
+
+func (p *servicePackage) SupportedResources() map[string]*schema.Resource {
+	return p.sdkResources()
+}
+
+func (p *servicePackage) SupportedDataSources() map[string]*schema.Resource {
+	return p.dataSources()
+}
+`,
+		"/services/widget/registrations.go": `package widget
+
+func (p *servicePackage) sdkResources() map[string]*schema.Resource {
+	return map[string]*schema.Resource{
+		"aws_widget": resourceWidget(),
+	}
+}
+
+// Delegation is only followed one level deep
+func (p *servicePackage) dataSources() map[string]*schema.Resource {
+	return p.legacyDataSources()
+}
+
+func (p *servicePackage) legacyDataSources() map[string]*schema.Resource {
+	return map[string]*schema.Resource{
+		"aws_widget": dataSourceWidget(),
+	}
+}
+`,
+		"/services/widget/widget.go": `package widget
+
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+
+func dataSourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceWidgetRead,
+	}
+}
+`,
+	}
+	for filePath, content := range files {
+		require.NoError(t, afero.WriteFile(fs, filePath, []byte(content), 0644))
+	}
+	packageInfo, err := scanPackageFromFs(fs, "/services/widget", "example.com/provider")
+	require.NoError(t, err)
+	serviceReg := newServiceRegistration(packageInfo, "widget")
+
+	// Execute
+	require.NoError(t, parseAWSServiceFileWithAnnotations(packageInfo, &serviceReg))
+
+	// Verify - the entry is attributed to the exported registration method
+	require.Contains(t, serviceReg.AWSSDKResources, "aws_widget")
+	widget := serviceReg.AWSSDKResources["aws_widget"]
+	assert.Equal(t, "SupportedResources", widget.RegistrationMethod)
+	assert.Equal(t, "resourceWidget", widget.FactoryFunction)
+	assert.Equal(t, "resourceWidgetRead", serviceReg.ResourceCRUDMethods["aws_widget"].ReadMethod)
+	assert.Empty(t, serviceReg.AWSSDKDataSources)
+}
+
+func TestParseAWSServiceFileWithAnnotations_DanglingMapRegistration(t *testing.T) {
+	// Setup - a map entry calling a factory that was renamed without updating the registration
+	packageInfo := createMockPackageInfoFromSource(t, "/services/widget/widget.go", "example.com/provider/widget", `package widget
+
+func SupportedResources() map[string]*schema.Resource {
+	return map[string]*schema.Resource{
+		"aws_widget":        resourceWidget(),
+		"aws_widget_policy": resourceWidgetPolicyy(),
+	}
+}
+
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+
+func resourceWidgetPolicy() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetPolicyRead,
+	}
+}
+`)
+	serviceReg := CreateTestServiceRegistration("widget")
+
+	// Execute
+	require.NoError(t, parseAWSServiceFileWithAnnotations(packageInfo, &serviceReg))
+
+	// Verify
+	assert.Contains(t, serviceReg.AWSSDKResources, "aws_widget")
+	assert.NotContains(t, serviceReg.AWSSDKResources, "aws_widget_policy")
+	assert.Equal(t, []string{
+		"dangling factory function resourceWidgetPolicyy of SupportedResources aws_widget_policy in widget: not declared in the package",
+	}, serviceReg.Warnings)
+}
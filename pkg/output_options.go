@@ -0,0 +1,288 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// OutputOptions controls how WriteIndexFilesWithOptions writes index files
+type OutputOptions struct {
+	Compress     bool // Write gzip-compressed ".json.gz" files with the same JSON content
+	Compact      bool // Write JSON without indentation, the default indents with two spaces
+	ServiceFiles bool // Also write one services/<service_name>.json file per service, see WriteServiceFiles
+
+	// Atomic writes everything into a temporary sibling directory that replaces the output directory only once
+	// all files have been written, so a failed or interrupted run never leaves a half-generated index behind
+	Atomic bool
+
+	// Transient write errors such as too many open files are retried with exponential backoff
+	MaxRetries     int           // Retries after the first attempt, 0 uses the default and a negative value disables retrying
+	RetryBaseDelay time.Duration // Delay before the first retry, doubled for each further retry, 0 uses the default
+
+	// Services whose resource files are written per parallel batch, bounding the write tasks held at once.
+	// 0 uses the default and a negative value writes all services in a single batch
+	ServiceBatchSize int
+
+	// FileNameTemplate is a text/template rendered with FileNameData to name each resource, data source and
+	// ephemeral file, e.g. "{{.Service}}_{{.TerraformType}}.json". Empty keeps "<terraform_type>.json".
+	// Attribute schema files are named like the resource files they describe.
+	// The result must be a clean relative path, slashes place the file in a subdirectory of its category directory
+	FileNameTemplate string
+
+	// SDKTypePrefix is prepended to the sdk_type of every entry file and service file, e.g. "my" writes
+	// "myaws_sdk" instead of "aws_sdk", so indexes of several providers can be told apart once merged.
+	// The main index keeps its unprefixed SDK types, they classify the entries
+	SDKTypePrefix string
+
+	// RelatedFunctions adds the indexes of the unexported package functions an SDK or framework factory references,
+	// e.g. expand and flatten helpers, to resource and data source files as related_functions
+	RelatedFunctions bool
+
+	// AttributeSchemas also writes one attributes/<terraform_type>.json file per resource describing its schema
+	// attributes with their types, flags and nesting, see WriteAttributeSchemas
+	AttributeSchemas bool
+
+	// Category directory names relative to the output directory, empty keeps "resources", "datasources" and
+	// "ephemeral". The manifest and write plans follow the configured names
+	ResourcesDir   string
+	DataSourcesDir string
+	EphemeralDir   string
+
+	// MainIndexFileName names the main index file written to the output directory, empty keeps
+	// "terraform-provider-aws-index.json", e.g. "terraform-provider-awscc-index.json" for another provider.
+	// The manifest, write plans and LoadIndexFromDirWithOptions follow the configured name
+	MainIndexFileName string
+}
+
+// FileNameData is the data OutputOptions.FileNameTemplate is rendered with
+type FileNameData struct {
+	TerraformType string // "aws_s3_bucket"
+	Service       string // Service directory name, e.g. "s3"
+	Category      string // WalkCategoryResource, WalkCategoryDataSource or WalkCategoryEphemeral
+}
+
+// Default category directory names, see OutputOptions.ResourcesDir
+const (
+	defaultResourcesDir   = "resources"
+	defaultDataSourcesDir = "datasources"
+	defaultEphemeralDir   = "ephemeral"
+)
+
+// Directories, relative to the output directory, WriteServiceFiles and WriteAttributeSchemas write to
+const (
+	serviceFilesDir     = "services"
+	attributeSchemasDir = "attributes"
+)
+
+// defaultMainIndexFileName is the main index file name when OutputOptions.MainIndexFileName is empty
+const defaultMainIndexFileName = "terraform-provider-aws-index.json"
+
+// mainIndexFileName returns the name of the main index file, without the compression suffix
+func (o OutputOptions) mainIndexFileName() string {
+	if o.MainIndexFileName != "" {
+		return o.MainIndexFileName
+	}
+	return defaultMainIndexFileName
+}
+
+// validateMainIndexFileName rejects main index names that are not a plain file name or that would overwrite
+// another top-level index file
+func (o OutputOptions) validateMainIndexFileName() error {
+	name := o.mainIndexFileName()
+	switch {
+	case strings.TrimSpace(name) == "" || name == "." || name == "..":
+		return fmt.Errorf("invalid main index file name %q", name)
+	case strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("invalid main index file name %q: must not contain path separators", name)
+	}
+	for _, reserved := range []string{"factory-index.json", "statistics.json", manifestFileName} {
+		if name == reserved {
+			return fmt.Errorf("invalid main index file name %q: already used for %s", name, reserved)
+		}
+	}
+	return nil
+}
+
+// categoryDir returns the directory, relative to the output directory, that entries of a walk category are written to
+func (o OutputOptions) categoryDir(category string) string {
+	switch category {
+	case WalkCategoryResource:
+		if o.ResourcesDir != "" {
+			return o.ResourcesDir
+		}
+		return defaultResourcesDir
+	case WalkCategoryDataSource:
+		if o.DataSourcesDir != "" {
+			return o.DataSourcesDir
+		}
+		return defaultDataSourcesDir
+	case WalkCategoryEphemeral:
+		if o.EphemeralDir != "" {
+			return o.EphemeralDir
+		}
+		return defaultEphemeralDir
+	}
+	return category
+}
+
+// validateCategoryDirs rejects category directories that would escape the output directory or share a directory
+// with another category, the services/ or the attributes/ files
+func (o OutputOptions) validateCategoryDirs() error {
+	used := map[string]string{serviceFilesDir: "service files", attributeSchemasDir: "attribute schema files"}
+	for _, category := range walkCategories {
+		dir := o.categoryDir(category)
+		if err := validateEntryFileName(dir); err != nil {
+			return fmt.Errorf("invalid %s directory %q: %w", category, dir, err)
+		}
+		if other, exists := used[dir]; exists {
+			return fmt.Errorf("invalid %s directory %q: already used for %s", category, dir, other)
+		}
+		used[dir] = category
+	}
+	return nil
+}
+
+const (
+	defaultWriteMaxRetries     = 3
+	defaultWriteRetryBaseDelay = 10 * time.Millisecond
+)
+
+// defaultServiceBatchSize is the number of services per batch when OutputOptions.ServiceBatchSize is 0
+const defaultServiceBatchSize = 32
+
+// serviceBatchSize returns the effective number of services per batch for an index of serviceCount services
+func (o OutputOptions) serviceBatchSize(serviceCount int) int {
+	switch {
+	case o.ServiceBatchSize < 0:
+		return max(serviceCount, 1)
+	case o.ServiceBatchSize == 0:
+		return defaultServiceBatchSize
+	}
+	return o.ServiceBatchSize
+}
+
+// writeRetryPolicy returns the effective retry count and base delay
+func (o OutputOptions) writeRetryPolicy() (int, time.Duration) {
+	maxRetries := o.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultWriteMaxRetries
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	baseDelay := o.RetryBaseDelay
+	if baseDelay <= 0 {
+		baseDelay = defaultWriteRetryBaseDelay
+	}
+
+	return maxRetries, baseDelay
+}
+
+// compressedFileSuffix is appended to every file name when OutputOptions.Compress is set
+const compressedFileSuffix = ".gz"
+
+// WriteIndexFilesWithOptions is like WriteIndexFiles but applies options to every file written,
+// including the main index, factory index and manifest
+func (index *TerraformProviderIndex) WriteIndexFilesWithOptions(outputDir string, progressCallback ProgressCallback, options OutputOptions) error {
+	writer, err := index.withOutputOptions(options)
+	if err != nil {
+		return err
+	}
+	if options.Atomic {
+		return writer.writeIndexFilesAtomically(outputDir, progressCallback)
+	}
+	return writer.WriteIndexFiles(outputDir, progressCallback)
+}
+
+// PlanIndexFilesWithOptions is like PlanIndexFiles for a WriteIndexFilesWithOptions call with options
+func (index *TerraformProviderIndex) PlanIndexFilesWithOptions(outputDir string, options OutputOptions) (*WritePlan, error) {
+	writer, err := index.withOutputOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	return writer.PlanIndexFiles(outputDir)
+}
+
+// withOutputOptions returns a shallow copy of index that writes with options, so the options don't leak
+// into later writes of the same index. Category directories, the main index name and the file name template are checked
+// before anything is written
+func (index *TerraformProviderIndex) withOutputOptions(options OutputOptions) (*TerraformProviderIndex, error) {
+	writer := *index
+	writer.outputOptions = options
+	writer.fileNameTemplate = nil
+
+	if err := options.validateCategoryDirs(); err != nil {
+		return nil, err
+	}
+	if err := options.validateMainIndexFileName(); err != nil {
+		return nil, err
+	}
+
+	if options.FileNameTemplate != "" {
+		tmpl, err := template.New("file-name").Option("missingkey=error").Parse(options.FileNameTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file name template: %w", err)
+		}
+		writer.fileNameTemplate = tmpl
+
+		// Render a sample so a template that can never produce a valid name fails up front
+		if _, err := writer.entryFileName(WalkCategoryResource, "example", "aws_example"); err != nil {
+			return nil, err
+		}
+	}
+
+	return &writer, nil
+}
+
+// sdkTypeLabel returns the sdk_type written to entry files for sdkType, see OutputOptions.SDKTypePrefix
+func (index *TerraformProviderIndex) sdkTypeLabel(sdkType string) string {
+	return index.outputOptions.SDKTypePrefix + sdkType
+}
+
+// outputFileName returns the name a JSON file is written under given the output options
+func (index *TerraformProviderIndex) outputFileName(name string) string {
+	if index.outputOptions.Compress {
+		return name + compressedFileSuffix
+	}
+	return name
+}
+
+// entryFileName returns the file name, relative to its category directory, of a resource, data source or
+// ephemeral file. The compression suffix is not included, WriteJSONFile adds it
+func (index *TerraformProviderIndex) entryFileName(category, service, terraformType string) (string, error) {
+	if index.fileNameTemplate == nil {
+		return fmt.Sprintf("%s.json", terraformType), nil
+	}
+
+	var buf bytes.Buffer
+	data := FileNameData{TerraformType: terraformType, Service: service, Category: category}
+	if err := index.fileNameTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render file name for %s %s: %w", category, terraformType, err)
+	}
+
+	name := buf.String()
+	if err := validateEntryFileName(name); err != nil {
+		return "", fmt.Errorf("file name template rendered %q for %s %s: %w", name, category, terraformType, err)
+	}
+	return name, nil
+}
+
+// validateEntryFileName rejects rendered file names that would escape their category directory
+func validateEntryFileName(name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return fmt.Errorf("file name is empty")
+	case strings.Contains(name, "\\"):
+		return fmt.Errorf("file name must use forward slashes")
+	case path.IsAbs(name):
+		return fmt.Errorf("file name must be relative")
+	case name != path.Clean(name) || name == "." || name == ".." || strings.HasPrefix(name, "../"):
+		return fmt.Errorf("file name must be a clean path inside its category directory")
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+package pkg
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_Compress(t *testing.T) {
+	// Setup
+	sut := createTestTerraformProviderIndex()
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+
+	// Execute
+	err := sut.WriteIndexFilesWithOptions(outputDir, nil, OutputOptions{Compress: true})
+	require.NoError(t, err)
+
+	// Verify - resource file reads back through gzip
+	resourcePath := filepath.Join(outputDir, "resources", "aws_s3_bucket_policy.json")
+	exists, err := afero.Exists(fs, resourcePath)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	var resource TerraformResource
+	require.NoError(t, json.Unmarshal(readGzipFile(t, fs, resourcePath+".gz"), &resource))
+	assert.Equal(t, "aws_s3_bucket_policy", resource.TerraformType)
+
+	// Main index and manifest are compressed too, and the manifest lists compressed names
+	var index TerraformProviderIndex
+	require.NoError(t, json.Unmarshal(readGzipFile(t, fs, filepath.Join(outputDir, "terraform-provider-aws-index.json.gz")), &index))
+	assert.Equal(t, sut.Version, index.Version)
+
+	var manifest map[string]ManifestEntry
+	require.NoError(t, json.Unmarshal(readGzipFile(t, fs, filepath.Join(outputDir, "manifest.json.gz")), &manifest))
+	assert.Contains(t, manifest, "resources/aws_s3_bucket_policy.json.gz")
+	assert.Contains(t, manifest, "factory-index.json.gz")
+
+	// The plan accounts for the extension
+	plan, err := sut.PlanIndexFilesWithOptions(outputDir, OutputOptions{Compress: true})
+	require.NoError(t, err)
+	for _, file := range plan.Files {
+		exists, err := afero.Exists(fs, filepath.Join(outputDir, filepath.FromSlash(file.Path)))
+		require.NoError(t, err)
+		assert.True(t, exists, file.Path)
+	}
+
+	// Options don't stick to the index
+	require.NoError(t, sut.WriteIndexFiles("/test/plain", nil))
+	exists, err = afero.Exists(fs, "/test/plain/resources/aws_s3_bucket_policy.json")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_Compact(t *testing.T) {
+	// Setup
+	sut := createTestTerraformProviderIndex()
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	resourceFile := filepath.Join("resources", "aws_s3_bucket_policy.json")
+
+	// Execute
+	require.NoError(t, sut.WriteIndexFiles("/test/pretty", nil))
+	require.NoError(t, sut.WriteIndexFilesWithOptions("/test/compact", nil, OutputOptions{Compact: true}))
+
+	// Verify - the same resource is smaller without indentation but decodes identically
+	pretty, err := afero.ReadFile(fs, filepath.Join("/test/pretty", resourceFile))
+	require.NoError(t, err)
+	compact, err := afero.ReadFile(fs, filepath.Join("/test/compact", resourceFile))
+	require.NoError(t, err)
+	assert.Less(t, len(compact), len(pretty))
+	assert.NotContains(t, string(compact), "\n")
+
+	var prettyResource, compactResource TerraformResource
+	require.NoError(t, json.Unmarshal(pretty, &prettyResource))
+	require.NoError(t, json.Unmarshal(compact, &compactResource))
+	assert.Equal(t, prettyResource, compactResource)
+
+	// The manifest describes the compact file as written
+	manifest, err := sut.BuildManifest("/test/compact")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(compact)), manifest["resources/aws_s3_bucket_policy.json"].Size)
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_FileNameTemplate(t *testing.T) {
+	// Setup
+	sut := createTestTerraformProviderIndex()
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+	options := OutputOptions{FileNameTemplate: "{{.Service}}_{{.TerraformType}}.json"}
+
+	// Execute
+	err := sut.WriteIndexFilesWithOptions(outputDir, nil, options)
+	require.NoError(t, err)
+
+	// Verify - entry files are service-prefixed, the default names are not written
+	for _, path := range []string{
+		"resources/s3_aws_s3_bucket_policy.json",
+		"resources/s3_aws_s3_bucket.json",
+		"datasources/s3_aws_s3_bucket.json",
+	} {
+		exists, err := afero.Exists(fs, filepath.Join(outputDir, filepath.FromSlash(path)))
+		require.NoError(t, err)
+		assert.True(t, exists, path)
+	}
+	exists, err := afero.Exists(fs, filepath.Join(outputDir, "resources", "aws_s3_bucket_policy.json"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// The plan uses the same names
+	plan, err := sut.PlanIndexFilesWithOptions(outputDir, options)
+	require.NoError(t, err)
+	for _, file := range plan.Files {
+		exists, err := afero.Exists(fs, filepath.Join(outputDir, filepath.FromSlash(file.Path)))
+		require.NoError(t, err)
+		assert.True(t, exists, file.Path)
+	}
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_InvalidFileNameTemplate(t *testing.T) {
+	sut := createTestTerraformProviderIndex()
+
+	tests := []struct {
+		name     string
+		template string
+	}{
+		{name: "syntax error", template: "{{.TerraformType"},
+		{name: "unknown field", template: "{{.Namespace}}.json"},
+		{name: "empty name", template: "{{if false}}x{{end}}"},
+		{name: "absolute path", template: "/{{.TerraformType}}.json"},
+		{name: "parent directory", template: "../{{.TerraformType}}.json"},
+		{name: "unclean path", template: "{{.Service}}//{{.TerraformType}}.json"},
+		{name: "backslash", template: "{{.Service}}\\{{.TerraformType}}.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			stub := gostub.Stub(&outputFs, fs)
+			defer stub.Reset()
+
+			err := sut.WriteIndexFilesWithOptions("/test/output", nil, OutputOptions{FileNameTemplate: tt.template})
+			assert.Error(t, err)
+
+			// Nothing is written when the template is rejected
+			exists, err := afero.DirExists(fs, "/test/output")
+			require.NoError(t, err)
+			assert.False(t, exists)
+		})
+	}
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_CategoryDirs(t *testing.T) {
+	// Setup
+	sut := createTestTerraformProviderIndex()
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+	options := OutputOptions{ResourcesDir: "r", DataSourcesDir: "d", EphemeralDir: "docs/ephemeral-resources"}
+
+	// Execute
+	err := sut.WriteIndexFilesWithOptions(outputDir, nil, options)
+	require.NoError(t, err)
+
+	// Verify - entry files land in the custom directories, the default directories are not created
+	for _, path := range []string{
+		"r/aws_s3_bucket_policy.json",
+		"r/aws_s3_bucket.json",
+		"d/aws_s3_bucket.json",
+	} {
+		exists, err := afero.Exists(fs, filepath.Join(outputDir, filepath.FromSlash(path)))
+		require.NoError(t, err)
+		assert.True(t, exists, path)
+	}
+	exists, err := afero.DirExists(fs, filepath.Join(outputDir, "docs", "ephemeral-resources"))
+	require.NoError(t, err)
+	assert.True(t, exists)
+	for _, dir := range []string{"resources", "datasources", "ephemeral"} {
+		exists, err := afero.DirExists(fs, filepath.Join(outputDir, dir))
+		require.NoError(t, err)
+		assert.False(t, exists, dir)
+	}
+
+	// The manifest and the plan use the same directories
+	writer, err := sut.withOutputOptions(options)
+	require.NoError(t, err)
+	entries, err := writer.BuildManifest(outputDir)
+	require.NoError(t, err)
+	assert.Contains(t, entries, "r/aws_s3_bucket.json")
+	assert.Contains(t, entries, "d/aws_s3_bucket.json")
+
+	plan, err := sut.PlanIndexFilesWithOptions(outputDir, options)
+	require.NoError(t, err)
+	for _, file := range plan.Files {
+		exists, err := afero.Exists(fs, filepath.Join(outputDir, filepath.FromSlash(file.Path)))
+		require.NoError(t, err)
+		assert.True(t, exists, file.Path)
+	}
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_SDKTypePrefix(t *testing.T) {
+	// Setup
+	sut := createTestTerraformProviderIndex()
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+
+	// Execute
+	err := sut.WriteIndexFilesWithOptions(outputDir, nil, OutputOptions{SDKTypePrefix: "my", ServiceFiles: true})
+	require.NoError(t, err)
+
+	// Verify - entry files and service files carry the prefixed label
+	sdkTypes := make(map[string]string)
+	for _, path := range []string{"resources/aws_s3_bucket_policy.json", "resources/aws_s3_bucket.json", "datasources/aws_s3_bucket.json"} {
+		data, err := afero.ReadFile(fs, filepath.Join(outputDir, filepath.FromSlash(path)))
+		require.NoError(t, err)
+		var entry struct {
+			SDKType string `json:"sdk_type"`
+		}
+		require.NoError(t, json.Unmarshal(data, &entry))
+		sdkTypes[path] = entry.SDKType
+	}
+	assert.Equal(t, map[string]string{
+		"resources/aws_s3_bucket_policy.json": "myaws_sdk",
+		"resources/aws_s3_bucket.json":        "myaws_framework",
+		"datasources/aws_s3_bucket.json":      "myaws_sdk",
+	}, sdkTypes)
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "services", "s3.json"))
+	require.NoError(t, err)
+	var serviceFile ServiceFile
+	require.NoError(t, json.Unmarshal(data, &serviceFile))
+	for _, resource := range serviceFile.Resources {
+		assert.Contains(t, []string{"myaws_sdk", "myaws_framework"}, resource.SDKType, resource.TerraformType)
+	}
+
+	// The main index keeps the unprefixed SDK types
+	data, err = afero.ReadFile(fs, filepath.Join(outputDir, "terraform-provider-aws-index.json"))
+	require.NoError(t, err)
+	var index TerraformProviderIndex
+	require.NoError(t, json.Unmarshal(data, &index))
+	assert.Equal(t, sut.Services[0].AWSSDKResources["aws_s3_bucket_policy"].SDKType, index.Services[0].AWSSDKResources["aws_s3_bucket_policy"].SDKType)
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_InvalidCategoryDirs(t *testing.T) {
+	sut := createTestTerraformProviderIndex()
+
+	tests := []struct {
+		name    string
+		options OutputOptions
+	}{
+		{name: "parent directory", options: OutputOptions{ResourcesDir: "../resources"}},
+		{name: "absolute path", options: OutputOptions{DataSourcesDir: "/datasources"}},
+		{name: "shared directory", options: OutputOptions{ResourcesDir: "entries", DataSourcesDir: "entries"}},
+		{name: "same as default of another category", options: OutputOptions{EphemeralDir: "resources"}},
+		{name: "services directory", options: OutputOptions{ResourcesDir: "services"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			stub := gostub.Stub(&outputFs, fs)
+			defer stub.Reset()
+
+			err := sut.WriteIndexFilesWithOptions("/test/output", nil, tt.options)
+			assert.Error(t, err)
+
+			// Nothing is written when a directory is rejected
+			exists, err := afero.DirExists(fs, "/test/output")
+			require.NoError(t, err)
+			assert.False(t, exists)
+		})
+	}
+}
+
+func TestTerraformProviderIndex_WriteResourceFiles_BatchedMatchesSingleBatch(t *testing.T) {
+	// Setup - five services, so a batch size of two leaves a partial last batch
+	index := createTestTerraformProviderIndex()
+	for _, name := range []string{"ec2", "lambda", "iam", "sqs"} {
+		index.Services = append(index.Services, newMergeTestService(name,
+			[]string{"aws_" + name + "_alpha", "aws_" + name + "_beta"}, []string{"aws_" + name + "_alpha"}))
+	}
+	index.Statistics = newProviderStatistics(index.Services)
+
+	write := func(options OutputOptions) map[string][]byte {
+		fs := afero.NewMemMapFs()
+		stub := gostub.Stub(&outputFs, fs)
+		defer stub.Reset()
+		require.NoError(t, index.WriteIndexFilesWithOptions("/test/output", nil, options))
+		return readTree(t, fs, "/test/output")
+	}
+
+	// Execute
+	single := write(OutputOptions{ServiceBatchSize: -1})
+	batched := write(OutputOptions{ServiceBatchSize: 2})
+
+	// Verify
+	assert.Contains(t, single, filepath.Join("/test/output", "resources", "aws_sqs_beta.json"))
+	assert.Equal(t, single, batched)
+}
+
+func TestOutputOptions_ServiceBatchSize(t *testing.T) {
+	assert.Equal(t, defaultServiceBatchSize, OutputOptions{}.serviceBatchSize(100))
+	assert.Equal(t, 5, OutputOptions{ServiceBatchSize: 5}.serviceBatchSize(100))
+	assert.Equal(t, 100, OutputOptions{ServiceBatchSize: -1}.serviceBatchSize(100))
+	assert.Equal(t, 1, OutputOptions{ServiceBatchSize: -1}.serviceBatchSize(0))
+}
+
+// readTree reads every file below root, keyed by path
+func readTree(t *testing.T, fs afero.Fs, root string) map[string][]byte {
+	files := make(map[string][]byte)
+	err := afero.Walk(fs, root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := afero.ReadFile(fs, filePath)
+		files[filePath] = data
+		return err
+	})
+	require.NoError(t, err)
+	return files
+}
+
+func TestValidateEntryFileName_AllowsSubdirectories(t *testing.T) {
+	assert.NoError(t, validateEntryFileName("s3/aws_s3_bucket.json"))
+	assert.NoError(t, validateEntryFileName("aws_s3_bucket.json"))
+}
+
+func readGzipFile(t *testing.T, fs afero.Fs, path string) []byte {
+	file, err := fs.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	defer zr.Close()
+
+	data, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	return data
+}
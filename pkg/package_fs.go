@@ -0,0 +1,61 @@
+package pkg
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+	"github.com/spf13/afero"
+)
+
+// scanPackageFromFs parses the Go package in dir on fs into the gophon representation used by the scanner.
+// It stands in for gophon.ScanSinglePackage, which only reads from disk, for ScanOptions.InputFs: files are
+// parsed one by one without type information, test files are skipped and build constraints are not evaluated.
+// The package path follows gophon, basePkgUrl joined with dir's parent directories and the package name
+func scanPackageFromFs(fs afero.Fs, dir, basePkgUrl string) (*gophon.PackageInfo, error) {
+	entries, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package directory %s: %w", dir, err)
+	}
+
+	var fileNames []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+			fileNames = append(fileNames, name)
+		}
+	}
+	sort.Strings(fileNames)
+
+	packageInfo := &gophon.PackageInfo{}
+	fset := token.NewFileSet()
+	for _, name := range fileNames {
+		filePath := filepath.Join(dir, name)
+		src, err := afero.ReadFile(fs, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+		file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+		}
+
+		fileInfo := &gophon.FileInfo{
+			File:     file,
+			FileName: filePath,
+			FilePath: filePath,
+			Package:  path.Join(basePkgUrl, filepath.ToSlash(filepath.Dir(dir)), file.Name.Name),
+		}
+		functions, types := fileDeclarations(fset, fileInfo)
+		packageInfo.Files = append(packageInfo.Files, fileInfo)
+		packageInfo.Functions = append(packageInfo.Functions, functions...)
+		packageInfo.Types = append(packageInfo.Types, types...)
+	}
+
+	return packageInfo, nil
+}
@@ -0,0 +1,68 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanTerraformProviderServicesWithOptions_InputFs(t *testing.T) {
+	// Setup - a synthetic service tree that only exists in memory, the package inputFs is left untouched
+	fs := afero.NewMemMapFs()
+	files := map[string]string{
+		"/provider/internal/service/s3/bucket.go": `package s3
+
+// @SDKResource("aws_s3_bucket", name="Bucket")
+func resourceBucket() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceBucketRead,
+	}
+}
+`,
+		"/provider/internal/service/lambda/function.go": `package lambda
+
+// @FrameworkResource("aws_lambda_function", name="Function")
+func newFunctionResource(context.Context) (resource.ResourceWithConfigure, error) {
+	return &functionResource{}, nil
+}
+
+type functionResource struct{}
+
+func (r *functionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+}
+`,
+		"/provider/internal/service/lambda/function_test.go": `package lambda_test
+
+// @SDKResource("aws_lambda_test_only", name="TestOnly")
+func resourceTestOnly() *schema.Resource {
+	return &schema.Resource{}
+}
+`,
+	}
+	for filePath, content := range files {
+		require.NoError(t, afero.WriteFile(fs, filePath, []byte(content), 0644))
+	}
+
+	// Execute
+	index, err := ScanTerraformProviderServicesWithOptions(context.Background(), "/provider/internal/service",
+		"github.com/hashicorp/terraform-provider-aws", "v1.0.0", nil, ScanOptions{InputFs: fs})
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, index.Services, 2)
+	lambda, s3 := index.Services[0], index.Services[1]
+
+	assert.Equal(t, "s3", s3.ServiceName)
+	require.Contains(t, s3.AWSSDKResources, "aws_s3_bucket")
+	assert.Equal(t, "s3/bucket.go", s3.AWSSDKResources["aws_s3_bucket"].RegistrationFile)
+	assert.Equal(t, "resourceBucketRead", s3.ResourceCRUDMethods["aws_s3_bucket"].ReadMethod)
+
+	assert.Equal(t, "lambda", lambda.ServiceName)
+	require.Contains(t, lambda.AWSFrameworkResources, "aws_lambda_function")
+	assert.Equal(t, "functionResource", lambda.AWSFrameworkResources["aws_lambda_function"].StructType)
+	assert.NotContains(t, lambda.AWSSDKResources, "aws_lambda_test_only")
+	assert.Equal(t, 2, index.Statistics.TotalResources)
+}
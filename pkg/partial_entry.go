@@ -0,0 +1,10 @@
+package pkg
+
+// PartialEntry represents an annotated terraform type that was detected but could not be fully resolved
+type PartialEntry struct {
+	ServiceName    string         `json:"service_name"`    // "bedrock"
+	TerraformType  string         `json:"terraform_type"`  // "aws_bedrock_guardrail"
+	AnnotationType AnnotationType `json:"annotation_type"` // "FrameworkResource"
+	FilePath       string         `json:"file_path"`       // Source file containing the annotation
+	Reason         string         `json:"reason"`          // Why the entry could not be resolved
+}
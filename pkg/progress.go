@@ -14,6 +14,10 @@ type ProgressInfo struct {
 	Total      int       // Total number of items
 	Percentage float64   // Completion percentage (0-100)
 	StartTime  time.Time // When the operation started
+
+	// Timing estimates, zero when not yet known (e.g. before any item completes)
+	Elapsed time.Duration // Time since the operation started
+	ETA     time.Duration // Estimated time remaining based on the items-done rate
 }
 
 // ProgressCallback is called to report progress updates
@@ -82,10 +86,13 @@ func CreateRichProgressCallback() ProgressCallback {
 	return func(progress ProgressInfo) {
 		elapsed := time.Since(progress.StartTime)
 		
-		// Calculate ETA
+		// Calculate ETA, preferring the tracker's rate-based estimate when available
 		var eta time.Duration
 		if progress.Percentage > 0 && progress.Percentage < 100 {
-			eta = calculateETA(elapsed, progress.Percentage)
+			eta = progress.ETA
+			if eta <= 0 {
+				eta = calculateETA(elapsed, progress.Percentage)
+			}
 		}
 		
 		// Calculate processing rate
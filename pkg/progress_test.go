@@ -1,10 +1,13 @@
 package pkg
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/prashantv/gostub"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateProgressBar(t *testing.T) {
@@ -226,3 +229,42 @@ func TestProgressCallback(t *testing.T) {
 		})
 	}
 }
+
+func TestProgressTracker_ETADecreasesAsProgressAdvances(t *testing.T) {
+	// Setup - a fake clock advancing one second per completed item
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	stub := gostub.Stub(&timeNow, func() time.Time { return now })
+	defer stub.Reset()
+
+	var updates []ProgressInfo
+	tracker := NewProgressTracker("indexing", 4, func(info ProgressInfo) {
+		updates = append(updates, info)
+	})
+
+	// Initial report has no estimate because nothing has completed
+	require.Len(t, updates, 1)
+	assert.Zero(t, updates[0].ETA)
+
+	// Execute
+	for i := 0; i < 4; i++ {
+		now = now.Add(time.Second)
+		tracker.UpdateProgress(fmt.Sprintf("item %d", i))
+	}
+
+	// Verify - 1s per item leaves 3s, 2s, 1s, then nothing
+	require.Len(t, updates, 5)
+	assert.Equal(t, 3*time.Second, updates[1].ETA)
+	assert.Equal(t, 2*time.Second, updates[2].ETA)
+	assert.Equal(t, 1*time.Second, updates[3].ETA)
+	assert.Zero(t, updates[4].ETA)
+	for i := 2; i < len(updates); i++ {
+		assert.Less(t, updates[i].ETA, updates[i-1].ETA)
+		assert.Greater(t, updates[i].Elapsed, updates[i-1].Elapsed)
+	}
+}
+
+func TestEstimateRemaining_ZeroCompleted(t *testing.T) {
+	assert.Zero(t, estimateRemaining(5*time.Second, 0, 10))
+	assert.Zero(t, estimateRemaining(5*time.Second, 10, 10))
+	assert.Zero(t, estimateRemaining(0, 0, 0))
+}
@@ -5,6 +5,9 @@ import (
 	"time"
 )
 
+// timeNow is the clock used by ProgressTracker, replaceable in tests
+var timeNow = time.Now
+
 type ProgressTracker struct {
 	phase          string
 	startTime      time.Time
@@ -17,7 +20,7 @@ type ProgressTracker struct {
 func NewProgressTracker(phase string, totalItems int, callback ProgressCallback) *ProgressTracker {
 	tracker := &ProgressTracker{
 		phase:      phase,
-		startTime:  time.Now(),
+		startTime:  timeNow(),
 		totalItems: totalItems,
 		callback:   callback,
 	}
@@ -45,6 +48,7 @@ func (pt *ProgressTracker) UpdateProgress(currentItem string) {
 
 	completed := atomic.AddInt64(&pt.completedItems, 1)
 	percentage := float64(completed) / float64(pt.totalItems) * 100.0
+	elapsed := timeNow().Sub(pt.startTime)
 
 	pt.callback(ProgressInfo{
 		Phase:      pt.phase,
@@ -53,9 +57,22 @@ func (pt *ProgressTracker) UpdateProgress(currentItem string) {
 		Total:      pt.totalItems,
 		Percentage: percentage,
 		StartTime:  pt.startTime,
+		Elapsed:    elapsed,
+		ETA:        estimateRemaining(elapsed, int(completed), pt.totalItems),
 	})
 }
 
+// estimateRemaining estimates the remaining time from the average time per completed item
+// Returns zero when nothing has completed yet or all items are done
+func estimateRemaining(elapsed time.Duration, completed, total int) time.Duration {
+	if completed <= 0 || completed >= total {
+		return 0
+	}
+
+	perItem := elapsed / time.Duration(completed)
+	return perItem * time.Duration(total-completed)
+}
+
 // Complete reports completion
 func (pt *ProgressTracker) Complete() {
 	if pt == nil || pt.callback == nil {
@@ -69,5 +86,6 @@ func (pt *ProgressTracker) Complete() {
 		Total:      pt.totalItems,
 		Percentage: 100.0,
 		StartTime:  pt.startTime,
+		Elapsed:    timeNow().Sub(pt.startTime),
 	})
 }
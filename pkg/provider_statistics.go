@@ -1,5 +1,10 @@
 package pkg
 
+import (
+	"path/filepath"
+	"sort"
+)
+
 // ProviderStatistics represents summary statistics for the provider
 type ProviderStatistics struct {
 	ServiceCount       int `json:"service_count"`
@@ -8,4 +13,83 @@ type ProviderStatistics struct {
 	LegacyResources    int `json:"legacy_resources"`
 	ModernResources    int `json:"modern_resources"`
 	EphemeralResources int `json:"ephemeral_resources"`
+
+	// Legacy ephemeral mappings without an AWS ephemeral resource, already included in EphemeralResources since
+	// they are written to ephemeral/ too
+	LegacyEphemeralResources int `json:"legacy_ephemeral_resources,omitempty"`
+
+	// Per-service breakdown keyed by service name, map iteration order is unspecified
+	PerService map[string]ServiceStats `json:"per_service,omitempty"`
+
+	// Every service's warnings, see ServiceRegistration.Warnings, plus conflicting terraform type registrations
+	// across services
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Wall-clock time of the scan in milliseconds. It varies between runs, so Merge drops it when recomputing
+	// the statistics and comparisons of written indexes should ignore it
+	ScanDurationMs int64 `json:"scan_duration_ms,omitempty"`
+}
+
+// WriteStatisticsFile writes the statistics.json file holding only the index statistics, so dashboards can fetch
+// the counts without the main index
+func (index *TerraformProviderIndex) WriteStatisticsFile(outputDir string) error {
+	statisticsPath := filepath.Join(outputDir, "statistics.json")
+	return index.WriteJSONFile(statisticsPath, index.Statistics)
+}
+
+// ServiceStats represents summary statistics for a single service
+type ServiceStats struct {
+	Resources          int `json:"resources"`           // SDK + Framework resources
+	DataSources        int `json:"data_sources"`        // SDK + Framework data sources
+	EphemeralResources int `json:"ephemeral_resources"` // AWS and legacy ephemeral resources
+	SDK                int `json:"sdk"`                 // SDK resources + SDK data sources
+	Framework          int `json:"framework"`           // Framework resources + data sources + AWS and legacy ephemeral resources
+}
+
+// newServiceStats computes the statistics for a single service registration. SDK and Framework split the same
+// entries as Resources, DataSources and EphemeralResources, so both add up to the same total
+func newServiceStats(serviceReg ServiceRegistration) ServiceStats {
+	ephemeralResources := len(serviceReg.AWSEphemeralResources) + len(serviceReg.legacyEphemeralTerraformTypes())
+	return ServiceStats{
+		Resources:          len(serviceReg.AWSSDKResources) + len(serviceReg.AWSFrameworkResources),
+		DataSources:        len(serviceReg.AWSSDKDataSources) + len(serviceReg.AWSFrameworkDataSources),
+		EphemeralResources: ephemeralResources,
+		SDK:                len(serviceReg.AWSSDKResources) + len(serviceReg.AWSSDKDataSources),
+		Framework:          len(serviceReg.AWSFrameworkResources) + len(serviceReg.AWSFrameworkDataSources) + ephemeralResources,
+	}
+}
+
+// newProviderStatistics computes the aggregate and per-service statistics for a set of services,
+// including conflicting registrations reported within each service and across services
+func newProviderStatistics(services []ServiceRegistration) ProviderStatistics {
+	stats := ProviderStatistics{
+		PerService: make(map[string]ServiceStats),
+	}
+	conflicts := newRegistrationConflictDetector()
+
+	for _, serviceReg := range services {
+		stats.ServiceCount++
+
+		// AWS 5-category statistics
+		stats.TotalResources += len(serviceReg.AWSSDKResources)
+		stats.TotalResources += len(serviceReg.AWSFrameworkResources)
+		stats.TotalDataSources += len(serviceReg.AWSSDKDataSources)
+		stats.TotalDataSources += len(serviceReg.AWSFrameworkDataSources)
+		legacyEphemeral := len(serviceReg.legacyEphemeralTerraformTypes())
+		stats.EphemeralResources += len(serviceReg.AWSEphemeralResources) + legacyEphemeral
+		stats.LegacyEphemeralResources += legacyEphemeral
+
+		// Per-service breakdown
+		stats.PerService[serviceReg.ServiceName] = newServiceStats(serviceReg)
+
+		// Conflicts within the service and across previously counted services
+		stats.Warnings = append(stats.Warnings, serviceReg.Warnings...)
+		recordServiceRegistrations(conflicts, serviceReg)
+	}
+	stats.Warnings = append(stats.Warnings, conflicts.warnings...)
+	sort.Strings(stats.Warnings)
+
+	// LegacyResources and ModernResources are no longer used and stay zero
+
+	return stats
 }
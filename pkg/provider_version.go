@@ -0,0 +1,160 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/mod/modfile"
+)
+
+// providerVersionFiles are plain text files holding the provider version, relative to the provider root
+var providerVersionFiles = []string{
+	filepath.Join("version", "VERSION"),
+	"VERSION",
+}
+
+// providerVersionSource is the Go file declaring the provider version, relative to the provider root
+var providerVersionSource = filepath.Join("internal", "version", "version.go")
+
+// providerVersionIdentifiers are the constant or variable names checked in providerVersionSource
+var providerVersionIdentifiers = []string{"ProviderVersion", "Version"}
+
+// DetectProviderVersion reads the provider version from the provider source tree rooted at dir, so callers
+// can pass it to ScanTerraformProviderServices. It checks version/VERSION and VERSION first, then a string
+// ProviderVersion or Version declared in internal/version/version.go
+func DetectProviderVersion(dir string) (string, error) {
+	for _, name := range providerVersionFiles {
+		filePath := filepath.Join(dir, name)
+		exists, err := afero.Exists(inputFs, filePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to stat %s: %w", filePath, err)
+		}
+		if !exists {
+			continue
+		}
+
+		data, err := afero.ReadFile(inputFs, filePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+		if version := strings.TrimSpace(string(data)); version != "" {
+			return version, nil
+		}
+	}
+
+	version, err := versionFromSource(filepath.Join(dir, providerVersionSource))
+	if err != nil {
+		return "", err
+	}
+	if version == "" {
+		return "", fmt.Errorf("no provider version found in %s", dir)
+	}
+	return version, nil
+}
+
+// versionFromSource returns the first string literal assigned to one of providerVersionIdentifiers in the Go
+// file at filePath, or an empty string when the file does not exist or declares none
+func versionFromSource(filePath string) (string, error) {
+	exists, err := afero.Exists(inputFs, filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat %s: %w", filePath, err)
+	}
+	if !exists {
+		return "", nil
+	}
+
+	src, err := afero.ReadFile(inputFs, filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, src, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	values := make(map[string]string)
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || (genDecl.Tok != token.CONST && genDecl.Tok != token.VAR) {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				if i >= len(valueSpec.Values) {
+					break
+				}
+				lit, ok := valueSpec.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				if value, err := strconv.Unquote(lit.Value); err == nil {
+					values[name.Name] = strings.TrimSpace(value)
+				}
+			}
+		}
+	}
+
+	for _, identifier := range providerVersionIdentifiers {
+		if values[identifier] != "" {
+			return values[identifier], nil
+		}
+	}
+	return "", nil
+}
+
+// DetectProviderModule reads the module path from the go.mod nearest to dir, looking in dir and then its parent
+// directories, and returns empty strings when there is none. go.mod does not record the module's own version, it
+// is taken from the module directory name when the source was extracted to the module cache, e.g.
+// "terraform-provider-aws@v5.31.0", and is empty otherwise
+func DetectProviderModule(dir string) (modulePath, moduleVersion string, err error) {
+	return detectProviderModule(inputFs, dir)
+}
+
+// detectProviderModule is DetectProviderModule reading from fs
+func detectProviderModule(fs afero.Fs, dir string) (string, string, error) {
+	if absDir, err := filepath.Abs(dir); err == nil {
+		dir = absDir
+	}
+
+	for current := filepath.Clean(dir); ; current = filepath.Dir(current) {
+		filePath := filepath.Join(current, "go.mod")
+		exists, err := afero.Exists(fs, filePath)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to stat %s: %w", filePath, err)
+		}
+		if exists {
+			data, err := afero.ReadFile(fs, filePath)
+			if err != nil {
+				return "", "", fmt.Errorf("failed to read %s: %w", filePath, err)
+			}
+			modulePath := modfile.ModulePath(data)
+			if modulePath == "" {
+				return "", "", fmt.Errorf("no module directive found in %s", filePath)
+			}
+			return modulePath, moduleCacheVersion(filepath.Base(current)), nil
+		}
+		if filepath.Dir(current) == current {
+			return "", "", nil
+		}
+	}
+}
+
+// moduleCacheVersion returns the version of a module cache directory name, e.g. "v5.31.0" for
+// "terraform-provider-aws@v5.31.0", or an empty string for a directory outside the module cache
+func moduleCacheVersion(dirName string) string {
+	_, version, found := strings.Cut(dirName, "@")
+	if !found {
+		return ""
+	}
+	return version
+}
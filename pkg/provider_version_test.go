@@ -0,0 +1,170 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectProviderVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected string
+	}{
+		{
+			name:     "version directory file",
+			files:    map[string]string{"/provider/version/VERSION": "6.3.0\n"},
+			expected: "6.3.0",
+		},
+		{
+			name:     "top-level file",
+			files:    map[string]string{"/provider/VERSION": " 5.100.0 "},
+			expected: "5.100.0",
+		},
+		{
+			name: "version file takes precedence over source",
+			files: map[string]string{
+				"/provider/version/VERSION":             "6.3.0",
+				"/provider/internal/version/version.go": "package version\n\nvar ProviderVersion = \"dev\"\n",
+			},
+			expected: "6.3.0",
+		},
+		{
+			name: "version constant in source",
+			files: map[string]string{
+				"/provider/internal/version/version.go": "package version\n\nconst (\n\tName    = \"aws\"\n\tVersion = \"6.2.1\"\n)\n",
+			},
+			expected: "6.2.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			fs := afero.NewMemMapFs()
+			for filePath, content := range tt.files {
+				require.NoError(t, afero.WriteFile(fs, filePath, []byte(content), 0644))
+			}
+			stub := gostub.Stub(&inputFs, fs)
+			defer stub.Reset()
+
+			// Execute
+			version, err := DetectProviderVersion("/provider")
+
+			// Verify
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, version)
+		})
+	}
+}
+
+func TestDetectProviderVersion_NotFound(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/provider/internal/service", 0755))
+	require.NoError(t, afero.WriteFile(fs, "/provider/VERSION", []byte("\n"), 0644))
+	stub := gostub.Stub(&inputFs, fs)
+	defer stub.Reset()
+
+	version, err := DetectProviderVersion("/provider")
+
+	assert.Empty(t, version)
+	assert.ErrorContains(t, err, "no provider version found in /provider")
+}
+
+func TestDetectProviderModule(t *testing.T) {
+	tests := []struct {
+		name            string
+		files           map[string]string
+		dir             string
+		expectedPath    string
+		expectedVersion string
+	}{
+		{
+			name:         "go.mod above the services directory",
+			files:        map[string]string{"/provider/go.mod": "module github.com/hashicorp/terraform-provider-aws\n\ngo 1.24.5\n"},
+			dir:          "/provider/internal/service",
+			expectedPath: "github.com/hashicorp/terraform-provider-aws",
+		},
+		{
+			name: "nearest go.mod wins",
+			files: map[string]string{
+				"/provider/go.mod":                  "module github.com/hashicorp/terraform-provider-aws\n",
+				"/provider/internal/service/go.mod": "module example.com/services\n",
+			},
+			dir:          "/provider/internal/service",
+			expectedPath: "example.com/services",
+		},
+		{
+			name: "module cache directory",
+			files: map[string]string{
+				"/go/pkg/mod/github.com/hashicorp/terraform-provider-aws@v5.31.0/go.mod": "module \"github.com/hashicorp/terraform-provider-aws\" // provider\n",
+			},
+			dir:             "/go/pkg/mod/github.com/hashicorp/terraform-provider-aws@v5.31.0/internal/service",
+			expectedPath:    "github.com/hashicorp/terraform-provider-aws",
+			expectedVersion: "v5.31.0",
+		},
+		{
+			name: "no go.mod",
+			dir:  "/provider/internal/service",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			fs := afero.NewMemMapFs()
+			for filePath, content := range tt.files {
+				require.NoError(t, afero.WriteFile(fs, filePath, []byte(content), 0644))
+			}
+			stub := gostub.Stub(&inputFs, fs)
+			defer stub.Reset()
+
+			// Execute
+			modulePath, moduleVersion, err := DetectProviderModule(tt.dir)
+
+			// Verify
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPath, modulePath)
+			assert.Equal(t, tt.expectedVersion, moduleVersion)
+		})
+	}
+}
+
+func TestDetectProviderModule_MissingModuleDirective(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/provider/go.mod", []byte("go 1.24.5\n"), 0644))
+	stub := gostub.Stub(&inputFs, fs)
+	defer stub.Reset()
+
+	_, _, err := DetectProviderModule("/provider/internal/service")
+
+	assert.Error(t, err)
+}
+
+func TestScanTerraformProviderServicesWithOptions_RecordsModule(t *testing.T) {
+	// Setup
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/provider/go.mod", []byte("module github.com/hashicorp/terraform-provider-aws\n"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/provider/internal/service/widget/widget.go", []byte(`package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{}
+}
+`), 0644))
+
+	// Execute
+	index, err := ScanTerraformProviderServicesWithOptions(context.Background(), "/provider/internal/service",
+		"github.com/hashicorp/terraform-provider-aws", "v6.0.0", nil, ScanOptions{InputFs: fs})
+
+	// Verify - the user supplied version is kept next to the module provenance
+	require.NoError(t, err)
+	assert.Equal(t, "v6.0.0", index.Version)
+	assert.Equal(t, "github.com/hashicorp/terraform-provider-aws", index.ModulePath)
+	assert.Empty(t, index.ModuleVersion)
+}
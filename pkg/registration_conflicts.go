@@ -0,0 +1,50 @@
+package pkg
+
+import "fmt"
+
+// registrationRecord describes where a terraform type was registered, used for conflict detection
+type registrationRecord struct {
+	ServiceName     string
+	FactoryFunction string
+	SDKType         string
+	FilePath        string
+}
+
+// conflictsWith reports whether two registrations of the same terraform type disagree
+func (r registrationRecord) conflictsWith(other registrationRecord) bool {
+	return r.FactoryFunction != other.FactoryFunction || r.SDKType != other.SDKType
+}
+
+// String describes the registration for warning messages
+func (r registrationRecord) String() string {
+	location := r.ServiceName
+	if r.FilePath != "" {
+		location = fmt.Sprintf("%s (%s)", r.ServiceName, r.FilePath)
+	}
+	return fmt.Sprintf("%s [%s] in %s", r.FactoryFunction, r.SDKType, location)
+}
+
+// registrationConflictDetector tracks registrations per category and records a warning
+// whenever a terraform type is registered again with a different factory function or SDK type
+// It only observes registrations, callers keep their last-write-wins behavior
+type registrationConflictDetector struct {
+	seen     map[string]registrationRecord
+	warnings []string
+}
+
+// newRegistrationConflictDetector creates an empty registrationConflictDetector
+func newRegistrationConflictDetector() *registrationConflictDetector {
+	return &registrationConflictDetector{
+		seen: make(map[string]registrationRecord),
+	}
+}
+
+// record registers a terraform type in the given category ("resource", "data source", "ephemeral resource")
+func (d *registrationConflictDetector) record(category, terraformType string, record registrationRecord) {
+	key := category + "/" + terraformType
+	if previous, exists := d.seen[key]; exists && previous.conflictsWith(record) {
+		d.warnings = append(d.warnings, fmt.Sprintf("conflicting %s registration for %s: %s overrides %s",
+			category, terraformType, record, previous))
+	}
+	d.seen[key] = record
+}
@@ -0,0 +1,68 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// relatedFunctions returns the indexes of the helpers referenced by an entry's factory when
+// OutputOptions.RelatedFunctions is set, nil otherwise
+func (index *TerraformProviderIndex) relatedFunctions(service *ServiceRegistration, factoryFunction string) []string {
+	if !index.outputOptions.RelatedFunctions {
+		return nil
+	}
+	return relatedFunctionIndexes(service.Package, factoryFunction)
+}
+
+// relatedFunctionIndexes returns the indexes of the unexported package-level functions referenced in the body of
+// factoryFunction, e.g. expand and flatten helpers or CRUD functions, sorted and without the factory itself.
+// Functions of other packages, methods and exported functions are left out
+func relatedFunctionIndexes(packageInfo *gophon.PackageInfo, factoryFunction string) []string {
+	factory := findFuncDecl(packageInfo, factoryFunction)
+	if factory == nil || factory.Body == nil {
+		return nil
+	}
+
+	declared := make(map[string]bool)
+	for _, fileInfo := range packageInfo.Files {
+		if fileInfo == nil || fileInfo.File == nil {
+			continue
+		}
+		for _, decl := range fileInfo.File.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil && !funcDecl.Name.IsExported() {
+				declared[funcDecl.Name.Name] = true
+			}
+		}
+	}
+
+	referenced := make(map[string]bool)
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			// x.f names a field, method or function of another package, only x can reference a package function
+			ast.Inspect(node.X, visit)
+			return false
+		case *ast.Ident:
+			if declared[node.Name] && node.Name != factoryFunction {
+				referenced[node.Name] = true
+			}
+		}
+		return true
+	}
+	ast.Inspect(factory.Body, visit)
+
+	if len(referenced) == 0 {
+		return nil
+	}
+	indexes := make([]string, 0, len(referenced))
+	for name := range referenced {
+		indexes = append(indexes, fmt.Sprintf("func.%s.goindex", name))
+	}
+	sort.Strings(indexes)
+
+	return indexes
+}
@@ -0,0 +1,150 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const relatedFunctionsServiceSource = `package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceWidgetCreate,
+		ReadWithoutTimeout:   resourceWidgetRead,
+		Schema: map[string]*schema.Schema{
+			"rule": {
+				Type:             schema.TypeList,
+				Optional:         true,
+				Elem:             ruleSchema(),
+				DiffSuppressFunc: verify.SuppressEquivalentJSONDiffs,
+			},
+		},
+		CustomizeDiff: customdiff.Sequence(validateWidgetRules, verify.SetTagsDiff),
+	}
+}
+
+func resourceWidgetCreate() {}
+
+func resourceWidgetRead() {}
+
+func ruleSchema() *schema.Resource {
+	return expandRules(flattenRules(nil))
+}
+
+func validateWidgetRules() {}
+
+func expandRules(v interface{}) *schema.Resource { return nil }
+
+func flattenRules(v interface{}) interface{} { return nil }
+
+func ExportedHelper() {}
+`
+
+func TestRelatedFunctionIndexes(t *testing.T) {
+	packageInfo := createMockPackageInfoFromSource(t, "/services/widget/widget.go", "example.com/provider/widget", relatedFunctionsServiceSource)
+
+	// Only functions referenced directly by the factory body, not by the helpers it calls
+	assert.Equal(t, []string{
+		"func.resourceWidgetCreate.goindex",
+		"func.resourceWidgetRead.goindex",
+		"func.ruleSchema.goindex",
+		"func.validateWidgetRules.goindex",
+	}, relatedFunctionIndexes(packageInfo, "resourceWidget"))
+	assert.Equal(t, []string{"func.expandRules.goindex", "func.flattenRules.goindex"}, relatedFunctionIndexes(packageInfo, "ruleSchema"))
+	assert.Nil(t, relatedFunctionIndexes(packageInfo, "resourceMissing"))
+	assert.Nil(t, relatedFunctionIndexes(nil, "resourceWidget"))
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_RelatedFunctions(t *testing.T) {
+	// Setup
+	service := CreateTestServiceRegistration("widget")
+	service.Package = createMockPackageInfoFromSource(t, "/services/widget/widget.go", "example.com/provider/widget", relatedFunctionsServiceSource)
+	service.AWSSDKResources["aws_widget"] = AWSResource{TerraformType: "aws_widget", FactoryFunction: "ruleSchema", SDKType: "sdk"}
+	sut := &TerraformProviderIndex{Version: "v1.0.0", Services: []ServiceRegistration{service}}
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+
+	readRelatedFunctions := func(filePath string) []string {
+		data, err := afero.ReadFile(fs, filePath)
+		require.NoError(t, err)
+		var entry TerraformResource
+		require.NoError(t, json.Unmarshal(data, &entry))
+		return entry.RelatedFunctions
+	}
+
+	// Execute
+	require.NoError(t, sut.WriteIndexFilesWithOptions("/test/related", nil, OutputOptions{RelatedFunctions: true, ServiceFiles: true}))
+	require.NoError(t, sut.WriteIndexFilesWithOptions("/test/plain", nil, OutputOptions{}))
+
+	// Verify - entry and service files list the helpers only when asked to
+	expected := []string{"func.expandRules.goindex", "func.flattenRules.goindex"}
+	assert.Equal(t, expected, readRelatedFunctions(filepath.Join("/test/related", "resources", "aws_widget.json")))
+	assert.Nil(t, readRelatedFunctions(filepath.Join("/test/plain", "resources", "aws_widget.json")))
+
+	data, err := afero.ReadFile(fs, filepath.Join("/test/related", "services", "widget.json"))
+	require.NoError(t, err)
+	var serviceFile ServiceFile
+	require.NoError(t, json.Unmarshal(data, &serviceFile))
+	require.Len(t, serviceFile.Resources, 1)
+	assert.Equal(t, expected, serviceFile.Resources[0].RelatedFunctions)
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_RelatedFunctionsOfScannedEntries(t *testing.T) {
+	// Setup - the factories are named differently from resourceExampleWidget and dataSourceExampleWidget
+	serviceReg, err := ParseServiceSource([]byte(`package example
+
+// @SDKResource("aws_example_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+
+// @SDKDataSource("aws_example_widget", name="Widget")
+func dataSourceWidgetLookup() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceWidgetLookupRead,
+	}
+}
+
+func resourceWidgetRead() {}
+
+func dataSourceWidgetLookupRead() {}
+`), "github.com/hashicorp/terraform-provider-aws/internal/service/example")
+	require.NoError(t, err)
+	sut := &TerraformProviderIndex{Version: "v1.0.0", Services: []ServiceRegistration{*serviceReg}}
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+
+	// Execute
+	require.NoError(t, sut.WriteIndexFilesWithOptions("/test/related", nil, OutputOptions{RelatedFunctions: true, ServiceFiles: true}))
+
+	// Verify
+	data, err := afero.ReadFile(fs, filepath.Join("/test/related", "resources", "aws_example_widget.json"))
+	require.NoError(t, err)
+	var resource TerraformResource
+	require.NoError(t, json.Unmarshal(data, &resource))
+	assert.Equal(t, []string{"func.resourceWidgetRead.goindex"}, resource.RelatedFunctions)
+
+	data, err = afero.ReadFile(fs, filepath.Join("/test/related", "datasources", "aws_example_widget.json"))
+	require.NoError(t, err)
+	var dataSource TerraformDataSource
+	require.NoError(t, json.Unmarshal(data, &dataSource))
+	assert.Equal(t, []string{"func.dataSourceWidgetLookupRead.goindex"}, dataSource.RelatedFunctions)
+
+	data, err = afero.ReadFile(fs, filepath.Join("/test/related", "services", "example.json"))
+	require.NoError(t, err)
+	var serviceFile ServiceFile
+	require.NoError(t, json.Unmarshal(data, &serviceFile))
+	require.Len(t, serviceFile.Resources, 1)
+	assert.Equal(t, resource.RelatedFunctions, serviceFile.Resources[0].RelatedFunctions)
+}
@@ -0,0 +1,36 @@
+package pkg
+
+// linkResourceTwins marks every resource that has a data source with the same terraform type, and every data source
+// that has a matching resource, e.g. aws_s3_bucket. Twins are matched across all services since a resource and its
+// data source are not always registered by the same service package
+func linkResourceTwins(services []ServiceRegistration) {
+	resourceTypes := make(map[string]bool)
+	dataSourceTypes := make(map[string]bool)
+	for _, service := range services {
+		for _, resources := range []map[string]AWSResource{service.AWSSDKResources, service.AWSFrameworkResources} {
+			for terraformType := range resources {
+				resourceTypes[terraformType] = true
+			}
+		}
+		for _, dataSources := range []map[string]AWSResource{service.AWSSDKDataSources, service.AWSFrameworkDataSources} {
+			for terraformType := range dataSources {
+				dataSourceTypes[terraformType] = true
+			}
+		}
+	}
+
+	for _, service := range services {
+		for _, resources := range []map[string]AWSResource{service.AWSSDKResources, service.AWSFrameworkResources} {
+			for terraformType, resource := range resources {
+				resource.HasDataSourceTwin = dataSourceTypes[terraformType]
+				resources[terraformType] = resource
+			}
+		}
+		for _, dataSources := range []map[string]AWSResource{service.AWSSDKDataSources, service.AWSFrameworkDataSources} {
+			for terraformType, dataSource := range dataSources {
+				dataSource.HasResourceTwin = resourceTypes[terraformType]
+				dataSources[terraformType] = dataSource
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkResourceTwins_AcrossServices(t *testing.T) {
+	// Setup - aws_s3_bucket is a resource in one service and a data source in another,
+	// aws_s3_object only exists as a resource
+	services := []ServiceRegistration{
+		{
+			ServiceName: "s3",
+			AWSSDKResources: map[string]AWSResource{
+				"aws_s3_bucket": {TerraformType: "aws_s3_bucket", FactoryFunction: "resourceBucket"},
+				"aws_s3_object": {TerraformType: "aws_s3_object", FactoryFunction: "resourceObject"},
+			},
+		},
+		{
+			ServiceName: "s3buckets",
+			AWSFrameworkDataSources: map[string]AWSResource{
+				"aws_s3_bucket": {TerraformType: "aws_s3_bucket", StructType: "bucketDataSource"},
+			},
+		},
+	}
+
+	// Execute
+	linkResourceTwins(services)
+
+	// Verify
+	assert.True(t, services[0].AWSSDKResources["aws_s3_bucket"].HasDataSourceTwin)
+	assert.False(t, services[0].AWSSDKResources["aws_s3_object"].HasDataSourceTwin)
+	assert.True(t, services[1].AWSFrameworkDataSources["aws_s3_bucket"].HasResourceTwin)
+
+	assert.True(t, NewTerraformResourceFromAWSSDK(services[0].AWSSDKResources["aws_s3_bucket"], services[0]).HasDataSourceTwin)
+	assert.False(t, NewTerraformResourceFromAWSSDK(services[0].AWSSDKResources["aws_s3_object"], services[0]).HasDataSourceTwin)
+	assert.True(t, NewTerraformDataSourceFromAWSFramework(services[1].AWSFrameworkDataSources["aws_s3_bucket"], services[1]).HasResourceTwin)
+}
+
+func TestTerraformProviderIndex_Merge_LinksResourceTwins(t *testing.T) {
+	// Setup - the twins only meet once both partial indexes are merged
+	index := &TerraformProviderIndex{
+		Version: "v1.0.0",
+		Services: []ServiceRegistration{{
+			ServiceName:     "s3",
+			AWSSDKResources: map[string]AWSResource{"aws_s3_bucket": {TerraformType: "aws_s3_bucket"}},
+		}},
+	}
+	other := &TerraformProviderIndex{
+		Version: "v1.0.0",
+		Services: []ServiceRegistration{{
+			ServiceName:       "s3buckets",
+			AWSSDKDataSources: map[string]AWSResource{"aws_s3_bucket": {TerraformType: "aws_s3_bucket"}},
+		}},
+	}
+
+	// Execute
+	err := index.Merge(other, false)
+
+	// Verify
+	assert.NoError(t, err)
+	assert.True(t, index.Services[0].AWSSDKResources["aws_s3_bucket"].HasDataSourceTwin)
+	assert.True(t, index.Services[1].AWSSDKDataSources["aws_s3_bucket"].HasResourceTwin)
+}
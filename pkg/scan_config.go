@@ -0,0 +1,24 @@
+package pkg
+
+import "context"
+
+// ScanConfig holds the inputs of ScanWithConfig. Options that apply to individual services and terraform
+// types come from the embedded ScanOptions
+type ScanConfig struct {
+	Dir        string // Services directory, e.g. "./terraform-provider-aws/internal/service"
+	BasePkgUrl string // Import path of Dir, e.g. "github.com/hashicorp/terraform-provider-aws/internal/service"
+	Version    string // Provider version recorded in the index, e.g. "v6.3.0"
+
+	Context          context.Context  // Stops dispatching services once done, nil uses context.Background()
+	ProgressCallback ProgressCallback // Receives scan progress, may be nil
+
+	ScanOptions
+}
+
+// context returns the configured Context or context.Background() when none is set
+func (c ScanConfig) context() context.Context {
+	if c.Context == nil {
+		return context.Background()
+	}
+	return c.Context
+}
@@ -0,0 +1,104 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubScanConfigServices serves an SDK resource and data source for each service directory under /services
+func stubScanConfigServices(t *testing.T, services ...string) {
+	fs := afero.NewMemMapFs()
+	for _, service := range services {
+		require.NoError(t, fs.MkdirAll("/services/"+service, 0755))
+	}
+	inputStub := gostub.Stub(&inputFs, fs)
+	t.Cleanup(inputStub.Reset)
+
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		service := filepath.Base(pkgPath)
+		return createMockPackageInfoFromSource(t, pkgPath+"/main.go", basePkgUrl+"/"+service, fmt.Sprintf(`package %[1]s
+
+// @SDKResource("aws_%[1]s_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @SDKDataSource("aws_%[1]s_widget", name="Widget")
+func dataSourceWidget() *schema.Resource {
+	return &schema.Resource{}
+}
+`, service)), nil
+	})
+	t.Cleanup(scanStub.Reset)
+}
+
+func TestScanWithConfig_MatchesPositionalScan(t *testing.T) {
+	stubScanConfigServices(t, "ec2", "lambda", "s3")
+
+	testCases := []struct {
+		name       string
+		positional func() (*TerraformProviderIndex, error)
+		config     ScanConfig
+	}{
+		{
+			name: "Defaults",
+			positional: func() (*TerraformProviderIndex, error) {
+				return ScanTerraformProviderServices("/services", "example.com/provider", "v1.0.0", nil)
+			},
+			config: ScanConfig{Dir: "/services", BasePkgUrl: "example.com/provider", Version: "v1.0.0"},
+		},
+		{
+			name: "Options",
+			positional: func() (*TerraformProviderIndex, error) {
+				return ScanTerraformProviderServicesWithOptions(context.Background(), "/services", "example.com/provider", "v1.0.0", nil,
+					ScanOptions{ExcludeServices: []string{"lambda"}, ExcludeTypes: []string{"aws_s3_*"}})
+			},
+			config: ScanConfig{
+				Dir:         "/services",
+				BasePkgUrl:  "example.com/provider",
+				Version:     "v1.0.0",
+				ScanOptions: ScanOptions{ExcludeServices: []string{"lambda"}, ExcludeTypes: []string{"aws_s3_*"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Execute
+			expected, err := tc.positional()
+			require.NoError(t, err)
+			actual, err := ScanWithConfig(tc.config)
+			require.NoError(t, err)
+
+			// Verify - both entry points produce the same index, the scan duration varies between runs by design
+			expected.Statistics.ScanDurationMs = 0
+			actual.Statistics.ScanDurationMs = 0
+			expectedJSON, err := json.Marshal(expected)
+			require.NoError(t, err)
+			actualJSON, err := json.Marshal(actual)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expectedJSON), string(actualJSON))
+			assert.NotEmpty(t, actual.Services)
+		})
+	}
+}
+
+func TestScanWithConfig_CancelledContext(t *testing.T) {
+	stubScanConfigServices(t, "ec2")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	index, err := ScanWithConfig(ScanConfig{Dir: "/services", BasePkgUrl: "example.com/provider", Version: "v1.0.0", Context: ctx})
+
+	assert.Nil(t, index)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,144 @@
+package pkg
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+	"github.com/spf13/afero"
+)
+
+// ScanOptions controls which services and terraform types are included in a scan
+// Patterns use path.Match glob syntax, e.g. "ec2*" or "aws_s3_*". When a name matches both
+// an include and an exclude pattern, the exclude pattern wins. Empty include lists match everything
+type ScanOptions struct {
+	IncludeServices []string // Service directory names to scan
+	ExcludeServices []string // Service directory names to skip
+	IncludeTypes    []string // Terraform types to index
+	ExcludeTypes    []string // Terraform types to drop
+
+	// Categories limits the scan to the given walk categories, e.g. []string{WalkCategoryDataSource}.
+	// Registrations of other categories are neither extracted nor written. Empty scans every category
+	Categories []string
+
+	// OnlyTaggable keeps only resources and data sources with tags, see AWSTagsConfig.
+	// Ephemeral resources are never tagged and are dropped as well
+	OnlyTaggable bool
+
+	// FailOnEmpty returns an error instead of an empty index when no service registers anything,
+	// catching a wrong scan path in CI
+	FailOnEmpty bool
+
+	Logger Logger // Receives scan diagnostics, defaults to discarding them
+
+	// InputFs is the filesystem the services directory is read from, e.g. an in-memory tree or an embed.FS
+	// wrapped with afero.FromIOFS. Packages are then parsed file by file, see scanPackageFromFs.
+	// Nil reads from the OS filesystem through gophon
+	InputFs afero.Fs
+}
+
+// logger returns the configured Logger or a no-op logger when none is set
+func (o ScanOptions) logger() Logger {
+	if o.Logger == nil {
+		return noopLogger{}
+	}
+	return o.Logger
+}
+
+// inputFs returns the configured InputFs or the package input filesystem when none is set
+func (o ScanOptions) inputFs() afero.Fs {
+	if o.InputFs == nil {
+		return inputFs
+	}
+	return o.InputFs
+}
+
+// scanPackage loads the Go package in dir, from InputFs when set and through gophon otherwise
+func (o ScanOptions) scanPackage(dir, basePkgUrl string) (*gophon.PackageInfo, error) {
+	if o.InputFs == nil {
+		return scanSinglePackage(dir, basePkgUrl)
+	}
+	return scanPackageFromFs(o.InputFs, dir, basePkgUrl)
+}
+
+// validate checks that all patterns are well-formed
+func (o ScanOptions) validate() error {
+	for _, patterns := range [][]string{o.IncludeServices, o.ExcludeServices, o.IncludeTypes, o.ExcludeTypes} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid filter pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	for _, category := range o.Categories {
+		if !slices.Contains(walkCategories, category) {
+			return fmt.Errorf("invalid category %q: must be one of %s", category, strings.Join(walkCategories, ", "))
+		}
+	}
+	return nil
+}
+
+// includesCategory reports whether registrations of the walk category should be extracted and written
+func (o ScanOptions) includesCategory(category string) bool {
+	return len(o.Categories) == 0 || slices.Contains(o.Categories, category)
+}
+
+// includesAnnotationType reports whether registrations of the annotation type should be extracted
+func (o ScanOptions) includesAnnotationType(annotationType AnnotationType) bool {
+	return o.includesCategory(annotationCategory(annotationType))
+}
+
+// includesService reports whether the service directory should be scanned
+func (o ScanOptions) includesService(serviceName string) bool {
+	return matchesFilter(serviceName, o.IncludeServices, o.ExcludeServices)
+}
+
+// includesType reports whether the terraform type should be indexed
+func (o ScanOptions) includesType(terraformType string) bool {
+	return matchesFilter(terraformType, o.IncludeTypes, o.ExcludeTypes)
+}
+
+// includesAnnotation reports whether an annotation result should be indexed
+func (o ScanOptions) includesAnnotation(result AnnotationResult) bool {
+	if o.OnlyTaggable && (result.Tags == nil || !result.Tags.HasTags) {
+		return false
+	}
+	return o.includesType(result.TerraformType)
+}
+
+// filterAnnotationResults returns the annotation results that pass the type and tag filters
+func (o ScanOptions) filterAnnotationResults(results *AnnotationResults) *AnnotationResults {
+	if len(o.IncludeTypes) == 0 && len(o.ExcludeTypes) == 0 && !o.OnlyTaggable {
+		return results
+	}
+
+	filtered := NewAnnotationResults()
+	for _, result := range results.GetAll() {
+		if o.includesAnnotation(result) {
+			filtered.Add(result)
+		}
+	}
+	return filtered
+}
+
+// matchesFilter applies include and exclude glob patterns to name, exclude taking precedence
+func matchesFilter(name string, include, exclude []string) bool {
+	for _, pattern := range exclude {
+		if matched, _ := path.Match(pattern, name); matched {
+			return false
+		}
+	}
+
+	if len(include) == 0 {
+		return true
+	}
+
+	for _, pattern := range include {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
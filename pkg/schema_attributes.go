@@ -0,0 +1,503 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
+
+// extractFrameworkSchemaAttributes extracts the top-level attribute and block names declared
+// in the Schema method of a framework struct type
+// For example, from: resp.Schema = schema.Schema{Attributes: map[string]schema.Attribute{"name": ...}}
+// It extracts: ["name"]
+func extractFrameworkSchemaAttributes(packageInfo *gophon.PackageInfo, structType string) []string {
+	schemaMethod := findMethodDecl(packageInfo, structType, "Schema")
+	if schemaMethod == nil || schemaMethod.Body == nil {
+		return nil
+	}
+
+	attributeSet := make(map[string]bool)
+	ast.Inspect(schemaMethod.Body, func(n ast.Node) bool {
+		compositeLit, ok := n.(*ast.CompositeLit)
+		if !ok || !isSchemaSchemaType(compositeLit.Type) {
+			return true
+		}
+
+		for _, elt := range compositeLit.Elts {
+			keyValue, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			key, ok := keyValue.Key.(*ast.Ident)
+			if !ok || (key.Name != "Attributes" && key.Name != "Blocks") {
+				continue
+			}
+
+			mapLit, ok := keyValue.Value.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+
+			for _, attr := range mapLit.Elts {
+				attrKeyValue, ok := attr.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				if name := schemaAttributeKeyName(attrKeyValue.Key); name != "" {
+					attributeSet[name] = true
+				}
+			}
+		}
+
+		// Nested attributes belong to their parent block, don't descend further
+		return false
+	})
+
+	if len(attributeSet) == 0 {
+		return nil
+	}
+
+	attributes := make([]string, 0, len(attributeSet))
+	for name := range attributeSet {
+		attributes = append(attributes, name)
+	}
+	sort.Strings(attributes)
+
+	return attributes
+}
+
+// extractSDKSchemaAttributes extracts the top-level attribute names of the schema.Resource returned by an SDK
+// factory function, along with the child attribute names of blocks declared through Elem: &schema.Resource{...}.
+// Nested blocks are followed one level deep only, their own nested blocks are listed by name but not expanded
+func extractSDKSchemaAttributes(packageInfo *gophon.PackageInfo, factoryFunction string) ([]string, map[string][]string) {
+	resourceLit := sdkSchemaResourceLit(packageInfo, factoryFunction)
+	if resourceLit == nil {
+		return nil, nil
+	}
+
+	attributes, blocks := sdkSchemaAttributeNames(resourceLit)
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+
+	var nested map[string][]string
+	for name, blockLit := range blocks {
+		children, _ := sdkSchemaAttributeNames(blockLit)
+		if len(children) == 0 {
+			continue
+		}
+		if nested == nil {
+			nested = make(map[string][]string)
+		}
+		nested[name] = children
+	}
+
+	return attributes, nested
+}
+
+// extractSDKSchemaDefaultFuncs returns the package functions supplying plan-time defaults of the top-level
+// attributes of an SDK factory's schema, keyed by attribute name. Both `DefaultFunc: attrDefault` and a call to a
+// package function building the default, e.g. `DefaultFunc: envDefault("AWS_REGION")` or `Default: defaultName()`,
+// are recorded. Defaults from other packages such as schema.EnvDefaultFunc have no function index and are skipped
+func extractSDKSchemaDefaultFuncs(packageInfo *gophon.PackageInfo, factoryFunction string) map[string]string {
+	resourceLit := sdkSchemaResourceLit(packageInfo, factoryFunction)
+	if resourceLit == nil {
+		return nil
+	}
+
+	var defaultFuncs map[string]string
+	for name, attrLit := range sdkSchemaAttributeLits(resourceLit) {
+		for _, elt := range attrLit.Elts {
+			keyValue, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := keyValue.Key.(*ast.Ident)
+			if !ok || (key.Name != "Default" && key.Name != "DefaultFunc") {
+				continue
+			}
+
+			var funcName string
+			switch value := keyValue.Value.(type) {
+			case *ast.Ident:
+				// A bare identifier is only a function reference for DefaultFunc, Default: x is a value
+				if key.Name == "DefaultFunc" && findFuncDecl(packageInfo, value.Name) != nil {
+					funcName = value.Name
+				}
+			case *ast.CallExpr:
+				if fun, ok := value.Fun.(*ast.Ident); ok && findFuncDecl(packageInfo, fun.Name) != nil {
+					funcName = fun.Name
+				}
+			}
+			if funcName == "" {
+				continue
+			}
+			if defaultFuncs == nil {
+				defaultFuncs = make(map[string]string)
+			}
+			defaultFuncs[name] = funcName
+		}
+	}
+
+	return defaultFuncs
+}
+
+// extractSDKSchemaWriteOnlyAttributes returns the sorted top-level attributes of an SDK factory's schema declared
+// with `WriteOnly: true`, whose values are never persisted to plan or state
+func extractSDKSchemaWriteOnlyAttributes(packageInfo *gophon.PackageInfo, factoryFunction string) []string {
+	resourceLit := sdkSchemaResourceLit(packageInfo, factoryFunction)
+	if resourceLit == nil {
+		return nil
+	}
+	return writeOnlyAttributes(sdkSchemaAttributeLits(resourceLit))
+}
+
+// extractFrameworkSchemaWriteOnlyAttributes returns the sorted top-level attributes declared with `WriteOnly: true`
+// in the Schema method of a framework struct type, e.g. "password": schema.StringAttribute{WriteOnly: true}
+func extractFrameworkSchemaWriteOnlyAttributes(packageInfo *gophon.PackageInfo, structType string) []string {
+	schemaMethod := findMethodDecl(packageInfo, structType, "Schema")
+	if schemaMethod == nil || schemaMethod.Body == nil {
+		return nil
+	}
+
+	attrLits := make(map[string]*ast.CompositeLit)
+	ast.Inspect(schemaMethod.Body, func(n ast.Node) bool {
+		compositeLit, ok := n.(*ast.CompositeLit)
+		if !ok || !isSchemaSchemaType(compositeLit.Type) {
+			return true
+		}
+
+		for _, elt := range compositeLit.Elts {
+			keyValue, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := keyValue.Key.(*ast.Ident)
+			if !ok || key.Name != "Attributes" {
+				continue
+			}
+			mapLit, ok := keyValue.Value.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+
+			for _, attr := range mapLit.Elts {
+				attrKeyValue, ok := attr.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				name := schemaAttributeKeyName(attrKeyValue.Key)
+				if attrLit, ok := attrKeyValue.Value.(*ast.CompositeLit); ok && name != "" {
+					attrLits[name] = attrLit
+				}
+			}
+		}
+
+		// Nested attributes belong to their parent block, don't descend further
+		return false
+	})
+
+	return writeOnlyAttributes(attrLits)
+}
+
+// writeOnlyAttributes returns the sorted names of the attribute literals setting `WriteOnly: true`, nil when none do
+func writeOnlyAttributes(attrLits map[string]*ast.CompositeLit) []string {
+	var names []string
+	for name, attrLit := range attrLits {
+		for _, elt := range attrLit.Elts {
+			keyValue, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := keyValue.Key.(*ast.Ident)
+			if !ok || key.Name != "WriteOnly" {
+				continue
+			}
+			if value, ok := keyValue.Value.(*ast.Ident); ok && value.Name == "true" {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sdkSchemaResourceLit returns the schema.Resource literal returned by an SDK factory function, nil when the
+// factory is not declared in the package or builds its resource in a way that can't be followed statically
+func sdkSchemaResourceLit(packageInfo *gophon.PackageInfo, factoryFunction string) *ast.CompositeLit {
+	factory := findFuncDecl(packageInfo, factoryFunction)
+	if factory == nil || factory.Body == nil {
+		return nil
+	}
+
+	// Variables assigned &schema.Resource{...} so `return r` resolves like for CRUD extraction
+	assigned := collectSchemaResourceAssignments(factory.Body)
+	var resourceLit *ast.CompositeLit
+	ast.Inspect(factory.Body, func(n ast.Node) bool {
+		if resourceLit != nil {
+			return false
+		}
+		returnStmt, ok := n.(*ast.ReturnStmt)
+		if !ok {
+			return true
+		}
+		for _, result := range returnStmt.Results {
+			if compositeLit := resolveReturnedCompositeLit(result, assigned); compositeLit != nil && isSchemaResourceType(compositeLit.Type) {
+				resourceLit = compositeLit
+				return false
+			}
+		}
+		return true
+	})
+
+	return resourceLit
+}
+
+// sdkSchemaAttributeNames returns the sorted attribute names of the Schema map of a schema.Resource literal, and
+// the schema.Resource literal of every attribute whose Elem is one, keyed by attribute name
+func sdkSchemaAttributeNames(resourceLit *ast.CompositeLit) ([]string, map[string]*ast.CompositeLit) {
+	var attributes []string
+	blocks := make(map[string]*ast.CompositeLit)
+
+	for _, elt := range resourceLit.Elts {
+		keyValue, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := keyValue.Key.(*ast.Ident)
+		if !ok || key.Name != "Schema" {
+			continue
+		}
+		mapLit, ok := keyValue.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+
+		for _, attr := range mapLit.Elts {
+			attrKeyValue, ok := attr.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			name := schemaAttributeKeyName(attrKeyValue.Key)
+			if name == "" {
+				continue
+			}
+			attributes = append(attributes, name)
+
+			// Map values are usually `{...}` with the *schema.Schema type elided, sometimes &schema.Schema{...}
+			attrLit, ok := attrKeyValue.Value.(*ast.CompositeLit)
+			if !ok {
+				attrLit = addressOfCompositeLit(attrKeyValue.Value)
+			}
+			if attrLit == nil {
+				continue
+			}
+			if blockLit := sdkSchemaElemResource(attrLit); blockLit != nil {
+				blocks[name] = blockLit
+			}
+		}
+	}
+
+	sort.Strings(attributes)
+	return attributes, blocks
+}
+
+// sdkSchemaAttributeLits returns the schema.Schema literal of every attribute of the Schema map of a
+// schema.Resource literal, keyed by attribute name. Attributes built by a function call are left out
+func sdkSchemaAttributeLits(resourceLit *ast.CompositeLit) map[string]*ast.CompositeLit {
+	attrLits := make(map[string]*ast.CompositeLit)
+	for _, elt := range resourceLit.Elts {
+		keyValue, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := keyValue.Key.(*ast.Ident)
+		if !ok || key.Name != "Schema" {
+			continue
+		}
+		mapLit, ok := keyValue.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+
+		for _, attr := range mapLit.Elts {
+			attrKeyValue, ok := attr.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			name := schemaAttributeKeyName(attrKeyValue.Key)
+			if name == "" {
+				continue
+			}
+			attrLit, ok := attrKeyValue.Value.(*ast.CompositeLit)
+			if !ok {
+				attrLit = addressOfCompositeLit(attrKeyValue.Value)
+			}
+			if attrLit != nil {
+				attrLits[name] = attrLit
+			}
+		}
+	}
+	return attrLits
+}
+
+// sdkSchemaElemResource returns the schema.Resource literal of an attribute's Elem, nil for primitive elements
+func sdkSchemaElemResource(attrLit *ast.CompositeLit) *ast.CompositeLit {
+	for _, elt := range attrLit.Elts {
+		keyValue, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := keyValue.Key.(*ast.Ident); !ok || key.Name != "Elem" {
+			continue
+		}
+		if compositeLit := addressOfCompositeLit(keyValue.Value); compositeLit != nil && isSchemaResourceType(compositeLit.Type) {
+			return compositeLit
+		}
+	}
+	return nil
+}
+
+// findFuncDecl finds the declaration of a package-level function across all files in the package
+func findFuncDecl(packageInfo *gophon.PackageInfo, funcName string) *ast.FuncDecl {
+	if packageInfo == nil || funcName == "" {
+		return nil
+	}
+
+	for _, fileInfo := range packageInfo.Files {
+		if fileInfo == nil || fileInfo.File == nil {
+			continue
+		}
+
+		for _, decl := range fileInfo.File.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if ok && funcDecl.Recv == nil && funcDecl.Name.Name == funcName {
+				return funcDecl
+			}
+		}
+	}
+
+	return nil
+}
+
+// findMethodDecl finds the declaration of a method on the given struct type across all files in the package
+func findMethodDecl(packageInfo *gophon.PackageInfo, structType, methodName string) *ast.FuncDecl {
+	if packageInfo == nil || structType == "" {
+		return nil
+	}
+
+	for _, fileInfo := range packageInfo.Files {
+		if fileInfo == nil || fileInfo.File == nil {
+			continue
+		}
+
+		for _, decl := range fileInfo.File.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || funcDecl.Name.Name != methodName {
+				continue
+			}
+
+			if receiverTypeName(funcDecl) == structType {
+				return funcDecl
+			}
+		}
+	}
+
+	return nil
+}
+
+// receiverTypeName returns the receiver type name of a method, stripping any pointer and type parameters,
+// so `(r *fooResource)`, `(r fooResource)` and `(r *fooResource[T])` all yield "fooResource".
+// Every place that maps a method to its struct must go through it so pointer and value receivers match alike
+func receiverTypeName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+
+	recvType := funcDecl.Recv.List[0].Type
+	if starExpr, ok := recvType.(*ast.StarExpr); ok {
+		recvType = starExpr.X
+	}
+
+	switch typeExpr := recvType.(type) {
+	case *ast.IndexExpr:
+		recvType = typeExpr.X
+	case *ast.IndexListExpr:
+		recvType = typeExpr.X
+	}
+
+	if ident, ok := recvType.(*ast.Ident); ok {
+		return ident.Name
+	}
+
+	return ""
+}
+
+// receiverVarName returns the receiver variable name of a method, empty when the receiver is unnamed
+func receiverVarName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 || len(funcDecl.Recv.List[0].Names) == 0 {
+		return ""
+	}
+	return funcDecl.Recv.List[0].Names[0].Name
+}
+
+// isSchemaSchemaType checks whether an expression is the schema.Schema type
+func isSchemaSchemaType(expr ast.Expr) bool {
+	selectorExpr, ok := expr.(*ast.SelectorExpr)
+	if !ok || selectorExpr.Sel.Name != "Schema" {
+		return false
+	}
+
+	pkgIdent, ok := selectorExpr.X.(*ast.Ident)
+	return ok && pkgIdent.Name == "schema"
+}
+
+// schemaAttributeKeyName resolves an attribute map key to its attribute name
+// Handles both string literals ("guardrail_arn") and names package constants (names.AttrCreatedAt)
+func schemaAttributeKeyName(expr ast.Expr) string {
+	switch key := expr.(type) {
+	case *ast.BasicLit:
+		if key.Kind != token.STRING {
+			return ""
+		}
+		name, err := strconv.Unquote(key.Value)
+		if err != nil {
+			return ""
+		}
+		return name
+	case *ast.SelectorExpr:
+		pkgIdent, ok := key.X.(*ast.Ident)
+		if !ok || pkgIdent.Name != "names" || !strings.HasPrefix(key.Sel.Name, "Attr") {
+			return ""
+		}
+		return camelCaseToSnakeCase(strings.TrimPrefix(key.Sel.Name, "Attr"))
+	}
+
+	return ""
+}
+
+// camelCaseToSnakeCase converts a CamelCase identifier into snake_case, keeping acronyms together
+// For example: "KMSKeyARN" -> "kms_key_arn", "CreatedAt" -> "created_at"
+func camelCaseToSnakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				builder.WriteRune('_')
+			}
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+
+	return builder.String()
+}
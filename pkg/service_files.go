@@ -0,0 +1,110 @@
+package pkg
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// ServiceFile is the content of services/<service_name>.json written by WriteServiceFiles
+type ServiceFile struct {
+	ServiceName         string                `json:"service_name"`
+	PackagePath         string                `json:"package_path"`
+	PackageName         string                `json:"package_name,omitempty"`
+	MixedImplementation bool                  `json:"mixed_implementation,omitempty"` // Registers both SDK and Framework resources
+	Resources           []TerraformResource   `json:"resources"`                      // SDK before framework, sorted by terraform type
+	DataSources         []TerraformDataSource `json:"data_sources"`                   // SDK before framework, sorted by terraform type
+	EphemeralResources  []TerraformEphemeral  `json:"ephemeral_resources"`            // Sorted by terraform type
+}
+
+// newServiceFile converts every entry of a service the same way the per-entry index files are written
+func newServiceFile(service *ServiceRegistration) ServiceFile {
+	serviceFile := ServiceFile{
+		ServiceName:         service.ServiceName,
+		PackagePath:         service.PackagePath,
+		PackageName:         service.PackageName,
+		MixedImplementation: service.MixedImplementation,
+		Resources:           []TerraformResource{},
+		DataSources:         []TerraformDataSource{},
+		EphemeralResources:  []TerraformEphemeral{},
+	}
+
+	_ = walkServiceEntries(service, func(category string, framework bool, service *ServiceRegistration, resource AWSResource) error {
+		switch entry := convertWalkedEntry(category, framework, service, resource).(type) {
+		case TerraformResource:
+			serviceFile.Resources = append(serviceFile.Resources, entry)
+		case TerraformDataSource:
+			serviceFile.DataSources = append(serviceFile.DataSources, entry)
+		case TerraformEphemeral:
+			serviceFile.EphemeralResources = append(serviceFile.EphemeralResources, entry)
+		}
+		return nil
+	})
+
+	return serviceFile
+}
+
+// WriteServiceFiles writes one JSON file per service to services/<service_name>.json, listing the service's
+// resources, data sources and ephemeral resources. The services directory is created even if there are no services
+func (index *TerraformProviderIndex) WriteServiceFiles(outputDir string, progressTracker *ProgressTracker) error {
+	servicesDir := filepath.Join(outputDir, serviceFilesDir)
+
+	// Ensure services directory exists even if no files will be written
+	if err := outputFs.MkdirAll(servicesDir, 0755); err != nil {
+		return fmt.Errorf("failed to create services directory %s: %w", servicesDir, err)
+	}
+
+	var tasks []func() error
+
+	for i := range index.Services {
+		// Capture variables for closure
+		svc := &index.Services[i]
+
+		tasks = append(tasks, func() error {
+			fileName := fmt.Sprintf("%s.json", svc.ServiceName)
+			filePath := filepath.Join(servicesDir, fileName)
+
+			serviceFile := newServiceFile(svc)
+			index.applyEntryOptions(&serviceFile, svc)
+			if err := index.WriteJSONFile(filePath, serviceFile); err != nil {
+				return fmt.Errorf("failed to write service file %s: %w", fileName, err)
+			}
+
+			if progressTracker != nil {
+				progressTracker.UpdateProgress(fmt.Sprintf("service %s", svc.ServiceName))
+			}
+			return nil
+		})
+	}
+
+	return processCallbacksParallel(tasks)
+}
+
+// applyEntryOptions applies the output options shaping entry files, OutputOptions.SDKTypePrefix and
+// OutputOptions.RelatedFunctions, to every entry of the service file of service
+func (index *TerraformProviderIndex) applyEntryOptions(serviceFile *ServiceFile, service *ServiceRegistration) {
+	for i := range serviceFile.Resources {
+		resource := &serviceFile.Resources[i]
+		resource.SDKType = index.sdkTypeLabel(resource.SDKType)
+		resource.RelatedFunctions = index.relatedFunctions(service, factoryFunctionOf(resource.TerraformType,
+			service.AWSSDKResources, service.AWSFrameworkResources))
+	}
+	for i := range serviceFile.DataSources {
+		dataSource := &serviceFile.DataSources[i]
+		dataSource.SDKType = index.sdkTypeLabel(dataSource.SDKType)
+		dataSource.RelatedFunctions = index.relatedFunctions(service, factoryFunctionOf(dataSource.TerraformType,
+			service.AWSSDKDataSources, service.AWSFrameworkDataSources))
+	}
+	for i := range serviceFile.EphemeralResources {
+		serviceFile.EphemeralResources[i].SDKType = index.sdkTypeLabel(serviceFile.EphemeralResources[i].SDKType)
+	}
+}
+
+// factoryFunctionOf returns the declared factory function of terraformType in the first registration map listing it
+func factoryFunctionOf(terraformType string, registrations ...map[string]AWSResource) string {
+	for _, resources := range registrations {
+		if resource, exists := resources[terraformType]; exists {
+			return resource.declaredFactory()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_WriteServiceFiles(t *testing.T) {
+	// Setup - s3 has entries, the empty service still gets a file
+	sut := createTestTerraformProviderIndex()
+	sut.Services = append(sut.Services, CreateTestServiceRegistration("empty"))
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+
+	// Execute
+	err := sut.WriteServiceFiles(outputDir, nil)
+
+	// Verify
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "services", "s3.json"))
+	require.NoError(t, err)
+	var serviceFile ServiceFile
+	require.NoError(t, json.Unmarshal(data, &serviceFile))
+	assert.Equal(t, "s3", serviceFile.ServiceName)
+	assert.Equal(t, sut.Services[0].PackagePath, serviceFile.PackagePath)
+
+	var resourceTypes []string
+	for _, resource := range serviceFile.Resources {
+		resourceTypes = append(resourceTypes, resource.TerraformType)
+	}
+	assert.Equal(t, []string{"aws_s3_bucket_policy", "aws_s3_bucket"}, resourceTypes)
+	require.Len(t, serviceFile.DataSources, 1)
+	assert.Equal(t, "aws_s3_bucket", serviceFile.DataSources[0].TerraformType)
+	assert.Equal(t, "method.bucketResource.Schema.goindex", serviceFile.Resources[1].SchemaIndex)
+
+	data, err = afero.ReadFile(fs, filepath.Join(outputDir, "services", "empty.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"resources": []`)
+}
+
+func TestTerraformProviderIndex_WriteIndexFilesWithOptions_ServiceFiles(t *testing.T) {
+	// Setup
+	sut := createTestTerraformProviderIndex()
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+	options := OutputOptions{ServiceFiles: true}
+
+	// Execute
+	require.NoError(t, sut.WriteIndexFiles("/test/plain", nil))
+	err := sut.WriteIndexFilesWithOptions(outputDir, nil, options)
+
+	// Verify - service files are opt-in, planned and listed in the manifest
+	require.NoError(t, err)
+	exists, err := afero.DirExists(fs, "/test/plain/services")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	plan, err := sut.PlanIndexFilesWithOptions(outputDir, options)
+	require.NoError(t, err)
+	assert.Contains(t, plan.Files, PlannedFile{Path: "services/s3.json", Category: WritePlanCategoryService})
+
+	manifest, err := sut.BuildManifest(outputDir)
+	require.NoError(t, err)
+	assert.Contains(t, manifest, "services/s3.json")
+}
@@ -1,8 +1,9 @@
 package pkg
 
 import (
+	"path"
+
 	gophon "github.com/lonegunmanb/gophon/pkg"
-	"os"
 )
 
 // ServiceRegistration represents all registration methods found in a single service package
@@ -10,6 +11,10 @@ type ServiceRegistration struct {
 	Package     *gophon.PackageInfo `json:"-"`
 	ServiceName string              `json:"service_name"` // "s3", "ec2", etc.
 	PackagePath string              `json:"package_path"` // "internal/service/s3"
+	PackageName string              `json:"package_name"` // Go package name from the package clause, "s3"
+
+	// Set when the service registers both SDK and Framework resources, i.e. it is served through the plugin mux
+	MixedImplementation bool `json:"mixed_implementation,omitempty"`
 
 	// AWS 5-category structure (NEW)
 	AWSSDKResources         map[string]AWSResource `json:"aws_sdk_resources"`          // SDK resources from SDKResources()
@@ -26,13 +31,24 @@ type ServiceRegistration struct {
 	// CRUD method mappings for SDK resources (function-based)
 	ResourceCRUDMethods map[string]*LegacyResourceCRUDFunctions `json:"resource_crud_methods,omitempty"` // CRUD methods for SDK resources
 	DataSourceMethods   map[string]*LegacyDataSourceMethods     `json:"data_source_methods,omitempty"`   // Methods for SDK data sources
+
+	// Method mappings for Framework resources (struct-based)
+	FrameworkResourceMethods map[string]*AWSCRUDMethods `json:"framework_resource_methods,omitempty"` // Methods declared on Framework resource structs
+
+	// Annotated entries that could not be fully resolved
+	PartialEntries []PartialEntry `json:"partial_entries,omitempty"`
+
+	// Problems found while scanning the service: conflicting registrations, factories returning the wrong type,
+	// annotations without a discoverable struct or CRUD methods, and factory functions not declared in the package
+	Warnings []string `json:"warnings,omitempty"`
 }
 
-func newServiceRegistration(packageInfo *gophon.PackageInfo, entry os.FileInfo) ServiceRegistration {
+func newServiceRegistration(packageInfo *gophon.PackageInfo, serviceName string) ServiceRegistration {
 	svc := ServiceRegistration{
 		Package:     packageInfo,
-		ServiceName: entry.Name(),
+		ServiceName: serviceName,
 		PackagePath: packageInfo.Files[0].Package,
+		PackageName: packageName(packageInfo),
 
 		// AWS 5-category structure (NEW)
 		AWSSDKResources:         make(map[string]AWSResource),
@@ -44,9 +60,45 @@ func newServiceRegistration(packageInfo *gophon.PackageInfo, entry os.FileInfo)
 		// Terraform type mappings and CRUD methods
 		ResourceCRUDMethods:      make(map[string]*LegacyResourceCRUDFunctions),
 		DataSourceMethods:        make(map[string]*LegacyDataSourceMethods),
+		FrameworkResourceMethods: make(map[string]*AWSCRUDMethods),
 		ResourceTerraformTypes:   make(map[string]string),
 		DataSourceTerraformTypes: make(map[string]string),
 		EphemeralTerraformTypes:  make(map[string]string),
 	}
 	return svc
 }
+
+// hasRegistrations reports whether the service registers at least one AWS resource of any category
+func (s ServiceRegistration) hasRegistrations() bool {
+	return len(s.AWSSDKResources) > 0 || len(s.AWSSDKDataSources) > 0 ||
+		len(s.AWSFrameworkResources) > 0 || len(s.AWSFrameworkDataSources) > 0 ||
+		len(s.AWSEphemeralResources) > 0
+}
+
+// legacyEphemeralTerraformTypes returns the EphemeralTerraformTypes entries whose terraform type is not also
+// registered in AWSEphemeralResources, i.e. the ephemeral files written only from the legacy mapping
+func (s ServiceRegistration) legacyEphemeralTerraformTypes() map[string]string {
+	legacy := make(map[string]string)
+	for structType, terraformType := range s.EphemeralTerraformTypes {
+		if _, exists := s.AWSEphemeralResources[terraformType]; !exists {
+			legacy[structType] = terraformType
+		}
+	}
+	return legacy
+}
+
+// isMixedImplementation reports whether the service registers resources through both the SDK and the Framework
+func (s ServiceRegistration) isMixedImplementation() bool {
+	return len(s.AWSSDKResources) > 0 && len(s.AWSFrameworkResources) > 0
+}
+
+// packageName returns the Go package name declared by the package's files, falling back to the
+// last element of the import path when no file has a parsed package clause
+func packageName(packageInfo *gophon.PackageInfo) string {
+	for _, fileInfo := range packageInfo.Files {
+		if fileInfo != nil && fileInfo.File != nil && fileInfo.File.Name != nil {
+			return fileInfo.File.Name.Name
+		}
+	}
+	return path.Base(packageInfo.Files[0].Package)
+}
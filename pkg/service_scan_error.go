@@ -0,0 +1,7 @@
+package pkg
+
+// ServiceScanError records a service package that could not be scanned or parsed
+type ServiceScanError struct {
+	ServiceName string `json:"service_name"` // "s3", "ec2", etc.
+	Error       string `json:"error"`        // Human readable failure reason
+}
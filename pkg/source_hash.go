@@ -0,0 +1,29 @@
+package pkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"go/ast"
+	"go/printer"
+	"go/token"
+)
+
+// funcSourceHash returns the hex SHA-256 of funcDecl printed back to Go source without its doc comment, or an
+// empty string for a nil declaration. The scanned packages keep no FileSet to slice the original bytes with, so
+// the AST is printed instead: formatting and comment edits leave the hash unchanged, code changes do not
+func funcSourceHash(funcDecl *ast.FuncDecl) string {
+	if funcDecl == nil {
+		return ""
+	}
+
+	withoutDoc := *funcDecl
+	withoutDoc.Doc = nil
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), &withoutDoc); err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTerraformResourceFromAWSSDK_SourceHash(t *testing.T) {
+	sourceHash := func(src string) string {
+		serviceReg, err := ParseServiceSource([]byte(src), "example.com/provider/widget")
+		require.NoError(t, err)
+		return NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_widget"], *serviceReg).SourceHash
+	}
+
+	original := sourceHash(`package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+`)
+	// Same code with other formatting and comments, below an unrelated function
+	reformatted := sourceHash(`package widget
+
+func unrelated() {}
+
+// Widget resource
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	// Read only
+	return &schema.Resource{ReadWithoutTimeout: resourceWidgetRead}
+}
+`)
+	modified := sourceHash(`package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout:   resourceWidgetRead,
+		DeleteWithoutTimeout: resourceWidgetDelete,
+	}
+}
+`)
+
+	assert.Len(t, original, 64)
+	assert.Equal(t, original, reformatted)
+	assert.NotEqual(t, original, modified)
+}
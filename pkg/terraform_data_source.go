@@ -1,17 +1,41 @@
 package pkg
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+)
 
 // TerraformDataSource represents information about a Terraform data source
 type TerraformDataSource struct {
+	ID                 string `json:"id"`       // Stable hash of namespace, terraform type and category
+	Category           string `json:"category"` // Walk category matching the output directory, e.g. "datasource"
 	TerraformType      string `json:"terraform_type"`
+	DisplayName        string `json:"display_name,omitempty"` // Registration name, or one derived from the terraform type, e.g. "S3 Bucket"
 	StructType         string `json:"struct_type"`
-	Namespace          string `json:"namespace"`           // "github.com/hashicorp/terraform-provider-aws/internal/service"
-	RegistrationMethod string `json:"registration_method"` // "func.SupportedDataSources", "DataSources", etc.
-	SDKType            string `json:"sdk_type"`            // "legacy_pluginsdk", "modern_sdk"
+	Namespace          string `json:"namespace"`              // "github.com/hashicorp/terraform-provider-aws/internal/service"
+	PackageName        string `json:"package_name,omitempty"` // Go package name, e.g. "s3"
+	RegistrationMethod string `json:"registration_method"`    // "func.SupportedDataSources", "DataSources", etc.
+	SDKType            string `json:"sdk_type"`               // "legacy_pluginsdk", "modern_sdk"
 	SchemaIndex        string `json:"schema_index,omitempty"`
 	ReadIndex          string `json:"read_index,omitempty"`
 	AttributeIndex     string `json:"attribute_index,omitempty"`
+	RegistrationFile   string `json:"registration_file,omitempty"` // Source file declaring the entry
+	RawAnnotation      string `json:"raw_annotation,omitempty"`    // Registration annotation as written in the source
+	HasResourceTwin    bool   `json:"has_resource_twin,omitempty"` // A resource with the same terraform type exists
+
+	// Tags of the //go:build constraint of the registration file, the entry only exists in builds satisfying it
+	BuildConstrained bool     `json:"build_constrained,omitempty"`
+	BuildTags        []string `json:"build_tags,omitempty"`
+
+	// The schema declares a top-level "filter" attribute or block, e.g. `names.AttrFilter: customFiltersSchema()`
+	SupportsFilters bool `json:"supports_filters,omitempty"`
+
+	// Indexes of unexported package functions referenced by the factory, e.g. expand and flatten helpers.
+	// Only set with OutputOptions.RelatedFunctions
+	RelatedFunctions []string `json:"related_functions,omitempty"`
+
+	// Acceptance test generation hints declared by @Testing, keyed by annotation argument, e.g. "importIgnore"
+	TestingConfig map[string]string `json:"testing_config,omitempty"`
 }
 
 // NewTerraformDataSourceInfo creates a TerraformDataSource struct
@@ -25,9 +49,12 @@ func NewTerraformDataSourceInfo(terraformType, structType, registrationMethod, s
 		}
 
 		return TerraformDataSource{
+			ID:                 entryID(serviceReg.PackagePath, terraformType, WalkCategoryDataSource),
+			Category:           WalkCategoryDataSource,
 			TerraformType:      terraformType,
 			StructType:         "",
 			Namespace:          serviceReg.PackagePath,
+			PackageName:        serviceReg.PackageName,
 			RegistrationMethod: registrationMethod,
 			SDKType:            sdkType,
 			// Optional fields can be added later when we have more sophisticated AST parsing
@@ -37,9 +64,12 @@ func NewTerraformDataSourceInfo(terraformType, structType, registrationMethod, s
 		}
 	}
 	return TerraformDataSource{
+		ID:                 entryID(serviceReg.PackagePath, serviceReg.DataSourceTerraformTypes[structType], WalkCategoryDataSource),
+		Category:           WalkCategoryDataSource,
 		TerraformType:      serviceReg.DataSourceTerraformTypes[structType],
 		StructType:         structType,
 		Namespace:          serviceReg.PackagePath,
+		PackageName:        serviceReg.PackageName,
 		RegistrationMethod: "",
 		SDKType:            sdkType,
 		// Optional fields can be added later when we have more sophisticated AST parsing
@@ -52,9 +82,9 @@ func NewTerraformDataSourceInfo(terraformType, structType, registrationMethod, s
 // NewTerraformDataSourceFromAWSSDK creates a TerraformDataSource struct from AWS SDK data source info
 func NewTerraformDataSourceFromAWSSDK(awsDataSource AWSResource, serviceReg ServiceRegistration) TerraformDataSource {
 	// Use specific data source methods if available, otherwise fall back to factory function
-	schemaIndex := fmt.Sprintf("func.%s.goindex", awsDataSource.FactoryFunction)
+	schemaIndex := fmt.Sprintf("func.%s.goindex", awsDataSource.declaredFactory())
 	var readIndex string
-	attributeIndex := fmt.Sprintf("func.%s.goindex", awsDataSource.FactoryFunction)
+	attributeIndex := fmt.Sprintf("func.%s.goindex", awsDataSource.declaredFactory())
 
 	// Check if we have extracted data source methods for this terraform type
 	if dataSourceMethods, exists := serviceReg.DataSourceMethods[awsDataSource.TerraformType]; exists && dataSourceMethods != nil {
@@ -63,15 +93,32 @@ func NewTerraformDataSourceFromAWSSDK(awsDataSource AWSResource, serviceReg Serv
 		}
 	}
 
+	// Older service packages register the data source through a map instead of an annotation
+	registrationMethod := "SDKDataSources"
+	if awsDataSource.RegistrationMethod != "" {
+		registrationMethod = awsDataSource.RegistrationMethod
+	}
+
 	return TerraformDataSource{
+		ID:                 entryID(serviceReg.PackagePath, awsDataSource.TerraformType, WalkCategoryDataSource),
+		Category:           WalkCategoryDataSource,
 		TerraformType:      awsDataSource.TerraformType,
+		DisplayName:        displayName(awsDataSource),
 		StructType:         "", // AWS SDK data sources don't have struct types
 		Namespace:          serviceReg.PackagePath,
-		RegistrationMethod: "SDKDataSources",
+		PackageName:        serviceReg.PackageName,
+		RegistrationMethod: registrationMethod,
 		SDKType:            "aws_sdk",
 		SchemaIndex:        schemaIndex,
 		ReadIndex:          readIndex,
 		AttributeIndex:     attributeIndex,
+		RegistrationFile:   awsDataSource.RegistrationFile,
+		RawAnnotation:      awsDataSource.RawAnnotation,
+		HasResourceTwin:    awsDataSource.HasResourceTwin,
+		BuildConstrained:   len(awsDataSource.BuildTags) > 0,
+		BuildTags:          awsDataSource.BuildTags,
+		SupportsFilters:    supportsFilters(sdkAttributes(serviceReg, awsDataSource.declaredFactory())),
+		TestingConfig:      awsDataSource.TestingConfig,
 	}
 }
 
@@ -81,14 +128,39 @@ func NewTerraformDataSourceFromAWSFramework(awsDataSource AWSResource, serviceRe
 	structType := awsDataSource.StructType
 
 	return TerraformDataSource{
+		ID:                 entryID(serviceReg.PackagePath, awsDataSource.TerraformType, WalkCategoryDataSource),
+		Category:           WalkCategoryDataSource,
 		TerraformType:      awsDataSource.TerraformType,
+		DisplayName:        displayName(awsDataSource),
 		StructType:         structType, // Framework data sources use struct types
 		Namespace:          serviceReg.PackagePath,
+		PackageName:        serviceReg.PackageName,
 		RegistrationMethod: "FrameworkDataSources",
 		SDKType:            "aws_framework",
 		// Framework data sources use method-based indexes on struct types
 		SchemaIndex:    fmt.Sprintf("method.%s.Schema.goindex", structType),
 		ReadIndex:      fmt.Sprintf("method.%s.Read.goindex", structType),
 		AttributeIndex: fmt.Sprintf("method.%s.Schema.goindex", structType),
+
+		RegistrationFile: awsDataSource.RegistrationFile,
+		RawAnnotation:    awsDataSource.RawAnnotation,
+		HasResourceTwin:  awsDataSource.HasResourceTwin,
+
+		BuildConstrained: len(awsDataSource.BuildTags) > 0,
+		BuildTags:        awsDataSource.BuildTags,
+
+		SupportsFilters: supportsFilters(extractFrameworkSchemaAttributes(serviceReg.Package, structType)),
+		TestingConfig:   awsDataSource.TestingConfig,
 	}
 }
+
+// sdkAttributes returns the top-level schema attribute names of an SDK factory function of the service
+func sdkAttributes(serviceReg ServiceRegistration, factoryFunction string) []string {
+	attributes, _ := extractSDKSchemaAttributes(serviceReg.Package, factoryFunction)
+	return attributes
+}
+
+// supportsFilters reports whether a data source's top-level attributes include the "filter" block
+func supportsFilters(attributes []string) bool {
+	return slices.Contains(attributes, "filter")
+}
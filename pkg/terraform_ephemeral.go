@@ -1,26 +1,51 @@
 package pkg
 
-import "fmt"
+import (
+	"fmt"
+	"go/ast"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
 
 // TerraformEphemeral represents information about a Terraform ephemeral resource
 type TerraformEphemeral struct {
-	TerraformType      string `json:"terraform_type"` // "aws_secretsmanager_secret_version"
+	ID                 string `json:"id"`                     // Stable hash of namespace, terraform type and category
+	Category           string `json:"category"`               // Walk category matching the output directory, e.g. "ephemeral"
+	TerraformType      string `json:"terraform_type"`         // "aws_secretsmanager_secret_version"
+	DisplayName        string `json:"display_name,omitempty"` // Registration name, or one derived from the terraform type, e.g. "S3 Bucket"
 	StructType         string `json:"struct_type"`
 	Namespace          string `json:"namespace"`
+	PackageName        string `json:"package_name,omitempty"` // Go package name, e.g. "s3"
 	RegistrationMethod string `json:"registration_method"`
 	SDKType            string `json:"sdk_type"`
 	SchemaIndex        string `json:"schema_index,omitempty"`
 	OpenIndex          string `json:"open_index,omitempty"`
 	RenewIndex         string `json:"renew_index,omitempty"`
 	CloseIndex         string `json:"close_index,omitempty"`
+	SupportsRenew      bool   `json:"supports_renew"`              // Whether the struct implements Renew
+	RegistrationFile   string `json:"registration_file,omitempty"` // Source file declaring the entry
+	RawAnnotation      string `json:"raw_annotation,omitempty"`    // Registration annotation as written in the source
+
+	// Region behaviour declared by @Region, nil when the annotation is absent
+	Region *AWSRegionConfig `json:"region,omitempty"`
+
+	// Tags of the //go:build constraint of the registration file, the entry only exists in builds satisfying it
+	BuildConstrained bool     `json:"build_constrained,omitempty"`
+	BuildTags        []string `json:"build_tags,omitempty"`
+
+	// Acceptance test generation hints declared by @Testing, keyed by annotation argument, e.g. "importIgnore"
+	TestingConfig map[string]string `json:"testing_config,omitempty"`
 }
 
 // NewTerraformEphemeralInfo creates a TerraformEphemeral struct (legacy approach)
 func NewTerraformEphemeralInfo(structType string, service ServiceRegistration) TerraformEphemeral {
 	return TerraformEphemeral{
+		ID:                 entryID(service.PackagePath, service.EphemeralTerraformTypes[structType], WalkCategoryEphemeral),
+		Category:           WalkCategoryEphemeral,
 		TerraformType:      service.EphemeralTerraformTypes[structType],
 		StructType:         structType,
 		Namespace:          service.PackagePath,
+		PackageName:        service.PackageName,
 		RegistrationMethod: "EphemeralResources",
 		SDKType:            "ephemeral",
 		// Optional fields can be added later when we have more sophisticated AST parsing
@@ -34,20 +59,52 @@ func NewTerraformEphemeralInfo(structType string, service ServiceRegistration) T
 // NewTerraformEphemeralFromAWS creates a TerraformEphemeral struct from AWS ephemeral resource information
 func NewTerraformEphemeralFromAWS(awsEphemeral AWSResource, service ServiceRegistration) TerraformEphemeral {
 	ephemeral := TerraformEphemeral{
+		ID:                 entryID(service.PackagePath, awsEphemeral.TerraformType, WalkCategoryEphemeral),
+		Category:           WalkCategoryEphemeral,
 		TerraformType:      awsEphemeral.TerraformType,
+		DisplayName:        displayName(awsEphemeral),
 		StructType:         awsEphemeral.StructType,
 		Namespace:          service.PackagePath,
+		PackageName:        service.PackageName,
 		RegistrationMethod: awsEphemeral.FactoryFunction,
 		SDKType:            awsEphemeral.SDKType,
+		RegistrationFile:   awsEphemeral.RegistrationFile,
+		RawAnnotation:      awsEphemeral.RawAnnotation,
+		Region:             awsEphemeral.Region,
+		BuildConstrained:   len(awsEphemeral.BuildTags) > 0,
+		BuildTags:          awsEphemeral.BuildTags,
+		TestingConfig:      awsEphemeral.TestingConfig,
 	}
 
 	// Set lifecycle method indexes if we have struct type (for method resolution)
 	if awsEphemeral.StructType != "" {
 		ephemeral.SchemaIndex = fmt.Sprintf("method.%s.Schema.goindex", awsEphemeral.StructType)
 		ephemeral.OpenIndex = fmt.Sprintf("method.%s.Open.goindex", awsEphemeral.StructType)
-		ephemeral.RenewIndex = fmt.Sprintf("method.%s.Renew.goindex", awsEphemeral.StructType)
 		ephemeral.CloseIndex = fmt.Sprintf("method.%s.Close.goindex", awsEphemeral.StructType)
+
+		// Renew is optional, only point at it when the package declares it. Without the package AST,
+		// e.g. for a loaded index, Renew is assumed as before
+		if service.Package == nil || packageDeclaresMethod(service.Package, awsEphemeral.StructType, "Renew") {
+			ephemeral.RenewIndex = fmt.Sprintf("method.%s.Renew.goindex", awsEphemeral.StructType)
+			ephemeral.SupportsRenew = true
+		}
 	}
 
 	return ephemeral
 }
+
+// packageDeclaresMethod reports whether any file of the package declares methodName on typeName
+func packageDeclaresMethod(packageInfo *gophon.PackageInfo, typeName, methodName string) bool {
+	for _, fileInfo := range packageInfo.Files {
+		if fileInfo == nil || fileInfo.File == nil {
+			continue
+		}
+		for _, decl := range fileInfo.File.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if ok && funcDecl.Recv != nil && funcDecl.Name.Name == methodName && receiverTypeName(funcDecl) == typeName {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -1,15 +1,23 @@
 package pkg
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"go/ast"
+	"go/parser"
 	"go/token"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"text/template"
 
 	gophon "github.com/lonegunmanb/gophon/pkg"
 	"github.com/spf13/afero"
@@ -17,37 +25,102 @@ import (
 
 var outputFs = afero.NewOsFs()
 var inputFs = afero.NewOsFs() // Add filesystem abstraction for input operations
+var scanSinglePackage = gophon.ScanSinglePackage
 
 // TerraformProviderIndex represents the complete index of a Terraform provider
 type TerraformProviderIndex struct {
-	Version    string                `json:"version"`    // Provider version
-	Services   []ServiceRegistration `json:"services"`   // All service registrations
-	Statistics ProviderStatistics    `json:"statistics"` // Summary statistics
+	Version    string                `json:"version"`               // Provider version
+	Services   []ServiceRegistration `json:"services"`              // All service registrations
+	Statistics ProviderStatistics    `json:"statistics"`            // Summary statistics
+	ScanErrors []ServiceScanError    `json:"scan_errors,omitempty"` // Services that failed to scan
+
+	// Annotated entries that were detected but could not be fully resolved
+	PartialEntries []PartialEntry `json:"partial_entries,omitempty"`
+
+	// Deprecated terraform types pointing to their canonical type, not counted in Statistics
+	Aliases []TypeAlias `json:"aliases,omitempty"`
+
+	// Module path and, when known, version of the go.mod nearest the scanned directory, for provenance
+	ModulePath    string `json:"module_path,omitempty"`
+	ModuleVersion string `json:"module_version,omitempty"`
+
+	// ScanOptions.Categories the index was scanned with, nil for all. Writers skip the other categories
+	categories []string
+
+	// Options applied by WriteJSONFile, set through WriteIndexFilesWithOptions
+	outputOptions    OutputOptions
+	fileNameTemplate *template.Template // Parsed outputOptions.FileNameTemplate, nil for the default names
 }
 
 // ScanTerraformProviderServices scans the specified directory for Terraform provider services
 // and extracts all registration information into a structured index
 func ScanTerraformProviderServices(dir, basePkgUrl string, version string, progressCallback ProgressCallback) (*TerraformProviderIndex, error) {
-	// Read the services directory to get all service subdirectories
-	entries, err := afero.ReadDir(inputFs, dir)
+	return ScanTerraformProviderServicesContext(context.Background(), dir, basePkgUrl, version, progressCallback)
+}
+
+// ScanTerraformProviderServicesContext is like ScanTerraformProviderServices but stops dispatching
+// services once ctx is done and returns ctx.Err(). Services already being scanned finish first
+func ScanTerraformProviderServicesContext(ctx context.Context, dir, basePkgUrl string, version string, progressCallback ProgressCallback) (*TerraformProviderIndex, error) {
+	return ScanTerraformProviderServicesWithOptions(ctx, dir, basePkgUrl, version, progressCallback, ScanOptions{})
+}
+
+// ScanTerraformProviderServicesWithOptions is like ScanTerraformProviderServicesContext but applies the
+// service and terraform type filters in options. Excluded services are never parsed
+func ScanTerraformProviderServicesWithOptions(ctx context.Context, dir, basePkgUrl string, version string, progressCallback ProgressCallback, options ScanOptions) (*TerraformProviderIndex, error) {
+	return ScanWithConfig(ScanConfig{
+		Dir:              dir,
+		BasePkgUrl:       basePkgUrl,
+		Version:          version,
+		Context:          ctx,
+		ProgressCallback: progressCallback,
+		ScanOptions:      options,
+	})
+}
+
+// ScanWithConfig scans the services directory cfg.Dir and extracts all registration information into a
+// structured index. The positional Scan* functions are wrappers around it
+func ScanWithConfig(cfg ScanConfig) (*TerraformProviderIndex, error) {
+	startTime := timeNow()
+
+	ctx, dir, basePkgUrl, version := cfg.context(), cfg.Dir, cfg.BasePkgUrl, cfg.Version
+	progressCallback, options := cfg.ProgressCallback, cfg.ScanOptions
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	// Read the services directory to get all service subdirectories selected by the service filters
+	logger := options.logger()
+	dirEntries, err := serviceDirectories(options.inputFs(), dir, options, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read services directory: %w", err)
+		return nil, err
 	}
 
-	// Filter entries to only include directories
-	var dirEntries []os.FileInfo
-	for _, entry := range entries {
-		if entry.IsDir() {
-			dirEntries = append(dirEntries, entry)
-		}
+	// A missing or unreadable go.mod only costs provenance, the scan goes on
+	modulePath, moduleVersion, err := detectProviderModule(options.inputFs(), dir)
+	if err != nil {
+		logger.Warnf("skipping module detection: %v", err)
 	}
 
 	totalServices := len(dirEntries)
+	logger.Infof("scanning %d services in %s", totalServices, dir)
 	if totalServices == 0 {
+		if options.FailOnEmpty {
+			return nil, fmt.Errorf("no services with AWS registrations found in %s", dir)
+		}
 		return &TerraformProviderIndex{
 			Version:    version,
 			Services:   []ServiceRegistration{},
-			Statistics: ProviderStatistics{},
+			Statistics: ProviderStatistics{ScanDurationMs: timeNow().Sub(startTime).Milliseconds()},
+
+			ModulePath:    modulePath,
+			ModuleVersion: moduleVersion,
+
+			categories: options.Categories,
 		}, nil
 	}
 
@@ -63,11 +136,19 @@ func ScanTerraformProviderServices(dir, basePkgUrl string, version string, progr
 	// Channels for work distribution and result collection
 	entryChan := make(chan os.FileInfo, len(dirEntries))
 	resultChan := make(chan ServiceRegistration, len(dirEntries))
+	errorChan := make(chan ServiceScanError, len(dirEntries))
 	var wg sync.WaitGroup
 
-	// Send all directory entries to the work channel
+	// Send all directory entries to the work channel, stopping early on cancellation
+	// The channel is buffered for every entry so dispatching never blocks
+dispatch:
 	for _, entry := range dirEntries {
-		entryChan <- entry
+		select {
+		case <-ctx.Done():
+			break dispatch
+		default:
+			entryChan <- entry
+		}
 	}
 	close(entryChan)
 
@@ -77,36 +158,40 @@ func ScanTerraformProviderServices(dir, basePkgUrl string, version string, progr
 		go func() {
 			defer wg.Done()
 			for entry := range entryChan {
-				servicePath := filepath.Join(dir, entry.Name())
+				// Stop picking up new services once cancelled, result channels are
+				// buffered so returning here never leaves a sender blocked
+				if ctx.Err() != nil {
+					return
+				}
 
 				// Scan the individual service package
-				packageInfo, err := gophon.ScanSinglePackage(servicePath, basePkgUrl)
+				serviceReg, err := scanServicePackage(dir, entry, basePkgUrl, options)
 
 				// Update progress
 				progressTracker.UpdateProgress(entry.Name())
 
-				if err != nil || packageInfo == nil || len(packageInfo.Files) == 0 {
-					// Skip services that can't be scanned (might not be valid Go packages)
+				if err != nil {
+					// Record error but continue with other services
+					logger.Errorf("failed to scan service %s: %v", entry.Name(), err)
+					errorChan <- ServiceScanError{
+						ServiceName: entry.Name(),
+						Error:       err.Error(),
+					}
 					continue
 				}
 
-				serviceReg := newServiceRegistration(packageInfo, entry)
-
-				// Phase 3: Use annotation-based scanning instead of file-by-file parsing
-				err = parseAWSServiceFileWithAnnotations(packageInfo, &serviceReg)
-				if err != nil {
-					// Log error but continue with other services
+				if serviceReg == nil {
+					// Skip directories that don't contain a Go package
+					logger.Debugf("skipping %s: no Go package found", entry.Name())
 					continue
 				}
 
 				// NOTE: extractAndStoreSDKCRUDMethodsForLegacyPlugin is no longer needed
 				// because CRUD methods are now extracted directly by the annotation scanner
 
-				// Only include services that have at least one AWS registration method
-				if len(serviceReg.AWSSDKResources) > 0 || len(serviceReg.AWSSDKDataSources) > 0 ||
-					len(serviceReg.AWSFrameworkResources) > 0 || len(serviceReg.AWSFrameworkDataSources) > 0 ||
-					len(serviceReg.AWSEphemeralResources) > 0 {
-					resultChan <- serviceReg
+				// Only forward services that have at least one AWS registration method or partial entry
+				if serviceReg.hasRegistrations() || len(serviceReg.PartialEntries) > 0 {
+					resultChan <- *serviceReg
 				}
 			}
 		}()
@@ -116,53 +201,282 @@ func ScanTerraformProviderServices(dir, basePkgUrl string, version string, progr
 	go func() {
 		wg.Wait()
 		close(resultChan)
+		close(errorChan)
 	}()
 
 	// Collect results and build final data structures
 	var services []ServiceRegistration
-	stats := ProviderStatistics{}
+	var partialEntries []PartialEntry
 
 	for serviceReg := range resultChan {
+		partialEntries = append(partialEntries, serviceReg.PartialEntries...)
+
+		// Services with only partial entries are reported but not indexed
+		if !serviceReg.hasRegistrations() {
+			continue
+		}
+
 		services = append(services, serviceReg)
-		stats.ServiceCount++
+	}
+	// Workers finish in arbitrary order, sort so the index is deterministic across runs
+	sortServicesByName(services)
+	linkResourceTwins(services)
+	stats := newProviderStatistics(services)
 
-		// AWS 5-category statistics
-		stats.TotalResources += len(serviceReg.AWSSDKResources)
-		stats.TotalResources += len(serviceReg.AWSFrameworkResources)
-		stats.TotalDataSources += len(serviceReg.AWSSDKDataSources)
-		stats.TotalDataSources += len(serviceReg.AWSFrameworkDataSources)
-		stats.EphemeralResources += len(serviceReg.AWSEphemeralResources)
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	// Final statistics calculation
-	stats.LegacyResources = 0 // No longer used
-	stats.ModernResources = 0 // No longer used
+	// Collect scan errors, the error channel is buffered so workers never block on it
+	var scanErrors []ServiceScanError
+	for scanErr := range errorChan {
+		scanErrors = append(scanErrors, scanErr)
+	}
+	sort.Slice(scanErrors, func(i, j int) bool {
+		return scanErrors[i].ServiceName < scanErrors[j].ServiceName
+	})
+	sort.Slice(partialEntries, func(i, j int) bool {
+		if partialEntries[i].ServiceName != partialEntries[j].ServiceName {
+			return partialEntries[i].ServiceName < partialEntries[j].ServiceName
+		}
+		return partialEntries[i].TerraformType < partialEntries[j].TerraformType
+	})
 
 	// Report scanning completion
 	progressTracker.Complete()
+	stats.ScanDurationMs = timeNow().Sub(startTime).Milliseconds()
 
-	return &TerraformProviderIndex{
+	if len(services) == 0 && options.FailOnEmpty {
+		return nil, fmt.Errorf("no services with AWS registrations found in %s", dir)
+	}
+
+	index := &TerraformProviderIndex{
 		Version:    version,
 		Services:   services,
 		Statistics: stats,
-	}, nil
+		ScanErrors: scanErrors,
+
+		PartialEntries: partialEntries,
+		ModulePath:     modulePath,
+		ModuleVersion:  moduleVersion,
+
+		categories: options.Categories,
+	}
+	index.Aliases = collectTypeAliases(index)
+
+	return index, nil
+}
+
+// serviceDirectories returns the subdirectories of the services directory dir selected by the service filters,
+// in directory order. Symlinks to directories are followed and scanned under the link's name, as monorepo setups
+// link service packages in from elsewhere. A link leading back to dir or one of its ancestors would scan the tree
+// recursively, and a link to a directory already scanned would duplicate a service, both are skipped with a warning.
+// Loops are only detected on filesystems whose file info identifies files, e.g. the OS filesystem
+func serviceDirectories(fs afero.Fs, dir string, options ScanOptions, logger Logger) ([]os.FileInfo, error) {
+	entries, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read services directory: %w", err)
+	}
+
+	// Real directories are seen first so a link never shadows the directory it points to
+	var scanned []os.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() {
+			scanned = append(scanned, entry)
+		}
+	}
+	ancestors := directoryAncestors(fs, dir)
+
+	var dirEntries []os.FileInfo
+	for _, entry := range entries {
+		if !options.includesService(entry.Name()) {
+			continue
+		}
+		if entry.IsDir() {
+			dirEntries = append(dirEntries, entry)
+			continue
+		}
+		if entry.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+
+		// Stat follows the link, dangling links and links to files are no services
+		linkPath := filepath.Join(dir, entry.Name())
+		target, err := fs.Stat(linkPath)
+		if err != nil || !target.IsDir() {
+			continue
+		}
+		if containsSameFile(ancestors, target) {
+			logger.Warnf("skipping service symlink %s: it leads back to the services directory", linkPath)
+			continue
+		}
+		if containsSameFile(scanned, target) {
+			logger.Warnf("skipping service symlink %s: its directory is already scanned", linkPath)
+			continue
+		}
+		scanned = append(scanned, target)
+		dirEntries = append(dirEntries, symlinkedDirInfo{FileInfo: target, name: entry.Name()})
+	}
+
+	return dirEntries, nil
+}
+
+// directoryAncestors returns the file info of dir and every directory above it that can be stat'ed
+func directoryAncestors(fs afero.Fs, dir string) []os.FileInfo {
+	if absDir, err := filepath.Abs(dir); err == nil {
+		dir = absDir
+	}
+
+	var ancestors []os.FileInfo
+	for current := filepath.Clean(dir); ; current = filepath.Dir(current) {
+		if info, err := fs.Stat(current); err == nil {
+			ancestors = append(ancestors, info)
+		}
+		if filepath.Dir(current) == current {
+			return ancestors
+		}
+	}
+}
+
+// containsSameFile reports whether infos contains a file info describing the same file as info
+func containsSameFile(infos []os.FileInfo, info os.FileInfo) bool {
+	for _, candidate := range infos {
+		if os.SameFile(candidate, info) {
+			return true
+		}
+	}
+	return false
+}
+
+// symlinkedDirInfo is the file info of a symlinked service directory, named after the link instead of its target
+type symlinkedDirInfo struct {
+	os.FileInfo
+	name string
+}
+
+func (info symlinkedDirInfo) Name() string {
+	return info.name
+}
+
+// ScanSingleService scans exactly one service package directory and returns its populated ServiceRegistration
+// Registration files are reported relative to the parent directory of servicePath, matching a full provider scan
+func ScanSingleService(servicePath, basePkgUrl string) (*ServiceRegistration, error) {
+	entry, err := inputFs.Stat(servicePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service directory: %w", err)
+	}
+	if !entry.IsDir() {
+		return nil, fmt.Errorf("service path %s is not a directory", servicePath)
+	}
+
+	serviceReg, err := scanServicePackage(filepath.Dir(servicePath), entry, basePkgUrl, ScanOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if serviceReg == nil {
+		return nil, fmt.Errorf("no Go package found in %s", servicePath)
+	}
+
+	return serviceReg, nil
+}
+
+// ParseServiceSource parses a single Go source file of the service package at pkgPath, e.g.
+// "github.com/hashicorp/terraform-provider-aws/internal/service/s3", and converts its annotations
+// into a ServiceRegistration named after the last element of pkgPath. Parse errors are returned as is
+func ParseServiceSource(src []byte, pkgPath string) (*ServiceRegistration, error) {
+	fileName := path.Base(pkgPath) + ".go"
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, fileName, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	fileInfo := &gophon.FileInfo{
+		File:     file,
+		FileName: fileName,
+		FilePath: fileName,
+		Package:  pkgPath,
+	}
+	packageInfo := &gophon.PackageInfo{Files: []*gophon.FileInfo{fileInfo}}
+	packageInfo.Functions, packageInfo.Types = fileDeclarations(fset, fileInfo)
+
+	serviceReg := newServiceRegistration(packageInfo, path.Base(pkgPath))
+	if err := parseAWSServiceFileWithAnnotations(packageInfo, &serviceReg); err != nil {
+		return nil, err
+	}
+
+	return &serviceReg, nil
+}
+
+// fileDeclarations lists the functions and types declared in fileInfo with their line ranges, like gophon records
+// them when scanning a package from disk. Method receiver types are not filled in
+func fileDeclarations(fset *token.FileSet, fileInfo *gophon.FileInfo) ([]*gophon.FunctionInfo, []*gophon.TypeInfo) {
+	newRange := func(node ast.Node) *gophon.Range {
+		return &gophon.Range{FileInfo: fileInfo, StartLine: fset.Position(node.Pos()).Line, EndLine: fset.Position(node.End()).Line}
+	}
+
+	var functions []*gophon.FunctionInfo
+	var types []*gophon.TypeInfo
+	for _, decl := range fileInfo.File.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			functions = append(functions, &gophon.FunctionInfo{Range: newRange(d), FuncDecl: d, Name: d.Name.Name})
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					types = append(types, &gophon.TypeInfo{Range: newRange(typeSpec), GenDecl: d, Name: typeSpec.Name.Name})
+				}
+			}
+		}
+	}
+	return functions, types
+}
+
+// scanServicePackage scans the service directory entry under baseDir and converts its annotations
+// into a ServiceRegistration. It returns nil without error when the directory holds no Go package
+func scanServicePackage(baseDir string, entry os.FileInfo, basePkgUrl string, options ScanOptions) (*ServiceRegistration, error) {
+	servicePath := filepath.Join(baseDir, entry.Name())
+
+	packageInfo, err := options.scanPackage(servicePath, basePkgUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan package: %w", err)
+	}
+
+	if packageInfo == nil || len(packageInfo.Files) == 0 {
+		return nil, nil
+	}
+
+	serviceReg := newServiceRegistration(packageInfo, entry.Name())
+
+	// Phase 3: Use annotation-based scanning instead of file-by-file parsing
+	if err := parseAWSServiceFileWithOptions(packageInfo, &serviceReg, options); err != nil {
+		return nil, err
+	}
+
+	// Report source files relative to the scanned base directory
+	makeRegistrationFilesRelative(&serviceReg, baseDir)
+
+	return &serviceReg, nil
 }
 
 // WriteIndexFiles writes all index files to the specified output directory
 // This is the main method that orchestrates writing all index files
 func (index *TerraformProviderIndex) WriteIndexFiles(outputDir string, progressCallback ProgressCallback) error {
-	// Calculate total number of files to write
-	totalFiles := 1 // main index file
-	for _, service := range index.Services {
-		// AWS 5-category file counts
-		totalFiles += len(service.AWSSDKResources)         // AWS SDK resources
-		totalFiles += len(service.AWSFrameworkResources)   // AWS Framework resources
-		totalFiles += len(service.AWSSDKDataSources)       // AWS SDK data sources
-		totalFiles += len(service.AWSFrameworkDataSources) // AWS Framework data sources
-		totalFiles += len(service.AWSEphemeralResources)   // AWS Ephemeral resources
-		totalFiles += len(service.EphemeralTerraformTypes) // Framework ephemeral resources (backward compatibility)
+	outputDir, err := cleanOutputDir(outputDir)
+	if err != nil {
+		return err
 	}
 
+	// Calculate total number of files to write, shared with PlanIndexFiles so the two cannot drift
+	writes, err := index.plannedWrites()
+	if err != nil {
+		return err
+	}
+	totalFiles := len(writes)
+
 	// Create progress tracker
 	progressTracker := NewProgressTracker("indexing", totalFiles, progressCallback)
 
@@ -177,20 +491,58 @@ func (index *TerraformProviderIndex) WriteIndexFiles(outputDir string, progressC
 	}
 	progressTracker.UpdateProgress("main index file")
 
+	// Write factory function reverse index file
+	if err := index.WriteFactoryIndexFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write factory index file: %w", err)
+	}
+	progressTracker.UpdateProgress("factory index file")
+
+	// Write standalone statistics file
+	if err := index.WriteStatisticsFile(outputDir); err != nil {
+		return fmt.Errorf("failed to write statistics file: %w", err)
+	}
+	progressTracker.UpdateProgress("statistics file")
+
 	// Write individual resource files
-	if err := index.WriteResourceFiles(outputDir, progressTracker); err != nil {
-		return fmt.Errorf("failed to write resource files: %w", err)
+	if index.writesCategory(WalkCategoryResource) {
+		if err := index.WriteResourceFiles(outputDir, progressTracker); err != nil {
+			return fmt.Errorf("failed to write resource files: %w", err)
+		}
 	}
 
 	// Write individual data source files
-	if err := index.WriteDataSourceFiles(outputDir, progressTracker); err != nil {
-		return fmt.Errorf("failed to write data source files: %w", err)
+	if index.writesCategory(WalkCategoryDataSource) {
+		if err := index.WriteDataSourceFiles(outputDir, progressTracker); err != nil {
+			return fmt.Errorf("failed to write data source files: %w", err)
+		}
 	}
 
 	// Write individual ephemeral resource files
-	if err := index.WriteEphemeralFiles(outputDir, progressTracker); err != nil {
-		return fmt.Errorf("failed to write ephemeral files: %w", err)
+	if index.writesCategory(WalkCategoryEphemeral) {
+		if err := index.WriteEphemeralFiles(outputDir, progressTracker); err != nil {
+			return fmt.Errorf("failed to write ephemeral files: %w", err)
+		}
+	}
+
+	// Write per-service files when requested
+	if index.outputOptions.ServiceFiles {
+		if err := index.WriteServiceFiles(outputDir, progressTracker); err != nil {
+			return fmt.Errorf("failed to write service files: %w", err)
+		}
+	}
+
+	// Write per-resource attribute schema files when requested
+	if index.outputOptions.AttributeSchemas && index.writesCategory(WalkCategoryResource) {
+		if err := index.writeAttributeSchemas(outputDir, progressTracker); err != nil {
+			return fmt.Errorf("failed to write attribute schema files: %w", err)
+		}
+	}
+
+	// Write manifest last so it covers every file written above
+	if err := index.WriteManifest(outputDir); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
 	}
+	progressTracker.UpdateProgress("manifest")
 
 	// Report completion
 	progressTracker.Complete()
@@ -198,12 +550,34 @@ func (index *TerraformProviderIndex) WriteIndexFiles(outputDir string, progressC
 	return nil
 }
 
-// WriteMainIndexFile writes the main terraform-provider-aws-index.json file
+// WriteMainIndexFile writes the main index file, terraform-provider-aws-index.json unless
+// OutputOptions.MainIndexFileName names another
 func (index *TerraformProviderIndex) WriteMainIndexFile(outputDir string) error {
-	mainIndexPath := filepath.Join(outputDir, "terraform-provider-aws-index.json")
+	mainIndexPath := filepath.Join(outputDir, index.outputOptions.mainIndexFileName())
+
+	// MarshalJSON sorts services by name without reordering the caller's index, e.g. after Merge
 	return index.WriteJSONFile(mainIndexPath, index)
 }
 
+// MarshalJSON encodes the index with services sorted by name, without reordering the index itself, so an index
+// encodes to the same bytes whatever order its services were added in. Resource maps need no normalization
+// since encoding/json always writes map keys in sorted order
+func (index TerraformProviderIndex) MarshalJSON() ([]byte, error) {
+	type plainIndex TerraformProviderIndex // Drops this method so encoding does not recurse
+	sorted := plainIndex(index)
+	sorted.Services = append([]ServiceRegistration(nil), index.Services...)
+	sortServicesByName(sorted.Services)
+
+	return json.Marshal(sorted)
+}
+
+// sortServicesByName sorts services in place by ServiceName
+func sortServicesByName(services []ServiceRegistration) {
+	sort.SliceStable(services, func(i, j int) bool {
+		return services[i].ServiceName < services[j].ServiceName
+	})
+}
+
 // processCallbacksParallel runs a slice of callbacks in parallel
 func processCallbacksParallel(tasks []func() error) error {
 	if len(tasks) == 0 {
@@ -255,65 +629,94 @@ func processCallbacksParallel(tasks []func() error) error {
 	return nil
 }
 
-// WriteResourceFiles writes individual JSON files for each resource
+// WriteResourceFiles writes individual JSON files for each resource. Services are processed in batches of
+// OutputOptions.ServiceBatchSize, the files of a batch are written in parallel before the next batch starts,
+// so only one batch of write tasks is held at a time
 func (index *TerraformProviderIndex) WriteResourceFiles(outputDir string, progressTracker *ProgressTracker) error {
-	resourcesDir := filepath.Join(outputDir, "resources")
-	var tasks []func() error
+	resourcesDir := filepath.Join(outputDir, index.outputOptions.categoryDir(WalkCategoryResource))
+	batchSize := index.outputOptions.serviceBatchSize(len(index.Services))
 
-	for _, service := range index.Services {
-		// Process AWS SDK resources
-		for terraformType, awsResourceInfo := range service.AWSSDKResources {
-			// Capture variables for closure
-			tfType := terraformType
-			awsResource := awsResourceInfo
-			svc := service
+	for start := 0; start < len(index.Services); start += batchSize {
+		end := min(start+batchSize, len(index.Services))
 
-			tasks = append(tasks, func() error {
-				// Create AWS-specific resource info using only core TerraformResource fields
-				awsResourceData := NewTerraformResourceFromAWSSDK(awsResource, svc)
+		var tasks []func() error
+		for _, service := range index.Services[start:end] {
+			tasks = append(tasks, index.resourceFileTasks(service, resourcesDir, progressTracker)...)
+		}
+		if err := processCallbacksParallel(tasks); err != nil {
+			return err
+		}
+	}
 
-				fileName := fmt.Sprintf("%s.json", tfType)
-				filePath := filepath.Join(resourcesDir, fileName)
+	return nil
+}
 
-				if err := index.WriteJSONFile(filePath, awsResourceData); err != nil {
-					return fmt.Errorf("failed to write AWS SDK resource file %s: %w", fileName, err)
-				}
+// resourceFileTasks returns the tasks writing the SDK and framework resource files of a single service
+func (index *TerraformProviderIndex) resourceFileTasks(service ServiceRegistration, resourcesDir string, progressTracker *ProgressTracker) []func() error {
+	var tasks []func() error
 
-				progressTracker.UpdateProgress(fmt.Sprintf("resource %s", tfType))
-				return nil
-			})
-		}
+	// Process AWS SDK resources
+	for terraformType, awsResourceInfo := range service.AWSSDKResources {
+		// Capture variables for closure
+		tfType := terraformType
+		awsResource := awsResourceInfo
+		svc := service
+
+		tasks = append(tasks, func() error {
+			// Create AWS-specific resource info using only core TerraformResource fields
+			awsResourceData := NewTerraformResourceFromAWSSDK(awsResource, svc)
+			awsResourceData.SDKType = index.sdkTypeLabel(awsResourceData.SDKType)
+			awsResourceData.RelatedFunctions = index.relatedFunctions(&svc, awsResource.declaredFactory())
+
+			fileName, err := index.entryFileName(WalkCategoryResource, svc.ServiceName, tfType)
+			if err != nil {
+				return err
+			}
+			filePath := filepath.Join(resourcesDir, filepath.FromSlash(fileName))
 
-		// Process AWS Framework resources (NEW)
-		for terraformType, awsResourceInfo := range service.AWSFrameworkResources {
-			// Capture variables for closure
-			tfType := terraformType
-			awsResource := awsResourceInfo
-			svc := service
+			if err := index.WriteJSONFile(filePath, awsResourceData); err != nil {
+				return fmt.Errorf("failed to write AWS SDK resource file %s: %w", fileName, err)
+			}
 
-			tasks = append(tasks, func() error {
-				// Create AWS Framework-specific resource info using only core TerraformResource fields
-				awsResourceData := NewTerraformResourceFromAWSFramework(awsResource, svc)
+			progressTracker.UpdateProgress(fmt.Sprintf("resource %s", tfType))
+			return nil
+		})
+	}
 
-				fileName := fmt.Sprintf("%s.json", tfType)
-				filePath := filepath.Join(resourcesDir, fileName)
+	// Process AWS Framework resources (NEW)
+	for terraformType, awsResourceInfo := range service.AWSFrameworkResources {
+		// Capture variables for closure
+		tfType := terraformType
+		awsResource := awsResourceInfo
+		svc := service
+
+		tasks = append(tasks, func() error {
+			// Create AWS Framework-specific resource info using only core TerraformResource fields
+			awsResourceData := NewTerraformResourceFromAWSFramework(awsResource, svc, svc.Package)
+			awsResourceData.SDKType = index.sdkTypeLabel(awsResourceData.SDKType)
+			awsResourceData.RelatedFunctions = index.relatedFunctions(&svc, awsResource.declaredFactory())
+
+			fileName, err := index.entryFileName(WalkCategoryResource, svc.ServiceName, tfType)
+			if err != nil {
+				return err
+			}
+			filePath := filepath.Join(resourcesDir, filepath.FromSlash(fileName))
 
-				if err := index.WriteJSONFile(filePath, awsResourceData); err != nil {
-					return fmt.Errorf("failed to write AWS Framework resource file %s: %w", fileName, err)
-				}
+			if err := index.WriteJSONFile(filePath, awsResourceData); err != nil {
+				return fmt.Errorf("failed to write AWS Framework resource file %s: %w", fileName, err)
+			}
 
-				progressTracker.UpdateProgress(fmt.Sprintf("resource %s", tfType))
-				return nil
-			})
-		}
+			progressTracker.UpdateProgress(fmt.Sprintf("resource %s", tfType))
+			return nil
+		})
 	}
 
-	return processCallbacksParallel(tasks)
+	return tasks
 }
 
 // WriteDataSourceFiles writes individual JSON files for each data source
 func (index *TerraformProviderIndex) WriteDataSourceFiles(outputDir string, progressTracker *ProgressTracker) error {
-	dataSourcesDir := filepath.Join(outputDir, "datasources")
+	dataSourcesDir := filepath.Join(outputDir, index.outputOptions.categoryDir(WalkCategoryDataSource))
 	var tasks []func() error
 
 	for _, service := range index.Services {
@@ -327,9 +730,14 @@ func (index *TerraformProviderIndex) WriteDataSourceFiles(outputDir string, prog
 			tasks = append(tasks, func() error {
 				// Create AWS-specific data source info using only core TerraformDataSource fields
 				awsDataSourceData := NewTerraformDataSourceFromAWSSDK(awsDataSource, svc)
+				awsDataSourceData.SDKType = index.sdkTypeLabel(awsDataSourceData.SDKType)
+				awsDataSourceData.RelatedFunctions = index.relatedFunctions(&svc, awsDataSource.declaredFactory())
 
-				fileName := fmt.Sprintf("%s.json", tfType)
-				filePath := filepath.Join(dataSourcesDir, fileName)
+				fileName, err := index.entryFileName(WalkCategoryDataSource, svc.ServiceName, tfType)
+				if err != nil {
+					return err
+				}
+				filePath := filepath.Join(dataSourcesDir, filepath.FromSlash(fileName))
 
 				if err := index.WriteJSONFile(filePath, awsDataSourceData); err != nil {
 					return fmt.Errorf("failed to write AWS SDK data source file %s: %w", fileName, err)
@@ -350,9 +758,14 @@ func (index *TerraformProviderIndex) WriteDataSourceFiles(outputDir string, prog
 			tasks = append(tasks, func() error {
 				// Create AWS Framework-specific data source info using only core TerraformDataSource fields
 				awsDataSourceData := NewTerraformDataSourceFromAWSFramework(awsDataSource, svc)
+				awsDataSourceData.SDKType = index.sdkTypeLabel(awsDataSourceData.SDKType)
+				awsDataSourceData.RelatedFunctions = index.relatedFunctions(&svc, awsDataSource.declaredFactory())
 
-				fileName := fmt.Sprintf("%s.json", tfType)
-				filePath := filepath.Join(dataSourcesDir, fileName)
+				fileName, err := index.entryFileName(WalkCategoryDataSource, svc.ServiceName, tfType)
+				if err != nil {
+					return err
+				}
+				filePath := filepath.Join(dataSourcesDir, filepath.FromSlash(fileName))
 
 				if err := index.WriteJSONFile(filePath, awsDataSourceData); err != nil {
 					return fmt.Errorf("failed to write AWS Framework data source file %s: %w", fileName, err)
@@ -369,7 +782,7 @@ func (index *TerraformProviderIndex) WriteDataSourceFiles(outputDir string, prog
 
 // WriteEphemeralFiles writes individual JSON files for each ephemeral resource
 func (index *TerraformProviderIndex) WriteEphemeralFiles(outputDir string, progressTracker *ProgressTracker) error {
-	ephemeralDir := filepath.Join(outputDir, "ephemeral")
+	ephemeralDir := filepath.Join(outputDir, index.outputOptions.categoryDir(WalkCategoryEphemeral))
 
 	// Ensure ephemeral directory exists even if no files will be written
 	if err := outputFs.MkdirAll(ephemeralDir, 0755); err != nil {
@@ -379,8 +792,9 @@ func (index *TerraformProviderIndex) WriteEphemeralFiles(outputDir string, progr
 	var tasks []func() error
 
 	for _, service := range index.Services {
-		// Process legacy ephemeral resources (for backward compatibility)
-		for structType, tfType := range service.EphemeralTerraformTypes {
+		// Process legacy ephemeral resources (for backward compatibility). Mappings of an AWS ephemeral resource
+		// are written below, writing them here too would race with it for the same file
+		for structType, tfType := range service.legacyEphemeralTerraformTypes() {
 			// Capture variables for closure
 			structT := structType
 			svc := service
@@ -389,8 +803,12 @@ func (index *TerraformProviderIndex) WriteEphemeralFiles(outputDir string, progr
 			tasks = append(tasks, func() error {
 
 				ephemeralInfo := NewTerraformEphemeralInfo(structT, svc)
-				fileName := fmt.Sprintf("%s.json", terraformType)
-				filePath := filepath.Join(ephemeralDir, fileName)
+				ephemeralInfo.SDKType = index.sdkTypeLabel(ephemeralInfo.SDKType)
+				fileName, err := index.entryFileName(WalkCategoryEphemeral, svc.ServiceName, terraformType)
+				if err != nil {
+					return err
+				}
+				filePath := filepath.Join(ephemeralDir, filepath.FromSlash(fileName))
 
 				if err := index.WriteJSONFile(filePath, ephemeralInfo); err != nil {
 					return fmt.Errorf("failed to write ephemeral resource file %s: %w", fileName, err)
@@ -409,8 +827,12 @@ func (index *TerraformProviderIndex) WriteEphemeralFiles(outputDir string, progr
 
 			tasks = append(tasks, func() error {
 				ephemeralInfo := NewTerraformEphemeralFromAWS(ephemeral, svc)
-				fileName := fmt.Sprintf("%s.json", ephemeral.TerraformType)
-				filePath := filepath.Join(ephemeralDir, fileName)
+				ephemeralInfo.SDKType = index.sdkTypeLabel(ephemeralInfo.SDKType)
+				fileName, err := index.entryFileName(WalkCategoryEphemeral, svc.ServiceName, ephemeral.TerraformType)
+				if err != nil {
+					return err
+				}
+				filePath := filepath.Join(ephemeralDir, filepath.FromSlash(fileName))
 
 				if err := index.WriteJSONFile(filePath, ephemeralInfo); err != nil {
 					return fmt.Errorf("failed to write AWS ephemeral resource file %s: %w", fileName, err)
@@ -429,11 +851,16 @@ func (index *TerraformProviderIndex) WriteEphemeralFiles(outputDir string, progr
 
 // CreateDirectoryStructure creates the required directory structure for index files
 func (index *TerraformProviderIndex) CreateDirectoryStructure(outputDir string) error {
-	dirs := []string{
-		outputDir,
-		filepath.Join(outputDir, "resources"),
-		filepath.Join(outputDir, "datasources"),
-		filepath.Join(outputDir, "ephemeral"),
+	outputDir, err := cleanOutputDir(outputDir)
+	if err != nil {
+		return err
+	}
+
+	dirs := []string{outputDir}
+	for _, category := range walkCategories {
+		if index.writesCategory(category) {
+			dirs = append(dirs, filepath.Join(outputDir, index.outputOptions.categoryDir(category)))
+		}
 	}
 
 	for _, dir := range dirs {
@@ -445,6 +872,20 @@ func (index *TerraformProviderIndex) CreateDirectoryStructure(outputDir string)
 	return nil
 }
 
+// writesCategory reports whether entry files of the walk category are written, see ScanOptions.Categories
+func (index *TerraformProviderIndex) writesCategory(category string) bool {
+	return len(index.categories) == 0 || slices.Contains(index.categories, category)
+}
+
+// cleanOutputDir normalizes outputDir, so "out/", "./out" and "out" all write to the same files.
+// An empty output directory is rejected rather than silently writing to the working directory
+func cleanOutputDir(outputDir string) (string, error) {
+	if strings.TrimSpace(outputDir) == "" {
+		return "", fmt.Errorf("output directory must not be empty")
+	}
+	return filepath.Clean(outputDir), nil
+}
+
 // WriteJSONFile writes data as JSON to the specified file path
 func (index *TerraformProviderIndex) WriteJSONFile(filePath string, data interface{}) error {
 	// Ensure parent directory exists
@@ -453,14 +894,28 @@ func (index *TerraformProviderIndex) WriteJSONFile(filePath string, data interfa
 		return fmt.Errorf("failed to create parent directory %s: %w", parentDir, err)
 	}
 
-	// Marshal data to JSON with indentation
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+	// Marshal data to JSON, indented unless compact output was requested
+	var jsonData []byte
+	var err error
+	if index.outputOptions.Compact {
+		jsonData, err = json.Marshal(data)
+	} else {
+		jsonData, err = json.MarshalIndent(data, "", "  ")
+	}
 	if err != nil {
 		return fmt.Errorf("failed to marshal data to JSON: %w", err)
 	}
 
-	// Write to file
-	if err := afero.WriteFile(outputFs, filePath, jsonData, 0644); err != nil {
+	// Compress the same JSON content when requested
+	if index.outputOptions.Compress {
+		filePath = index.outputFileName(filePath)
+		if jsonData, err = gzipBytes(jsonData); err != nil {
+			return fmt.Errorf("failed to compress %s: %w", filePath, err)
+		}
+	}
+
+	// Write to file, retrying transient errors
+	if err := index.writeFileWithRetry(filePath, jsonData); err != nil {
 		return fmt.Errorf("failed to write file %s: %w", filePath, err)
 	}
 
@@ -474,39 +929,80 @@ func (index *TerraformProviderIndex) WriteJSONFile(filePath string, data interfa
 // parseAWSServiceFileWithAnnotations replaces parseAWSServiceFile with annotation-based scanning
 // This is the new Phase 3 integration function that uses the annotation scanner
 func parseAWSServiceFileWithAnnotations(packageInfo *gophon.PackageInfo, serviceReg *ServiceRegistration) error {
+	return parseAWSServiceFileWithOptions(packageInfo, serviceReg, ScanOptions{})
+}
+
+// parseAWSServiceFileWithOptions is like parseAWSServiceFileWithAnnotations but drops
+// annotations whose terraform types are excluded by the options before conversion
+func parseAWSServiceFileWithOptions(packageInfo *gophon.PackageInfo, serviceReg *ServiceRegistration, options ScanOptions) error {
 	// Use the annotation scanner to find all annotations in the package
-	annotationResults, err := ScanPackageForAnnotations(packageInfo)
+	annotationResults, err := scanPackageForAnnotations(packageInfo, options)
 	if err != nil {
 		return fmt.Errorf("failed to scan package for annotations: %w", err)
 	}
+	annotationResults = options.filterAnnotationResults(annotationResults)
+
+	// Flag annotations whose struct or factory could not be discovered
+	serviceReg.Warnings = append(serviceReg.Warnings, validateAnnotationResults(annotationResults, serviceReg.ServiceName)...)
 
 	// Convert annotation results to service registration format
 	convertAnnotationResultsToServiceRegistration(annotationResults, serviceReg)
 
+	// Flag registrations of undeclared factories and converted entries whose emitted factory is not declared
+	serviceReg.Warnings = append(serviceReg.Warnings, verifyFactoryFunctions(packageInfo, serviceReg)...)
+
+	// Every file of the package has been converted, so both maps are complete
+	serviceReg.MixedImplementation = serviceReg.isMixedImplementation()
+
 	return nil
 }
 
 // convertAnnotationResultsToServiceRegistration converts AnnotationResults to ServiceRegistration format
 // This function bridges the annotation scanner output with the existing ServiceRegistration structure
 func convertAnnotationResultsToServiceRegistration(results *AnnotationResults, serviceReg *ServiceRegistration) {
+	conflicts := newRegistrationConflictDetector()
+
 	// Process SDK Resources
 	for _, annotation := range results.SDKResources {
 		resourceInfo := AWSResource{
 			TerraformType:   annotation.TerraformType,
-			FactoryFunction: extractFactoryFunctionNameFromTerraformType(annotation.TerraformType, "resource"),
+			FactoryFunction: annotatedFactoryFunction(annotation, "resource"),
+			FunctionName:    annotation.FunctionName,
 			Name:            annotation.Name,
 			SDKType:         "sdk",
 			StructType:      "", // SDK resources don't have struct types
+
+			RegistrationFile: annotation.FilePath,
+			RawAnnotation:    annotation.RawAnnotation,
+			Aliases:          annotation.Aliases,
+			Region:           annotation.Region,
+			TestingConfig:    annotation.TestingConfig,
+			BuildTags:        annotation.BuildTags,
+			SourceHash:       annotation.SourceHash,
+			Tags:             annotation.Tags,
+
+			RegistrationMethod: annotation.RegistrationMethod,
+
+			Description:        annotation.Description,
+			DeprecationMessage: annotation.DeprecationMessage,
+
+			SchemaVersion:  annotation.SchemaVersion,
+			StateUpgraders: annotation.StateUpgraders,
+			Import:         annotation.Import,
+
+			Identity: annotation.Identity,
 		}
+		conflicts.record("resource", annotation.TerraformType, newAnnotationRegistrationRecord(annotation, resourceInfo, serviceReg))
 		serviceReg.AWSSDKResources[annotation.TerraformType] = resourceInfo
 
 		// Store CRUD methods if available from annotation scanner
-		if len(annotation.CRUDMethods) > 0 {
+		if len(annotation.CRUDMethods) > 0 || len(annotation.NoopMethods) > 0 {
 			legacyCRUD := &LegacyResourceCRUDFunctions{
 				CreateMethod: annotation.CRUDMethods["create"],
 				ReadMethod:   annotation.CRUDMethods["read"],
 				UpdateMethod: annotation.CRUDMethods["update"],
 				DeleteMethod: annotation.CRUDMethods["delete"],
+				NoopMethods:  annotation.NoopMethods,
 			}
 			serviceReg.ResourceCRUDMethods[annotation.TerraformType] = legacyCRUD
 		}
@@ -516,11 +1012,23 @@ func convertAnnotationResultsToServiceRegistration(results *AnnotationResults, s
 	for _, annotation := range results.SDKDataSources {
 		resourceInfo := AWSResource{
 			TerraformType:   annotation.TerraformType,
-			FactoryFunction: extractFactoryFunctionNameFromTerraformType(annotation.TerraformType, "dataSource"),
+			FactoryFunction: annotatedFactoryFunction(annotation, "dataSource"),
+			FunctionName:    annotation.FunctionName,
 			Name:            annotation.Name,
 			SDKType:         "sdk",
 			StructType:      "", // SDK data sources don't have struct types
+
+			RegistrationFile: annotation.FilePath,
+			RawAnnotation:    annotation.RawAnnotation,
+			Aliases:          annotation.Aliases,
+			Region:           annotation.Region,
+			TestingConfig:    annotation.TestingConfig,
+			BuildTags:        annotation.BuildTags,
+			Tags:             annotation.Tags,
+
+			RegistrationMethod: annotation.RegistrationMethod,
 		}
+		conflicts.record("data source", annotation.TerraformType, newAnnotationRegistrationRecord(annotation, resourceInfo, serviceReg))
 		serviceReg.AWSSDKDataSources[annotation.TerraformType] = resourceInfo
 
 		// Store read method if available from annotation scanner
@@ -534,30 +1042,77 @@ func convertAnnotationResultsToServiceRegistration(results *AnnotationResults, s
 
 	// Process Framework Resources
 	for _, annotation := range results.FrameworkResources {
+		if annotation.StructType == "" {
+			serviceReg.PartialEntries = append(serviceReg.PartialEntries, newUnresolvedStructPartialEntry(annotation, serviceReg))
+			continue
+		}
+
 		resourceInfo := AWSResource{
 			TerraformType:   annotation.TerraformType,
-			FactoryFunction: extractFactoryFunctionNameFromTerraformType(annotation.TerraformType, "frameworkResource"),
+			FactoryFunction: annotatedFactoryFunction(annotation, "frameworkResource"),
+			FunctionName:    annotation.FunctionName,
 			Name:            annotation.Name,
 			SDKType:         "framework",
 			StructType:      annotation.StructType,
+
+			ImplementedFrameworkMethods: annotation.ImplementedFrameworkMethods,
+			MissingFrameworkMethods:     annotation.MissingFrameworkMethods,
+
+			RegistrationFile: annotation.FilePath,
+			RawAnnotation:    annotation.RawAnnotation,
+			Aliases:          annotation.Aliases,
+			Region:           annotation.Region,
+			TestingConfig:    annotation.TestingConfig,
+			BuildTags:        annotation.BuildTags,
+			SourceHash:       annotation.SourceHash,
+			Tags:             annotation.Tags,
+
+			Identity: annotation.Identity,
 		}
+		conflicts.record("resource", annotation.TerraformType, newAnnotationRegistrationRecord(annotation, resourceInfo, serviceReg))
 		serviceReg.AWSFrameworkResources[annotation.TerraformType] = resourceInfo
 
 		// Store struct type to terraform type mapping for framework resources
 		if annotation.StructType != "" {
 			serviceReg.ResourceTerraformTypes[annotation.StructType] = annotation.TerraformType
 		}
+
+		// Store methods declared on the framework resource struct
+		if annotation.FrameworkCRUDMethods != nil {
+			if serviceReg.FrameworkResourceMethods == nil {
+				serviceReg.FrameworkResourceMethods = make(map[string]*AWSCRUDMethods)
+			}
+			serviceReg.FrameworkResourceMethods[annotation.TerraformType] = annotation.FrameworkCRUDMethods
+		}
 	}
 
 	// Process Framework Data Sources
 	for _, annotation := range results.FrameworkDataSources {
+		if annotation.StructType == "" {
+			serviceReg.PartialEntries = append(serviceReg.PartialEntries, newUnresolvedStructPartialEntry(annotation, serviceReg))
+			continue
+		}
+
 		resourceInfo := AWSResource{
 			TerraformType:   annotation.TerraformType,
-			FactoryFunction: extractFactoryFunctionNameFromTerraformType(annotation.TerraformType, "frameworkDataSource"),
+			FactoryFunction: annotatedFactoryFunction(annotation, "frameworkDataSource"),
+			FunctionName:    annotation.FunctionName,
 			Name:            annotation.Name,
 			SDKType:         "framework",
 			StructType:      annotation.StructType,
+
+			ImplementedFrameworkMethods: annotation.ImplementedFrameworkMethods,
+			MissingFrameworkMethods:     annotation.MissingFrameworkMethods,
+
+			RegistrationFile: annotation.FilePath,
+			RawAnnotation:    annotation.RawAnnotation,
+			Aliases:          annotation.Aliases,
+			Region:           annotation.Region,
+			TestingConfig:    annotation.TestingConfig,
+			BuildTags:        annotation.BuildTags,
+			Tags:             annotation.Tags,
 		}
+		conflicts.record("data source", annotation.TerraformType, newAnnotationRegistrationRecord(annotation, resourceInfo, serviceReg))
 		serviceReg.AWSFrameworkDataSources[annotation.TerraformType] = resourceInfo
 
 		// Store struct type to terraform type mapping for framework data sources
@@ -568,13 +1123,30 @@ func convertAnnotationResultsToServiceRegistration(results *AnnotationResults, s
 
 	// Process Ephemeral Resources
 	for _, annotation := range results.EphemeralResources {
+		if annotation.StructType == "" {
+			serviceReg.PartialEntries = append(serviceReg.PartialEntries, newUnresolvedStructPartialEntry(annotation, serviceReg))
+			continue
+		}
+
 		resourceInfo := AWSResource{
 			TerraformType:   annotation.TerraformType,
-			FactoryFunction: extractFactoryFunctionNameFromTerraformType(annotation.TerraformType, "ephemeral"),
+			FactoryFunction: annotatedFactoryFunction(annotation, "ephemeral"),
+			FunctionName:    annotation.FunctionName,
 			Name:            annotation.Name,
 			SDKType:         "framework", // Ephemeral resources use the Framework SDK
 			StructType:      annotation.StructType,
+
+			ImplementedFrameworkMethods: annotation.ImplementedFrameworkMethods,
+			MissingFrameworkMethods:     annotation.MissingFrameworkMethods,
+
+			RegistrationFile: annotation.FilePath,
+			RawAnnotation:    annotation.RawAnnotation,
+			Aliases:          annotation.Aliases,
+			Region:           annotation.Region,
+			TestingConfig:    annotation.TestingConfig,
+			BuildTags:        annotation.BuildTags,
 		}
+		conflicts.record("ephemeral resource", annotation.TerraformType, newAnnotationRegistrationRecord(annotation, resourceInfo, serviceReg))
 		serviceReg.AWSEphemeralResources[annotation.TerraformType] = resourceInfo
 
 		// Store struct type to terraform type mapping for ephemeral resources
@@ -582,6 +1154,122 @@ func convertAnnotationResultsToServiceRegistration(results *AnnotationResults, s
 			serviceReg.EphemeralTerraformTypes[annotation.StructType] = annotation.TerraformType
 		}
 	}
+
+	serviceReg.Warnings = append(serviceReg.Warnings, conflicts.warnings...)
+}
+
+// recordServiceRegistrations records every entry of a service in the cross-service conflict detector
+// Factory functions are qualified by package path since the same name in two services is a different function
+func recordServiceRegistrations(conflicts *registrationConflictDetector, serviceReg ServiceRegistration) {
+	categories := []struct {
+		category  string
+		resources map[string]AWSResource
+	}{
+		{"resource", serviceReg.AWSSDKResources},
+		{"resource", serviceReg.AWSFrameworkResources},
+		{"data source", serviceReg.AWSSDKDataSources},
+		{"data source", serviceReg.AWSFrameworkDataSources},
+		{"ephemeral resource", serviceReg.AWSEphemeralResources},
+	}
+
+	for _, c := range categories {
+		// Iterate in sorted order so warnings are deterministic
+		terraformTypes := make([]string, 0, len(c.resources))
+		for terraformType := range c.resources {
+			terraformTypes = append(terraformTypes, terraformType)
+		}
+		sort.Strings(terraformTypes)
+
+		for _, terraformType := range terraformTypes {
+			resource := c.resources[terraformType]
+			// Conflicts within the same service were already reported during conversion
+			key := c.category + "/" + terraformType
+			if previous, exists := conflicts.seen[key]; exists && previous.ServiceName == serviceReg.ServiceName {
+				continue
+			}
+			conflicts.record(c.category, terraformType, registrationRecord{
+				ServiceName:     serviceReg.ServiceName,
+				FactoryFunction: serviceReg.PackagePath + "." + resource.FactoryFunction,
+				SDKType:         resource.SDKType,
+				FilePath:        resource.RegistrationFile,
+			})
+		}
+	}
+}
+
+// newAnnotationRegistrationRecord creates a registrationRecord for an annotated entry within a service
+func newAnnotationRegistrationRecord(annotation AnnotationResult, resourceInfo AWSResource, serviceReg *ServiceRegistration) registrationRecord {
+	return registrationRecord{
+		ServiceName:     serviceReg.ServiceName,
+		FactoryFunction: resourceInfo.FactoryFunction,
+		SDKType:         resourceInfo.SDKType,
+		FilePath:        annotation.FilePath,
+	}
+}
+
+// makeRegistrationFilesRelative rewrites the registration file of every entry in the service
+// to be relative to baseDir, using forward slashes for stable output across platforms
+func makeRegistrationFilesRelative(serviceReg *ServiceRegistration, baseDir string) {
+	for _, resources := range []map[string]AWSResource{
+		serviceReg.AWSSDKResources,
+		serviceReg.AWSSDKDataSources,
+		serviceReg.AWSFrameworkResources,
+		serviceReg.AWSFrameworkDataSources,
+		serviceReg.AWSEphemeralResources,
+	} {
+		for terraformType, resource := range resources {
+			resource.RegistrationFile = relativeFilePath(baseDir, resource.RegistrationFile)
+			resources[terraformType] = resource
+		}
+	}
+
+	for i := range serviceReg.PartialEntries {
+		serviceReg.PartialEntries[i].FilePath = relativeFilePath(baseDir, serviceReg.PartialEntries[i].FilePath)
+	}
+}
+
+// relativeFilePath returns filePath relative to baseDir, or filePath unchanged if it cannot be made relative
+func relativeFilePath(baseDir, filePath string) string {
+	if filePath == "" {
+		return ""
+	}
+
+	absBase, err := filepath.Abs(baseDir)
+	if err != nil {
+		return filePath
+	}
+	absFile, err := filepath.Abs(filePath)
+	if err != nil {
+		return filePath
+	}
+
+	relPath, err := filepath.Rel(absBase, absFile)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return filePath
+	}
+
+	return filepath.ToSlash(relPath)
+}
+
+// newUnresolvedStructPartialEntry creates a PartialEntry for a framework annotation whose struct type could not be found
+func newUnresolvedStructPartialEntry(annotation AnnotationResult, serviceReg *ServiceRegistration) PartialEntry {
+	return PartialEntry{
+		ServiceName:    serviceReg.ServiceName,
+		TerraformType:  annotation.TerraformType,
+		AnnotationType: annotation.Type,
+		FilePath:       annotation.FilePath,
+		Reason:         "struct type unresolved: no struct with a Schema method found in file",
+	}
+}
+
+// annotatedFactoryFunction returns the factory function of an annotation, the annotated function itself, or the
+// name derived from the terraform type by extractFactoryFunctionNameFromTerraformType when the annotation sits on a
+// type declaration
+func annotatedFactoryFunction(annotation AnnotationResult, functionType string) string {
+	if annotation.FunctionName != "" {
+		return annotation.FunctionName
+	}
+	return extractFactoryFunctionNameFromTerraformType(annotation.TerraformType, functionType)
 }
 
 // extractFactoryFunctionNameFromTerraformType extracts the likely factory function name from terraform type
@@ -642,8 +1330,9 @@ func convertFunctionNamesToStructNames(functionNames []string, packageInfo *goph
 		structName := ""
 		for _, funcInfo := range packageInfo.Functions {
 			if funcInfo.Name == funcName && funcInfo.FuncDecl != nil {
-				// Extract struct type from the function's return statement
-				if extracted := extractStructTypeFromEphemeralFunction(funcInfo.FuncDecl); extracted != "" {
+				// Extract struct type from the function's return statements, preferring the struct implementing
+				// Schema when conditional returns construct different structs
+				if extracted := preferStructWithSchemaMethod(returnedStructTypes(funcInfo.FuncDecl), packageInfo); extracted != "" {
 					structName = extracted
 					break
 				}
@@ -669,31 +1358,74 @@ func convertFunctionNamesToStructNames(functionNames []string, packageInfo *goph
 // For example, from: func NewKeyVaultSecretEphemeralResource() ephemeral.EphemeralResource { return &KeyVaultSecretEphemeralResource{} }
 // It extracts: "KeyVaultSecretEphemeralResource"
 func extractStructTypeFromEphemeralFunction(funcDecl *ast.FuncDecl) string {
+	if structTypes := returnedStructTypes(funcDecl); len(structTypes) > 0 {
+		return structTypes[0]
+	}
+	return ""
+}
+
+// returnedStructTypes returns the struct types constructed by the return statements of funcDecl, including
+// returns nested in conditionals, in source order without duplicates. Function literals are not searched
+func returnedStructTypes(funcDecl *ast.FuncDecl) []string {
 	if funcDecl == nil || funcDecl.Body == nil {
-		return ""
+		return nil
 	}
 
-	// Look for return statements in the function body
-	for _, stmt := range funcDecl.Body.List {
-		if returnStmt, ok := stmt.(*ast.ReturnStmt); ok {
-			for _, result := range returnStmt.Results {
-				// Handle &StructName{} pattern
+	var structTypes []string
+	seen := make(map[string]bool)
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				// Handle both &StructName{} and StructName{} (without &)
 				if unaryExpr, ok := result.(*ast.UnaryExpr); ok && unaryExpr.Op == token.AND {
-					if compLit, ok := unaryExpr.X.(*ast.CompositeLit); ok {
-						if ident, ok := compLit.Type.(*ast.Ident); ok {
-							return ident.Name
-						}
-					}
+					result = unaryExpr.X
 				}
-				// Handle StructName{} pattern (without &)
-				if compLit, ok := result.(*ast.CompositeLit); ok {
-					if ident, ok := compLit.Type.(*ast.Ident); ok {
-						return ident.Name
-					}
+				compLit, ok := result.(*ast.CompositeLit)
+				if !ok {
+					continue
+				}
+				if ident, ok := compLit.Type.(*ast.Ident); ok && !seen[ident.Name] {
+					seen[ident.Name] = true
+					structTypes = append(structTypes, ident.Name)
+				}
+			}
+		}
+		return true
+	})
+
+	return structTypes
+}
+
+// preferStructWithSchemaMethod picks the struct type a factory returns. When the factory constructs several,
+// the first one with a Schema method in the package wins, otherwise the first one
+func preferStructWithSchemaMethod(structTypes []string, packageInfo *gophon.PackageInfo) string {
+	if len(structTypes) == 0 {
+		return ""
+	}
+	if len(structTypes) > 1 {
+		for _, structType := range structTypes {
+			for _, funcInfo := range packageInfo.Functions {
+				if funcInfo.FuncDecl != nil && funcInfo.FuncDecl.Recv != nil && funcInfo.FuncDecl.Name.Name == "Schema" && receiverTypeName(funcInfo.FuncDecl) == structType {
+					return structType
 				}
 			}
 		}
 	}
+	return structTypes[0]
+}
 
-	return ""
+// gzipBytes compresses data with gzip
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
@@ -1,12 +1,21 @@
 package pkg
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	gophon "github.com/lonegunmanb/gophon/pkg"
 	"github.com/prashantv/gostub"
@@ -204,6 +213,54 @@ func TestTerraformProviderIndex_WriteMainIndexFile(t *testing.T) {
 	assert.Equal(t, index.Statistics, readIndex.Statistics)
 }
 
+func TestTerraformProviderIndex_WriteIndexFiles_NormalizesOutputDir(t *testing.T) {
+	tests := []struct {
+		name        string
+		outputDir   string
+		expectedDir string
+	}{
+		{name: "trailing slash", outputDir: "/test/output/", expectedDir: "/test/output"},
+		{name: "relative with dot segment", outputDir: "./out", expectedDir: "out"},
+		{name: "redundant segments", outputDir: "/test/./nested/../output", expectedDir: "/test/output"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			index := createTestTerraformProviderIndex()
+			fs := afero.NewMemMapFs()
+			stub := gostub.Stub(&outputFs, fs)
+			defer stub.Reset()
+
+			// Execute
+			err := index.WriteIndexFiles(tt.outputDir, nil)
+
+			// Verify
+			require.NoError(t, err)
+			plan, err := index.PlanIndexFiles(tt.outputDir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedDir, plan.OutputDir)
+			for _, file := range plan.Files {
+				exists, err := afero.Exists(fs, filepath.Join(tt.expectedDir, filepath.FromSlash(file.Path)))
+				require.NoError(t, err)
+				assert.True(t, exists, "File should exist: %s", file.Path)
+			}
+		})
+	}
+}
+
+func TestTerraformProviderIndex_WriteIndexFiles_EmptyOutputDir(t *testing.T) {
+	index := createTestTerraformProviderIndex()
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+
+	assert.ErrorContains(t, index.WriteIndexFiles("", nil), "output directory must not be empty")
+	assert.ErrorContains(t, index.CreateDirectoryStructure(" "), "output directory must not be empty")
+	_, err := index.PlanIndexFiles("")
+	assert.ErrorContains(t, err, "output directory must not be empty")
+}
+
 func TestTerraformProviderIndex_CreateDirectoryStructure(t *testing.T) {
 	// Setup
 	index := createTestTerraformProviderIndex()
@@ -442,6 +499,21 @@ func NewFoundFunction() ephemeral.EphemeralResource {
 }`,
 			expected: []string{"FoundEphemeralResource", "NotFoundFunction"}, // Not found falls back to string manipulation
 		},
+		{
+			name:          "Conditional returns prefer the struct implementing Schema",
+			functionNames: []string{"NewWidgetEphemeralResource"},
+			packageCode: `
+package test
+func NewWidgetEphemeralResource() ephemeral.EphemeralResource {
+	if legacyWidgets {
+		return &legacyWidgetEphemeralResource{}
+	}
+	return &widgetEphemeralResource{}
+}
+func (w *widgetEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+}`,
+			expected: []string{"widgetEphemeralResource"},
+		},
 		{
 			name:          "Function without New prefix",
 			functionNames: []string{"CreateSomething"},
@@ -489,3 +561,2223 @@ func createMockPackageInfoWithFunctions(t *testing.T, packageCode string) *gopho
 		Functions: functions,
 	}
 }
+
+// Helper function to create a mock PackageInfo with a single file parsed from code
+func createMockPackageInfoFromSource(t *testing.T, filePath, packagePath, sourceCode string) *gophon.PackageInfo {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, sourceCode, parser.ParseComments)
+	require.NoError(t, err)
+
+	return &gophon.PackageInfo{
+		Files: []*gophon.FileInfo{
+			{
+				File:     file,
+				FileName: filePath,
+				FilePath: filePath,
+				Package:  packagePath,
+			},
+		},
+	}
+}
+
+func TestScanTerraformProviderServices_RecordsScanErrors(t *testing.T) {
+	// Setup - two service directories, one of which cannot be parsed
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/services/good", 0755))
+	require.NoError(t, fs.MkdirAll("/services/broken", 0755))
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	goodPackage := createMockPackageInfoFromSource(t, "/services/good/widget.go", "example.com/provider/good", `package good
+
+// @SDKResource("aws_good_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{}
+}
+`)
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		if filepath.Base(pkgPath) == "broken" {
+			return nil, fmt.Errorf("syntax error: unexpected EOF")
+		}
+		return goodPackage, nil
+	})
+	defer scanStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServices("/services", "example.com/provider", "v1.0.0", nil)
+
+	// Verify - the scan succeeds and the failure is observable
+	require.NoError(t, err)
+	require.Len(t, index.Services, 1)
+	assert.Equal(t, "good", index.Services[0].ServiceName)
+	assert.Contains(t, index.Services[0].AWSSDKResources, "aws_good_widget")
+
+	require.Len(t, index.ScanErrors, 1)
+	assert.Equal(t, "broken", index.ScanErrors[0].ServiceName)
+	assert.Contains(t, index.ScanErrors[0].Error, "unexpected EOF")
+}
+
+func TestScanTerraformProviderServices_RecordsPartialEntries(t *testing.T) {
+	// Setup - a framework resource whose struct has no Schema method cannot be resolved
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/services/widget", 0755))
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	packageInfo := createMockPackageInfoFromSource(t, "/services/widget/widget.go", "example.com/provider/widget", `package widget
+
+// @SDKResource("aws_widget_sdk", name="Widget SDK")
+func resourceWidgetSDK() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @FrameworkResource("aws_widget_framework", name="Widget Framework")
+func newWidgetFrameworkResource(_ context.Context) (resource.ResourceWithConfigure, error) {
+	return newWidgetFrameworkResourceImpl(), nil
+}
+`)
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		return packageInfo, nil
+	})
+	defer scanStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServices("/services", "example.com/provider", "v1.0.0", nil)
+
+	// Verify - the unresolved entry is reported instead of being indexed
+	require.NoError(t, err)
+	require.Len(t, index.Services, 1)
+	assert.Contains(t, index.Services[0].AWSSDKResources, "aws_widget_sdk")
+	assert.NotContains(t, index.Services[0].AWSFrameworkResources, "aws_widget_framework")
+	assert.Equal(t, 1, index.Statistics.TotalResources)
+
+	require.Len(t, index.PartialEntries, 1)
+	partial := index.PartialEntries[0]
+	assert.Equal(t, "widget", partial.ServiceName)
+	assert.Equal(t, "aws_widget_framework", partial.TerraformType)
+	assert.Equal(t, AnnotationFrameworkResource, partial.AnnotationType)
+	assert.Equal(t, "widget/widget.go", partial.FilePath)
+	assert.Contains(t, partial.Reason, "struct type unresolved")
+}
+
+func TestScanTerraformProviderServices_WarnsOnUnresolvedAnnotations(t *testing.T) {
+	// Setup - the framework struct lacks a Schema method and one SDK resource declares no CRUD methods
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/services/widget", 0755))
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	packageInfo := createMockPackageInfoFromSource(t, "/services/widget/empty.go", "example.com/provider/widget", `package widget
+
+// @SDKResource("aws_widget_empty", name="Widget Empty")
+func resourceWidgetEmpty() *schema.Resource {
+	return &schema.Resource{}
+}
+`)
+	secondFile := createMockPackageInfoFromSource(t, "/services/widget/widget.go", "example.com/provider/widget", `package widget
+
+// @SDKResource("aws_widget_sdk", name="Widget SDK")
+func resourceWidgetSDK() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetSDKRead,
+	}
+}
+
+// @FrameworkResource("aws_widget_framework", name="Widget Framework")
+func newWidgetFrameworkResource(_ context.Context) (resource.ResourceWithConfigure, error) {
+	return &widgetFrameworkResource{}, nil
+}
+
+type widgetFrameworkResource struct{}
+
+func (r *widgetFrameworkResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {}
+`)
+	packageInfo.Files = append(packageInfo.Files, secondFile.Files...)
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		return packageInfo, nil
+	})
+	defer scanStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServices("/services", "example.com/provider", "v1.0.0", nil)
+
+	// Verify - both unresolved annotations are reported, the resolved SDK resource is not
+	require.NoError(t, err)
+	require.Len(t, index.Services, 1)
+	warnings := index.Services[0].Warnings
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "@SDKResource aws_widget_empty in widget")
+	assert.Contains(t, warnings[0], "no CRUD methods found")
+	assert.Contains(t, warnings[1], "@FrameworkResource aws_widget_framework in widget")
+	assert.Contains(t, warnings[1], "no struct with a Schema method found")
+	assert.ElementsMatch(t, warnings, index.Statistics.Warnings)
+
+	require.Len(t, index.PartialEntries, 1)
+	assert.Equal(t, "aws_widget_framework", index.PartialEntries[0].TerraformType)
+}
+
+func TestScanTerraformProviderServices_RecordsAliases(t *testing.T) {
+	// Setup - aws_widget was renamed, the old type stays registered by a second annotation
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/services/widget", 0755))
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	packageInfo := createMockPackageInfoFromSource(t, "/services/widget/widget.go", "example.com/provider/widget", `package widget
+
+// @SDKResource("aws_widget", name="Widget")
+// @SDKResource("aws_legacy_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+`)
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		return packageInfo, nil
+	})
+	defer scanStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServices("/services", "example.com/provider", "v1.0.0", nil)
+
+	// Verify - the alias points to the primary type without becoming an entry of its own
+	require.NoError(t, err)
+	require.Len(t, index.Services, 1)
+	service := index.Services[0]
+	assert.Equal(t, []string{"aws_legacy_widget"}, service.AWSSDKResources["aws_widget"].Aliases)
+	assert.NotContains(t, service.AWSSDKResources, "aws_legacy_widget")
+	assert.Equal(t, 1, index.Statistics.TotalResources)
+	assert.Equal(t, []TypeAlias{{
+		Alias:         "aws_legacy_widget",
+		TerraformType: "aws_widget",
+		Category:      WalkCategoryResource,
+		ServiceName:   "widget",
+	}}, index.Aliases)
+
+	outFs := afero.NewMemMapFs()
+	outputStub := gostub.Stub(&outputFs, outFs)
+	defer outputStub.Reset()
+	require.NoError(t, index.WriteIndexFiles("/out", nil))
+	exists, err := afero.Exists(outFs, "/out/resources/aws_legacy_widget.json")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestScanTerraformProviderServices_NonStandardServicePackageReceiver(t *testing.T) {
+	// Setup - registration methods live on a renamed receiver type instead of servicePackage,
+	// detection relies on annotations only so the receiver name must not matter
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/services/widget", 0755))
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	packageInfo := createMockPackageInfoFromSource(t, "/services/widget/service_package.go", "example.com/provider/widget", `package widget
+
+type widgetServiceRegistry struct{}
+
+func (p *widgetServiceRegistry) SDKResources(ctx context.Context) []*types.ServicePackageSDKResource {
+	return []*types.ServicePackageSDKResource{
+		{
+			Factory:  resourceWidget,
+			TypeName: "aws_widget",
+		},
+	}
+}
+
+func (p *widgetServiceRegistry) FrameworkResources(ctx context.Context) []*types.ServicePackageFrameworkResource {
+	return []*types.ServicePackageFrameworkResource{
+		{
+			Factory:  newGadgetResource,
+			TypeName: "aws_widget_gadget",
+		},
+	}
+}
+`)
+	resourceFile := createMockPackageInfoFromSource(t, "/services/widget/widget.go", "example.com/provider/widget", `package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+`)
+	gadgetFile := createMockPackageInfoFromSource(t, "/services/widget/gadget.go", "example.com/provider/widget", `package widget
+
+// @FrameworkResource("aws_widget_gadget", name="Gadget")
+func newGadgetResource(_ context.Context) (resource.ResourceWithConfigure, error) {
+	return &gadgetResource{}, nil
+}
+
+func (r *gadgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+
+func (r *gadgetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {}
+`)
+	packageInfo.Files = append(packageInfo.Files, resourceFile.Files...)
+	packageInfo.Files = append(packageInfo.Files, gadgetFile.Files...)
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		return packageInfo, nil
+	})
+	defer scanStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServices("/services", "example.com/provider", "v1.0.0", nil)
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, index.Services, 1)
+	service := index.Services[0]
+	assert.Equal(t, "widget", service.ServiceName)
+	require.Contains(t, service.AWSSDKResources, "aws_widget")
+	assert.Equal(t, "resourceWidgetRead", service.ResourceCRUDMethods["aws_widget"].ReadMethod)
+	require.Contains(t, service.AWSFrameworkResources, "aws_widget_gadget")
+	assert.Equal(t, "gadgetResource", service.AWSFrameworkResources["aws_widget_gadget"].StructType)
+	assert.Equal(t, "newGadgetResource", service.AWSFrameworkResources["aws_widget_gadget"].FactoryFunction)
+	assert.Empty(t, service.Warnings)
+}
+
+func TestScanTerraformProviderServices_MixedImplementation(t *testing.T) {
+	// Setup - "mixed" registers SDK and Framework resources from separate files, "sdkonly" only SDK ones
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/services/mixed", 0755))
+	require.NoError(t, fs.MkdirAll("/services/sdkonly", 0755))
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	mixedPackage := createMockPackageInfoFromSource(t, "/services/mixed/widget.go", "example.com/provider/mixed", `package mixed
+
+// @SDKResource("aws_mixed_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+`)
+	frameworkFile := createMockPackageInfoFromSource(t, "/services/mixed/gadget.go", "example.com/provider/mixed", `package mixed
+
+// @FrameworkResource("aws_mixed_gadget", name="Gadget")
+func newGadgetResource(_ context.Context) (resource.ResourceWithConfigure, error) {
+	return &gadgetResource{}, nil
+}
+
+func (r *gadgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+`)
+	mixedPackage.Files = append(mixedPackage.Files, frameworkFile.Files...)
+
+	sdkOnlyPackage := createMockPackageInfoFromSource(t, "/services/sdkonly/widget.go", "example.com/provider/sdkonly", `package sdkonly
+
+// @SDKResource("aws_sdkonly_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+`)
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		if filepath.Base(pkgPath) == "mixed" {
+			return mixedPackage, nil
+		}
+		return sdkOnlyPackage, nil
+	})
+	defer scanStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServices("/services", "example.com/provider", "v1.0.0", nil)
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, index.Services, 2)
+	assert.Equal(t, "mixed", index.Services[0].ServiceName)
+	assert.True(t, index.Services[0].MixedImplementation)
+	assert.Equal(t, "sdkonly", index.Services[1].ServiceName)
+	assert.False(t, index.Services[1].MixedImplementation)
+
+	serviceJSON, err := json.Marshal(index.Services[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(serviceJSON), `"mixed_implementation":true`)
+	assert.True(t, newServiceFile(&index.Services[0]).MixedImplementation)
+}
+
+func TestNewProviderStatistics_CountsLegacyEphemeralFiles(t *testing.T) {
+	// Setup - one AWS ephemeral resource also in the legacy mapping, one legacy-only mapping
+	kms := CreateTestServiceRegistration("kms")
+	kms.AWSEphemeralResources["aws_kms_secrets"] = CreateTestAWSResourceInfo("ephemeral_resource", "aws_kms_secrets", "newSecretsEphemeralResource", "Secrets")
+	kms.EphemeralTerraformTypes["secretsEphemeralResource"] = "aws_kms_secrets"
+	kms.EphemeralTerraformTypes["keyEphemeralResource"] = "aws_kms_key"
+	index := &TerraformProviderIndex{Version: "v1.0.0", Services: []ServiceRegistration{kms}}
+
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+
+	// Execute
+	index.Statistics = newProviderStatistics(index.Services)
+	require.NoError(t, index.WriteEphemeralFiles("/output", nil))
+
+	// Verify - statistics match the ephemeral files written
+	files, err := afero.ReadDir(fs, "/output/ephemeral")
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, len(files), index.Statistics.EphemeralResources)
+	assert.Equal(t, 1, index.Statistics.LegacyEphemeralResources)
+	assert.Equal(t, 2, index.Statistics.PerService["kms"].EphemeralResources)
+
+	// The SDK and Framework breakdown adds up to the category totals
+	kmsStats := index.Statistics.PerService["kms"]
+	assert.Equal(t, 2, kmsStats.Framework)
+	assert.Equal(t, kmsStats.Resources+kmsStats.DataSources+kmsStats.EphemeralResources, kmsStats.SDK+kmsStats.Framework)
+}
+
+func TestScanTerraformProviderServices_PerServiceStatistics(t *testing.T) {
+	// Setup - two fabricated services with a mix of categories
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/services/alpha", 0755))
+	require.NoError(t, fs.MkdirAll("/services/beta", 0755))
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	packages := map[string]*gophon.PackageInfo{
+		"alpha": createMockPackageInfoFromSource(t, "/services/alpha/alpha.go", "example.com/provider/alpha", `package alpha
+
+// @SDKResource("aws_alpha_one", name="One")
+func resourceOne() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @SDKResource("aws_alpha_two", name="Two")
+func resourceTwo() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @SDKDataSource("aws_alpha_one", name="One")
+func dataSourceOne() *schema.Resource {
+	return &schema.Resource{}
+}
+`),
+		"beta": createMockPackageInfoFromSource(t, "/services/beta/beta.go", "example.com/provider/beta", `package beta
+
+// @FrameworkResource("aws_beta_thing", name="Thing")
+func newThingResource(_ context.Context) (resource.ResourceWithConfigure, error) {
+	return &thingResource{}, nil
+}
+
+func (r *thingResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+`),
+	}
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		return packages[filepath.Base(pkgPath)], nil
+	})
+	defer scanStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServices("/services", "example.com/provider", "v1.0.0", nil)
+
+	// Verify
+	require.NoError(t, err)
+	stats := index.Statistics
+	require.Len(t, stats.PerService, 2)
+
+	assert.Equal(t, ServiceStats{Resources: 2, DataSources: 1, SDK: 3}, stats.PerService["alpha"])
+	assert.Equal(t, ServiceStats{Resources: 1, Framework: 1}, stats.PerService["beta"])
+
+	var resources, dataSources, ephemeral int
+	for _, serviceStats := range stats.PerService {
+		resources += serviceStats.Resources
+		dataSources += serviceStats.DataSources
+		ephemeral += serviceStats.EphemeralResources
+		assert.Equal(t, serviceStats.Resources+serviceStats.DataSources+serviceStats.EphemeralResources,
+			serviceStats.SDK+serviceStats.Framework)
+	}
+	assert.Equal(t, stats.TotalResources, resources)
+	assert.Equal(t, stats.TotalDataSources, dataSources)
+	assert.Equal(t, stats.EphemeralResources, ephemeral)
+}
+
+func TestScanTerraformProviderServices_RecordsRegistrationFile(t *testing.T) {
+	// Setup - one service declaring SDK, framework and ephemeral resources
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/services/lambda", 0755))
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	packageInfo := createMockPackageInfoFromSource(t, "/services/lambda/invocation.go", "example.com/provider/lambda", `package lambda
+
+// @SDKResource("aws_lambda_invocation", name="Invocation")
+func resourceInvocation() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @FrameworkResource("aws_lambda_function_url", name="Function URL")
+func newFunctionURLResource(_ context.Context) (resource.ResourceWithConfigure, error) {
+	return &functionURLResource{}, nil
+}
+
+func (r *functionURLResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+
+// @EphemeralResource("aws_lambda_invocation", name="Invocation")
+func newInvocationEphemeralResource(_ context.Context) (ephemeral.EphemeralResourceWithConfigure, error) {
+	return &invocationEphemeralResource{}, nil
+}
+
+func (e *invocationEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {}
+`)
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		return packageInfo, nil
+	})
+	defer scanStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServices("/services", "example.com/provider", "v1.0.0", nil)
+
+	// Verify - registration files are relative to the scanned base directory
+	require.NoError(t, err)
+	require.Len(t, index.Services, 1)
+	service := index.Services[0]
+	expectedFile := "lambda/invocation.go"
+
+	assert.Equal(t, expectedFile, service.AWSSDKResources["aws_lambda_invocation"].RegistrationFile)
+	assert.Equal(t, expectedFile, service.AWSFrameworkResources["aws_lambda_function_url"].RegistrationFile)
+	assert.Equal(t, expectedFile, service.AWSEphemeralResources["aws_lambda_invocation"].RegistrationFile)
+
+	// Emitted entries carry the registration file as well
+	sdkResource := NewTerraformResourceFromAWSSDK(service.AWSSDKResources["aws_lambda_invocation"], service)
+	assert.Equal(t, expectedFile, sdkResource.RegistrationFile)
+	frameworkResource := NewTerraformResourceFromAWSFramework(service.AWSFrameworkResources["aws_lambda_function_url"], service, service.Package)
+	assert.Equal(t, expectedFile, frameworkResource.RegistrationFile)
+	ephemeralResource := NewTerraformEphemeralFromAWS(service.AWSEphemeralResources["aws_lambda_invocation"], service)
+	assert.Equal(t, expectedFile, ephemeralResource.RegistrationFile)
+}
+
+func TestScanTerraformProviderServices_WarnsOnConflictingRegistrations(t *testing.T) {
+	// Setup - service "dup" registers aws_dup_resource from two files, service "other" registers it again
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/services/dup", 0755))
+	require.NoError(t, fs.MkdirAll("/services/other", 0755))
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	dupPackage := createMockPackageInfoFromSource(t, "/services/dup/a.go", "example.com/provider/dup", `package dup
+
+// @SDKResource("aws_dup_resource", name="Dup")
+func resourceDup() *schema.Resource {
+	return &schema.Resource{}
+}
+`)
+	secondFile := createMockPackageInfoFromSource(t, "/services/dup/b.go", "example.com/provider/dup", `package dup
+
+// @SDKResource("aws_dup_resource", name="Dup")
+func resourceDupV2() *schema.Resource {
+	return &schema.Resource{}
+}
+`)
+	dupPackage.Files = append(dupPackage.Files, secondFile.Files...)
+
+	otherPackage := createMockPackageInfoFromSource(t, "/services/other/c.go", "example.com/provider/other", `package other
+
+// @SDKResource("aws_dup_resource", name="Dup")
+func resourceDup() *schema.Resource {
+	return &schema.Resource{}
+}
+`)
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		if filepath.Base(pkgPath) == "dup" {
+			return dupPackage, nil
+		}
+		return otherPackage, nil
+	})
+	defer scanStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServices("/services", "example.com/provider", "v1.0.0", nil)
+
+	// Verify - last write wins within the service, but the collisions are visible. Every registration of the
+	// empty schema.Resource is reported as unresolved too
+	require.NoError(t, err)
+	require.Len(t, index.Services, 2)
+	for _, service := range index.Services {
+		if service.ServiceName == "dup" {
+			assert.Equal(t, "dup/b.go", service.AWSSDKResources["aws_dup_resource"].RegistrationFile)
+			require.Len(t, service.Warnings, 3)
+			assert.Contains(t, service.Warnings[0], "unresolved @SDKResource aws_dup_resource in dup (/services/dup/a.go): no CRUD methods found")
+			assert.Contains(t, service.Warnings[1], "unresolved @SDKResource aws_dup_resource in dup (/services/dup/b.go): no CRUD methods found")
+			assert.Contains(t, service.Warnings[2], "resourceDupV2")
+			assert.Contains(t, service.Warnings[2], "resourceDup ")
+		}
+	}
+
+	// Both services also report their registrations as unresolved
+	var conflicts []string
+	for _, warning := range index.Statistics.Warnings {
+		if strings.HasPrefix(warning, "conflicting ") {
+			conflicts = append(conflicts, warning)
+		}
+	}
+	require.Len(t, conflicts, 2)
+	for _, warning := range conflicts {
+		assert.Contains(t, warning, "conflicting resource registration for aws_dup_resource")
+	}
+	assert.Len(t, index.Statistics.Warnings, 5)
+}
+
+func TestConvertAnnotationResultsToServiceRegistration_NoWarningForDistinctCategories(t *testing.T) {
+	// A resource and a data source sharing a terraform type is not a conflict
+	serviceReg := CreateTestServiceRegistration("s3")
+	results := NewAnnotationResults()
+	results.Add(AnnotationResult{Type: AnnotationSDKResource, TerraformType: "aws_s3_bucket", FunctionName: "resourceBucket"})
+	results.Add(AnnotationResult{Type: AnnotationSDKDataSource, TerraformType: "aws_s3_bucket", FunctionName: "dataSourceBucket"})
+
+	convertAnnotationResultsToServiceRegistration(results, &serviceReg)
+
+	assert.Empty(t, serviceReg.Warnings)
+}
+
+func TestConvertAnnotationResultsToServiceRegistration_Identity(t *testing.T) {
+	// Setup - a resource carrying two identity attributes
+	serviceReg := CreateTestServiceRegistration("widget")
+	identity := &AWSIdentityConfig{
+		Attributes: []AWSIdentityAttribute{
+			{Name: "widget_id"},
+			{Name: "attachment_id"},
+		},
+	}
+	results := NewAnnotationResults()
+	results.Add(AnnotationResult{Type: AnnotationSDKResource, TerraformType: "aws_widget_attachment", Identity: identity})
+	results.Add(AnnotationResult{Type: AnnotationFrameworkResource, TerraformType: "aws_widget_policy", StructType: "widgetPolicyResource", Identity: &AWSIdentityConfig{ARNAttribute: "arn"}})
+	results.Add(AnnotationResult{Type: AnnotationSDKResource, TerraformType: "aws_widget"})
+
+	// Execute
+	convertAnnotationResultsToServiceRegistration(results, &serviceReg)
+
+	// Verify
+	assert.Equal(t, identity, serviceReg.AWSSDKResources["aws_widget_attachment"].Identity)
+	assert.Equal(t, "arn", serviceReg.AWSFrameworkResources["aws_widget_policy"].Identity.ARNAttribute)
+	assert.Nil(t, serviceReg.AWSSDKResources["aws_widget"].Identity)
+}
+
+func TestScanTerraformProviderServicesContext_Cancelled(t *testing.T) {
+	// Setup - many services, the scan is cancelled while the first one is being scanned
+	fs := afero.NewMemMapFs()
+	for i := 0; i < 50; i++ {
+		require.NoError(t, fs.MkdirAll(fmt.Sprintf("/services/svc%02d", i), 0755))
+	}
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var scanned int64
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		atomic.AddInt64(&scanned, 1)
+		cancel()
+		return nil, nil
+	})
+	defer scanStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServicesContext(ctx, "/services", "example.com/provider", "v1.0.0", nil)
+
+	// Verify
+	assert.Nil(t, index)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.LessOrEqual(t, atomic.LoadInt64(&scanned), int64(runtime.NumCPU()), "workers should stop picking up services after cancellation")
+}
+
+func TestScanTerraformProviderServicesContext_AlreadyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	index, err := ScanTerraformProviderServicesContext(ctx, "/services", "example.com/provider", "v1.0.0", nil)
+
+	assert.Nil(t, index)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTerraformProviderIndex_FactoryFunctionIndex(t *testing.T) {
+	// Setup - a factory function name reused across two services
+	index := createTestTerraformProviderIndex()
+	other := CreateTestServiceRegistration("s3control")
+	other.AWSSDKResources["aws_s3control_bucket_policy"] = AWSResource{
+		TerraformType:   "aws_s3control_bucket_policy",
+		FactoryFunction: "resourceBucketPolicy",
+		SDKType:         "sdk",
+	}
+	index.Services = append(index.Services, other)
+
+	// Execute
+	factoryIndex := index.FactoryFunctionIndex()
+
+	// Verify reverse direction
+	assert.Equal(t, []string{"aws_s3_bucket_policy", "aws_s3control_bucket_policy"}, factoryIndex["resourceBucketPolicy"])
+	assert.Equal(t, []string{"aws_s3_bucket"}, factoryIndex["newBucketResource"])
+	assert.Equal(t, []string{"aws_s3_bucket"}, factoryIndex["dataSourceS3Bucket"])
+
+	// Verify forward/reverse consistency: every entry maps back to its own factory function
+	for _, service := range index.Services {
+		for _, resources := range []map[string]AWSResource{
+			service.AWSSDKResources,
+			service.AWSSDKDataSources,
+			service.AWSFrameworkResources,
+			service.AWSFrameworkDataSources,
+			service.AWSEphemeralResources,
+		} {
+			for terraformType, resource := range resources {
+				assert.Contains(t, factoryIndex[resource.declaredFactory()], terraformType)
+			}
+		}
+	}
+	for factoryFunction, terraformTypes := range factoryIndex {
+		for _, terraformType := range terraformTypes {
+			found := false
+			for _, service := range index.Services {
+				for _, resources := range []map[string]AWSResource{
+					service.AWSSDKResources,
+					service.AWSSDKDataSources,
+					service.AWSFrameworkResources,
+					service.AWSFrameworkDataSources,
+					service.AWSEphemeralResources,
+				} {
+					if resource, exists := resources[terraformType]; exists && resource.FactoryFunction == factoryFunction {
+						found = true
+					}
+				}
+			}
+			assert.True(t, found, "%s -> %s should exist in the index", factoryFunction, terraformType)
+		}
+	}
+}
+
+func TestTerraformProviderIndex_FactoryFunctionIndex_ScannedService(t *testing.T) {
+	// Setup - resourceBucket is not the name derived from the terraform type, resourceS3Bucket
+	serviceReg, err := ParseServiceSource([]byte(`package s3
+
+// @SDKResource("aws_s3_bucket", name="Bucket")
+func resourceBucket() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @FrameworkDataSource("aws_s3_bucket", name="Bucket")
+func newBucketDataSource(context.Context) (datasource.DataSourceWithConfigure, error) {
+	return &bucketDataSource{}, nil
+}
+
+type bucketDataSource struct{}
+
+func (d *bucketDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+}
+`), "github.com/hashicorp/terraform-provider-aws/internal/service/s3")
+	require.NoError(t, err)
+	index := &TerraformProviderIndex{Services: []ServiceRegistration{*serviceReg}}
+
+	// Execute
+	factoryIndex := index.FactoryFunctionIndex()
+
+	// Verify
+	assert.Equal(t, map[string][]string{
+		"resourceBucket":      {"aws_s3_bucket"},
+		"newBucketDataSource": {"aws_s3_bucket"},
+	}, factoryIndex)
+}
+
+func TestTerraformProviderIndex_WriteIndexFiles_WritesFactoryIndex(t *testing.T) {
+	// Setup
+	index := createTestTerraformProviderIndex()
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+
+	// Execute
+	err := index.WriteIndexFiles(outputDir, nil)
+
+	// Verify
+	require.NoError(t, err)
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "factory-index.json"))
+	require.NoError(t, err)
+
+	var factoryIndex map[string][]string
+	require.NoError(t, json.Unmarshal(data, &factoryIndex))
+	assert.Equal(t, index.FactoryFunctionIndex(), factoryIndex)
+}
+
+func TestTerraformProviderIndex_WriteIndexFiles_WritesStatisticsFile(t *testing.T) {
+	// Setup
+	index := createTestTerraformProviderIndex()
+	index.Statistics = newProviderStatistics(index.Services)
+	index.Statistics.ScanDurationMs = 1234
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+
+	// Execute
+	err := index.WriteIndexFiles(outputDir, nil)
+
+	// Verify - the per-service breakdown and the scan duration are included
+	require.NoError(t, err)
+	data, err := afero.ReadFile(fs, filepath.Join(outputDir, "statistics.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"scan_duration_ms": 1234`)
+
+	var statistics ProviderStatistics
+	require.NoError(t, json.Unmarshal(data, &statistics))
+	assert.Equal(t, index.Statistics, statistics)
+	assert.Contains(t, statistics.PerService, "s3")
+
+	// The main index file carries the same statistics
+	loaded, err := LoadIndexFromDir(outputDir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234), loaded.Statistics.ScanDurationMs)
+}
+
+func TestScanSingleService(t *testing.T) {
+	// Setup - a temp module containing one synthetic service package
+	// gophon loads packages relative to the working directory
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module example.com/provider\n\ngo 1.24\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "widget"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "widget", "widget.go"), []byte(`package widget
+
+type resourceSchema struct {
+	CreateWithoutTimeout interface{}
+	ReadWithoutTimeout   interface{}
+}
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *resourceSchema {
+	return &resourceSchema{
+		CreateWithoutTimeout: resourceWidgetCreate,
+		ReadWithoutTimeout:   resourceWidgetRead,
+	}
+}
+
+func resourceWidgetCreate() {}
+
+func resourceWidgetRead() {}
+`), 0644))
+	t.Chdir(tempDir)
+
+	// Execute
+	serviceReg, err := ScanSingleService("widget", "example.com/provider")
+
+	// Verify
+	require.NoError(t, err)
+	require.NotNil(t, serviceReg)
+	assert.Equal(t, "widget", serviceReg.ServiceName)
+	assert.Equal(t, "example.com/provider/widget", serviceReg.PackagePath)
+	require.Contains(t, serviceReg.AWSSDKResources, "aws_widget")
+	assert.Equal(t, "widget/widget.go", serviceReg.AWSSDKResources["aws_widget"].RegistrationFile)
+	require.Contains(t, serviceReg.ResourceCRUDMethods, "aws_widget")
+	assert.Equal(t, "resourceWidgetCreate", serviceReg.ResourceCRUDMethods["aws_widget"].CreateMethod)
+	assert.Equal(t, "resourceWidgetRead", serviceReg.ResourceCRUDMethods["aws_widget"].ReadMethod)
+}
+
+func TestScanTerraformProviderServicesWithOptions_SymlinkedServices(t *testing.T) {
+	// Setup - one real service, one linked in from outside the services directory, and links that would
+	// duplicate a service or recurse into the tree
+	tempDir := t.TempDir()
+	servicesDir := filepath.Join(tempDir, "services")
+	writeService := func(dir, name string) {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".go"), []byte(fmt.Sprintf(`package %[1]s
+
+// @SDKResource("aws_%[1]s", name="%[1]s")
+func resource%[1]s() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resource%[1]sRead,
+	}
+}
+`, name)), 0644))
+	}
+	writeService(filepath.Join(servicesDir, "widget"), "widget")
+	writeService(filepath.Join(tempDir, "shared", "gadget"), "gadget")
+	for link, target := range map[string]string{
+		"gadget":       filepath.Join(tempDir, "shared", "gadget"),
+		"widget_alias": filepath.Join(servicesDir, "widget"),
+		"loop":         servicesDir,
+		"root":         tempDir,
+		"dangling":     filepath.Join(tempDir, "missing"),
+	} {
+		if err := os.Symlink(target, filepath.Join(servicesDir, link)); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+	logger := newCapturingLogger()
+
+	// Execute
+	index, err := ScanTerraformProviderServicesWithOptions(context.Background(), servicesDir, "example.com/provider", "v1.0.0", nil,
+		ScanOptions{InputFs: afero.NewOsFs(), Logger: logger})
+
+	// Verify - the linked service is scanned under the link's name, the other links are skipped
+	require.NoError(t, err)
+	var serviceNames []string
+	for _, service := range index.Services {
+		serviceNames = append(serviceNames, service.ServiceName)
+	}
+	assert.ElementsMatch(t, []string{"gadget", "widget"}, serviceNames)
+	for _, service := range index.Services {
+		assert.Contains(t, service.AWSSDKResources, "aws_"+service.ServiceName)
+	}
+	assert.Len(t, logger.messages["warn"], 3)
+}
+
+func TestScanSingleService_MissingDirectory(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&inputFs, fs)
+	defer stub.Reset()
+
+	serviceReg, err := ScanSingleService("/services/missing", "example.com/provider")
+
+	assert.Nil(t, serviceReg)
+	assert.Error(t, err)
+}
+
+func TestParseServiceSource(t *testing.T) {
+	serviceReg, err := ParseServiceSource([]byte(mixedResourceServiceSource), "github.com/hashicorp/terraform-provider-aws/internal/service/example")
+
+	require.NoError(t, err)
+	require.NotNil(t, serviceReg)
+	assert.Equal(t, "example", serviceReg.ServiceName)
+	assert.Equal(t, "example", serviceReg.PackageName)
+	assert.Equal(t, "github.com/hashicorp/terraform-provider-aws/internal/service/example", serviceReg.PackagePath)
+	assert.Len(t, serviceReg.AWSSDKResources, 1)
+	assert.Len(t, serviceReg.AWSSDKDataSources, 1)
+	assert.Len(t, serviceReg.AWSFrameworkResources, 1)
+	assert.Len(t, serviceReg.AWSFrameworkDataSources, 1)
+	assert.Len(t, serviceReg.AWSEphemeralResources, 1)
+	assert.True(t, serviceReg.MixedImplementation)
+}
+
+func TestParseServiceSource_SDKSchemaAttributes(t *testing.T) {
+	// Setup - the factory name differs from the one derived from the terraform type, resourceExampleWidget
+	serviceReg, err := ParseServiceSource([]byte(`package example
+
+// @SDKResource("aws_example_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+		Schema: map[string]*schema.Schema{
+			names.AttrName: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"priority": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+`), "github.com/hashicorp/terraform-provider-aws/internal/service/example")
+	require.NoError(t, err)
+	require.Contains(t, serviceReg.AWSSDKResources, "aws_example_widget")
+	widget := serviceReg.AWSSDKResources["aws_example_widget"]
+	assert.Equal(t, "resourceWidget", widget.FunctionName)
+
+	// Execute
+	resource := NewTerraformResourceFromAWSSDK(widget, *serviceReg)
+
+	// Verify
+	assert.Equal(t, []string{"name", "rule"}, resource.Attributes)
+	assert.Equal(t, map[string][]string{"rule": {"priority"}}, resource.NestedAttributes)
+}
+
+func TestParseServiceSource_SDKSchemaDefaultFuncs(t *testing.T) {
+	// Setup - the factory name differs from the one derived from the terraform type, resourceExampleWidget
+	serviceReg, err := ParseServiceSource([]byte(`package example
+
+// @SDKResource("aws_example_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			names.AttrRegion: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: regionDefault,
+			},
+		},
+	}
+}
+
+func regionDefault() (interface{}, error) {
+	return "us-east-1", nil
+}
+`), "github.com/hashicorp/terraform-provider-aws/internal/service/example")
+	require.NoError(t, err)
+
+	// Execute
+	resource := NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_example_widget"], *serviceReg)
+
+	// Verify
+	assert.Equal(t, map[string]string{"region": "func.regionDefault.goindex"}, resource.DefaultFuncIndexes)
+}
+
+func TestParseServiceSource_WriteOnlyAttributes(t *testing.T) {
+	// Setup - the factory name differs from the one derived from the terraform type, resourceExampleWidget
+	serviceReg, err := ParseServiceSource([]byte(`package example
+
+// @SDKResource("aws_example_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"password_wo": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				WriteOnly: true,
+			},
+		},
+	}
+}
+`), "github.com/hashicorp/terraform-provider-aws/internal/service/example")
+	require.NoError(t, err)
+
+	// Execute
+	resource := NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_example_widget"], *serviceReg)
+
+	// Verify
+	assert.True(t, resource.HasWriteOnlyAttributes)
+	assert.Equal(t, []string{"password_wo"}, resource.WriteOnlyAttributes)
+}
+
+func TestParseServiceSource_MissingFrameworkMethods(t *testing.T) {
+	// Setup - the resource has no Update method
+	serviceReg, err := ParseServiceSource([]byte(`package example
+
+// @FrameworkResource("aws_example_gadget", name="Gadget")
+func newGadgetResource(context.Context) (resource.ResourceWithConfigure, error) {
+	return &gadgetResource{}, nil
+}
+
+type gadgetResource struct {
+	framework.ResourceWithModel[gadgetResourceModel]
+}
+
+func (r *gadgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+
+func (r *gadgetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {}
+
+func (r *gadgetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {}
+
+func (r *gadgetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {}
+`), "github.com/hashicorp/terraform-provider-aws/internal/service/example")
+	require.NoError(t, err)
+
+	// Execute
+	resource := NewTerraformResourceFromAWSFramework(serviceReg.AWSFrameworkResources["aws_example_gadget"], *serviceReg, serviceReg.Package)
+
+	// Verify
+	assert.Equal(t, []string{"Create", "Read", "Delete", "Metadata", "Schema"}, resource.ImplementedFrameworkMethods)
+	assert.Equal(t, []string{"Update"}, resource.MissingFrameworkMethods)
+	assert.Equal(t, "method.gadgetResource.Create.goindex", resource.CreateIndex)
+	assert.Equal(t, "method.gadgetResource.Read.goindex", resource.ReadIndex)
+	assert.Empty(t, resource.UpdateIndex)
+	assert.Equal(t, "method.gadgetResource.Delete.goindex", resource.DeleteIndex)
+	data, err := json.Marshal(resource)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"missing_framework_methods":["Update"]`)
+	assert.NotContains(t, string(data), `"update_index"`)
+}
+
+func TestParseServiceSource_ParseError(t *testing.T) {
+	serviceReg, err := ParseServiceSource([]byte("package example\n\nfunc broken( {"), "example.com/provider/example")
+
+	assert.Nil(t, serviceReg)
+	assert.Error(t, err)
+}
+
+func TestParseServiceSource_WarningSourceLine(t *testing.T) {
+	// Setup - the factory on line 6 returns a struct without a Schema method
+	src := `package widget
+
+type widgetResource struct{}
+
+// @FrameworkResource("aws_widget", name="Widget")
+func newWidgetResource(context.Context) (resource.ResourceWithConfigure, error) {
+	return &widgetResource{}, nil
+}
+`
+
+	// Execute
+	serviceReg, err := ParseServiceSource([]byte(src), "example.com/provider/widget")
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, serviceReg.Warnings, 1)
+	assert.Contains(t, serviceReg.Warnings[0], "widget.go:6")
+}
+
+func TestParseServiceSource_FactoryReturnTypeMismatch(t *testing.T) {
+	// Setup - aws_widget is consistent, aws_gadget is registered as SDK but returns a framework resource
+	src := `package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+
+// @SDKResource("aws_gadget", name="Gadget")
+func newGadgetResource(context.Context) (resource.ResourceWithConfigure, error) {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceGadgetRead,
+	}, nil
+}
+
+// @FrameworkDataSource("aws_gizmo", name="Gizmo")
+func newGizmoDataSource(context.Context) (datasource.DataSourceWithConfigure, error) {
+	return &gizmoDataSource{}, nil
+}
+
+type gizmoDataSource struct{}
+
+func (d *gizmoDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+}
+`
+
+	// Execute
+	serviceReg, err := ParseServiceSource([]byte(src), "example.com/provider/widget")
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"mismatched @SDKResource aws_gadget in widget (widget.go:11): factory newGadgetResource returns resource.ResourceWithConfigure",
+	}, serviceReg.Warnings)
+}
+
+func TestParseServiceSource_EmittedFactoryFunction(t *testing.T) {
+	// Setup - aws_widget is registered by the name derived from the terraform type, resourceWidget,
+	// aws_widget_rule and aws_widget_config are not
+	serviceReg, err := ParseServiceSource([]byte(`package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+
+// @SDKResource("aws_widget_rule", name="Rule")
+func resourceRule() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceRuleRead,
+	}
+}
+
+// @SDKDataSource("aws_widget_config", name="Config")
+func dataSourceConfig() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceConfigRead,
+	}
+}
+
+// @FrameworkResource("aws_widget_gadget", name="Gadget")
+type gadgetResource struct{}
+
+func (r *gadgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+`), "example.com/provider/widget")
+
+	// Verify - every annotated function is emitted and indexed as is, only the name derived for the annotated
+	// type declaration dangles
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"dangling factory function newWidgetGadgetResource of resource aws_widget_gadget in widget: not declared in the package",
+	}, serviceReg.Warnings)
+	rule := serviceReg.AWSSDKResources["aws_widget_rule"]
+	assert.Equal(t, "resourceRule", rule.FactoryFunction)
+	ruleEntry := NewTerraformResourceFromAWSSDK(rule, *serviceReg)
+	assert.Equal(t, "func.resourceRule.goindex", ruleEntry.SchemaIndex)
+	assert.Equal(t, "func.resourceRule.goindex", ruleEntry.AttributeIndex)
+	config := serviceReg.AWSSDKDataSources["aws_widget_config"]
+	assert.Equal(t, "dataSourceConfig", config.FactoryFunction)
+	configEntry := NewTerraformDataSourceFromAWSSDK(config, *serviceReg)
+	assert.Equal(t, "func.dataSourceConfig.goindex", configEntry.SchemaIndex)
+	assert.Equal(t, "func.dataSourceConfig.goindex", configEntry.AttributeIndex)
+}
+
+func TestParseServiceSource_SharedGenericFactory(t *testing.T) {
+	serviceReg, err := ParseServiceSource([]byte(`package example
+
+// @SDKResource("aws_example_alpha", name="Alpha")
+func resourceAlpha() *schema.Resource {
+	return resourceGeneric("alpha")
+}
+
+// @SDKResource("aws_example_beta", name="Beta")
+func resourceBeta() *schema.Resource {
+	return resourceGeneric("beta")
+}
+
+func resourceGeneric(typeName string) *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceGenericCreate,
+		ReadWithoutTimeout:   resourceGenericRead,
+		DeleteWithoutTimeout: resourceGenericDelete,
+	}
+}
+
+// @SDKResource("aws_example_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceWidgetCreate,
+		ReadWithoutTimeout:   resourceWidgetRead,
+		UpdateWithoutTimeout: resourceWidgetUpdate,
+		DeleteWithoutTimeout: resourceWidgetDelete,
+	}
+}
+`), "github.com/hashicorp/terraform-provider-aws/internal/service/example")
+	require.NoError(t, err)
+
+	// Both terraform types get the shared factory's methods, the unrelated resource keeps its own
+	generic := &LegacyResourceCRUDFunctions{
+		CreateMethod: "resourceGenericCreate",
+		ReadMethod:   "resourceGenericRead",
+		DeleteMethod: "resourceGenericDelete",
+	}
+	assert.Equal(t, generic, serviceReg.ResourceCRUDMethods["aws_example_alpha"])
+	assert.Equal(t, generic, serviceReg.ResourceCRUDMethods["aws_example_beta"])
+	assert.Equal(t, &LegacyResourceCRUDFunctions{
+		CreateMethod: "resourceWidgetCreate",
+		ReadMethod:   "resourceWidgetRead",
+		UpdateMethod: "resourceWidgetUpdate",
+		DeleteMethod: "resourceWidgetDelete",
+	}, serviceReg.ResourceCRUDMethods["aws_example_widget"])
+	assert.Empty(t, serviceReg.Warnings)
+}
+
+func TestNewTerraformEphemeralFromAWS_RenewSupport(t *testing.T) {
+	const source = `package example
+
+// @EphemeralResource("aws_example_credentials", name="Credentials")
+func newCredentialsEphemeralResource(_ context.Context) (ephemeral.EphemeralResourceWithConfigure, error) {
+	return &credentialsEphemeralResource{}, nil
+}
+
+type credentialsEphemeralResource struct {
+	framework.EphemeralResourceWithModel[credentialsEphemeralResourceModel]
+}
+
+func (e *credentialsEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {}
+
+func (e *credentialsEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {}
+`
+	tests := []struct {
+		name                  string
+		source                string
+		expectedRenewIndex    string
+		expectedSupportsRenew bool
+	}{
+		{
+			name:   "Renew omitted",
+			source: source,
+		},
+		{
+			name:                  "Renew declared",
+			source:                source + "\nfunc (e *credentialsEphemeralResource) Renew(ctx context.Context, req ephemeral.RenewRequest, resp *ephemeral.RenewResponse) {}\n",
+			expectedRenewIndex:    "method.credentialsEphemeralResource.Renew.goindex",
+			expectedSupportsRenew: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serviceReg, err := ParseServiceSource([]byte(tt.source), "github.com/hashicorp/terraform-provider-aws/internal/service/example")
+			require.NoError(t, err)
+			require.Contains(t, serviceReg.AWSEphemeralResources, "aws_example_credentials")
+
+			ephemeral := NewTerraformEphemeralFromAWS(serviceReg.AWSEphemeralResources["aws_example_credentials"], *serviceReg)
+
+			assert.Equal(t, tt.expectedRenewIndex, ephemeral.RenewIndex)
+			assert.Equal(t, tt.expectedSupportsRenew, ephemeral.SupportsRenew)
+			assert.Equal(t, "method.credentialsEphemeralResource.Open.goindex", ephemeral.OpenIndex)
+		})
+	}
+}
+
+func TestTerraformProviderIndex_WriteEphemeralFiles_WithoutRenew(t *testing.T) {
+	// Setup - the scanned ephemeral resource is also in the legacy mapping and has no Renew method
+	serviceReg, err := ParseServiceSource([]byte(`package example
+
+// @EphemeralResource("aws_example_credentials", name="Credentials")
+func newCredentialsEphemeralResource(_ context.Context) (ephemeral.EphemeralResourceWithConfigure, error) {
+	return &credentialsEphemeralResource{}, nil
+}
+
+type credentialsEphemeralResource struct {
+	framework.EphemeralResourceWithModel[credentialsEphemeralResourceModel]
+}
+
+func (e *credentialsEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {}
+
+func (e *credentialsEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {}
+`), "github.com/hashicorp/terraform-provider-aws/internal/service/example")
+	require.NoError(t, err)
+	require.Equal(t, "aws_example_credentials", serviceReg.EphemeralTerraformTypes["credentialsEphemeralResource"])
+	index := &TerraformProviderIndex{Version: "v1.0.0", Services: []ServiceRegistration{*serviceReg}}
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+
+	// Execute
+	require.NoError(t, index.WriteEphemeralFiles("/output", nil))
+
+	// Verify - the written file has no Renew index, whichever write finished last
+	data, err := afero.ReadFile(fs, "/output/ephemeral/aws_example_credentials.json")
+	require.NoError(t, err)
+	var written TerraformEphemeral
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Empty(t, written.RenewIndex)
+	assert.False(t, written.SupportsRenew)
+	assert.Equal(t, "method.credentialsEphemeralResource.Open.goindex", written.OpenIndex)
+	assert.Equal(t, "Credentials", written.DisplayName)
+}
+
+func TestNewTerraformEphemeralFromAWS_Region(t *testing.T) {
+	serviceReg, err := ParseServiceSource([]byte(`package example
+
+// @EphemeralResource("aws_example_credentials", name="Credentials")
+// @Region(overrideEnabled=false)
+func newCredentialsEphemeralResource(_ context.Context) (ephemeral.EphemeralResourceWithConfigure, error) {
+	return &credentialsEphemeralResource{}, nil
+}
+
+// @EphemeralResource("aws_example_token", name="Token")
+func newTokenEphemeralResource(_ context.Context) (ephemeral.EphemeralResourceWithConfigure, error) {
+	return &tokenEphemeralResource{}, nil
+}
+
+type credentialsEphemeralResource struct {
+	framework.EphemeralResourceWithModel[credentialsEphemeralResourceModel]
+}
+
+func (e *credentialsEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {}
+`), "github.com/hashicorp/terraform-provider-aws/internal/service/example")
+	require.NoError(t, err)
+	require.Contains(t, serviceReg.AWSEphemeralResources, "aws_example_credentials")
+
+	credentials := serviceReg.AWSEphemeralResources["aws_example_credentials"]
+	assert.Equal(t, &AWSRegionConfig{OverrideEnabled: false}, credentials.Region)
+	assert.Equal(t, credentials.Region, NewTerraformEphemeralFromAWS(credentials, *serviceReg).Region)
+	assert.Nil(t, serviceReg.AWSEphemeralResources["aws_example_token"].Region)
+}
+
+func TestParseRegionAnnotation(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected *AWSRegionConfig
+	}{
+		{name: "absent", text: `@SDKResource("aws_widget", name="Widget")`},
+		{name: "global", text: "@SDKResource(\"aws_widget\")\n@Region(global=true)", expected: &AWSRegionConfig{Global: true, OverrideEnabled: true}},
+		{name: "override disabled", text: "@Region(overrideEnabled=false)", expected: &AWSRegionConfig{}},
+		{name: "no arguments", text: "@Region", expected: &AWSRegionConfig{OverrideEnabled: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseRegionAnnotation(tt.text))
+		})
+	}
+}
+
+func TestScanTerraformProviderServicesWithOptions_Filters(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	for _, service := range []string{"ec2", "ec2ebs", "s3", "lambda"} {
+		require.NoError(t, fs.MkdirAll("/services/"+service, 0755))
+	}
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	newPackage := func(service string) *gophon.PackageInfo {
+		return createMockPackageInfoFromSource(t, "/services/"+service+"/main.go", "example.com/provider/"+service, fmt.Sprintf(`package %[1]s
+
+// @SDKResource("aws_%[1]s_alpha", name="Alpha")
+func resourceAlpha() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @SDKResource("aws_%[1]s_beta", name="Beta")
+func resourceBeta() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @SDKDataSource("aws_%[1]s_alpha", name="Alpha")
+func dataSourceAlpha() *schema.Resource {
+	return &schema.Resource{}
+}
+`, service))
+	}
+
+	testCases := []struct {
+		name             string
+		options          ScanOptions
+		expectedScanned  []string
+		expectedServices map[string][]string // service -> sorted SDK resource types
+		expectedDS       int
+	}{
+		{
+			name:            "Service include pattern limits scanned services",
+			options:         ScanOptions{IncludeServices: []string{"ec2*"}},
+			expectedScanned: []string{"ec2", "ec2ebs"},
+			expectedServices: map[string][]string{
+				"ec2":    {"aws_ec2_alpha", "aws_ec2_beta"},
+				"ec2ebs": {"aws_ec2ebs_alpha", "aws_ec2ebs_beta"},
+			},
+			expectedDS: 2,
+		},
+		{
+			name:            "Service exclude wins over include",
+			options:         ScanOptions{IncludeServices: []string{"ec2*"}, ExcludeServices: []string{"ec2ebs"}},
+			expectedScanned: []string{"ec2"},
+			expectedServices: map[string][]string{
+				"ec2": {"aws_ec2_alpha", "aws_ec2_beta"},
+			},
+			expectedDS: 1,
+		},
+		{
+			name:            "Type filters drop individual resources",
+			options:         ScanOptions{IncludeServices: []string{"s3"}, ExcludeTypes: []string{"aws_*_beta"}},
+			expectedScanned: []string{"s3"},
+			expectedServices: map[string][]string{
+				"s3": {"aws_s3_alpha"},
+			},
+			expectedDS: 1,
+		},
+		{
+			name:            "Type include and exclude",
+			options:         ScanOptions{IncludeServices: []string{"lambda"}, IncludeTypes: []string{"aws_lambda_*"}, ExcludeTypes: []string{"aws_lambda_alpha"}},
+			expectedScanned: []string{"lambda"},
+			expectedServices: map[string][]string{
+				"lambda": {"aws_lambda_beta"},
+			},
+			expectedDS: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var scanned []string
+			scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+				service := filepath.Base(pkgPath)
+				mu.Lock()
+				scanned = append(scanned, service)
+				mu.Unlock()
+				return newPackage(service), nil
+			})
+			defer scanStub.Reset()
+
+			index, err := ScanTerraformProviderServicesWithOptions(context.Background(), "/services", "example.com/provider", "v1.0.0", nil, tc.options)
+			require.NoError(t, err)
+
+			sort.Strings(scanned)
+			assert.Equal(t, tc.expectedScanned, scanned)
+
+			actualServices := make(map[string][]string)
+			for _, service := range index.Services {
+				var types []string
+				for terraformType := range service.AWSSDKResources {
+					types = append(types, terraformType)
+				}
+				sort.Strings(types)
+				actualServices[service.ServiceName] = types
+			}
+			assert.Equal(t, tc.expectedServices, actualServices)
+			assert.Equal(t, tc.expectedDS, index.Statistics.TotalDataSources)
+		})
+	}
+}
+
+func TestScanTerraformProviderServicesWithOptions_FailOnEmpty(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []string
+	}{
+		{name: "empty directory"},
+		{name: "services without registrations", services: []string{"widget"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll("/services", 0755))
+			for _, service := range tt.services {
+				require.NoError(t, fs.MkdirAll("/services/"+service, 0755))
+			}
+			inputStub := gostub.Stub(&inputFs, fs)
+			defer inputStub.Reset()
+			scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+				return createMockPackageInfoFromSource(t, pkgPath+"/widget.go", "example.com/provider/widget", "package widget\n"), nil
+			})
+			defer scanStub.Reset()
+
+			// Execute
+			index, err := ScanTerraformProviderServicesWithOptions(context.Background(), "/services", "example.com/provider", "v1.0.0", nil, ScanOptions{FailOnEmpty: true})
+
+			// Verify - the default keeps returning an empty index
+			assert.Nil(t, index)
+			assert.ErrorContains(t, err, "no services with AWS registrations found in /services")
+
+			index, err = ScanTerraformProviderServicesWithOptions(context.Background(), "/services", "example.com/provider", "v1.0.0", nil, ScanOptions{})
+			require.NoError(t, err)
+			assert.Empty(t, index.Services)
+		})
+	}
+}
+
+func TestScanTerraformProviderServicesWithOptions_OnlyTaggable(t *testing.T) {
+	// Setup - one taggable and one untagged entry per category, plus an ephemeral resource
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/services/widget", 0755))
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	packageInfo := createMockPackageInfoFromSource(t, "/services/widget/widget.go", "example.com/provider/widget", `package widget
+
+// @SDKResource("aws_widget", name="Widget")
+// @Tags(identifierAttribute="arn")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+		Schema: map[string]*schema.Schema{
+			names.AttrTags: tftags.TagsSchema(),
+		},
+	}
+}
+
+// @SDKResource("aws_widget_attachment", name="Widget Attachment")
+func resourceWidgetAttachment() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			names.AttrName: {Type: schema.TypeString, Required: true},
+		},
+	}
+}
+
+// @SDKDataSource("aws_widget", name="Widget")
+func dataSourceWidget() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			names.AttrTags: tftags.TagsSchemaComputed(),
+		},
+	}
+}
+
+// @SDKDataSource("aws_widget_attachment", name="Widget Attachment")
+func dataSourceWidgetAttachment() *schema.Resource {
+	return &schema.Resource{}
+}
+`)
+	ephemeralFile := createMockPackageInfoFromSource(t, "/services/widget/token.go", "example.com/provider/widget", `package widget
+
+// @EphemeralResource("aws_widget_token", name="Widget Token")
+func newTokenEphemeralResource(_ context.Context) (ephemeral.EphemeralResourceWithConfigure, error) {
+	return &tokenEphemeralResource{}, nil
+}
+
+func (r *tokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {}
+`)
+	packageInfo.Files = append(packageInfo.Files, ephemeralFile.Files...)
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		return packageInfo, nil
+	})
+	defer scanStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServicesWithOptions(context.Background(), "/services", "example.com/provider", "v1.0.0", nil, ScanOptions{OnlyTaggable: true})
+
+	// Verify - only the taggable resource and data source survive, and statistics count only them
+	require.NoError(t, err)
+	require.Len(t, index.Services, 1)
+	service := index.Services[0]
+	assert.Len(t, service.AWSSDKResources, 1)
+	assert.Contains(t, service.AWSSDKResources, "aws_widget")
+	assert.Len(t, service.AWSSDKDataSources, 1)
+	assert.Contains(t, service.AWSSDKDataSources, "aws_widget")
+	assert.Empty(t, service.AWSEphemeralResources)
+	assert.Equal(t, 1, index.Statistics.TotalResources)
+	assert.Equal(t, 1, index.Statistics.TotalDataSources)
+	assert.Equal(t, 0, index.Statistics.EphemeralResources)
+}
+
+func TestScanTerraformProviderServicesWithOptions_InvalidPattern(t *testing.T) {
+	index, err := ScanTerraformProviderServicesWithOptions(context.Background(), "/services", "example.com/provider", "v1.0.0", nil,
+		ScanOptions{IncludeServices: []string{"ec2["}})
+
+	assert.Nil(t, index)
+	assert.ErrorContains(t, err, "invalid filter pattern")
+}
+
+func TestScanTerraformProviderServicesWithOptions_Categories(t *testing.T) {
+	// Setup - one service registering a resource, a data source and an ephemeral resource
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/services/widget/widget.go", []byte(`package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+
+// @SDKDataSource("aws_widget", name="Widget")
+func dataSourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceWidgetRead,
+	}
+}
+`), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/services/widget/token.go", []byte(`package widget
+
+// @EphemeralResource("aws_widget_token", name="Widget Token")
+func newTokenEphemeralResource(_ context.Context) (ephemeral.EphemeralResourceWithConfigure, error) {
+	return &tokenEphemeralResource{}, nil
+}
+
+func (r *tokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {}
+`), 0644))
+	outFs := afero.NewMemMapFs()
+	outputStub := gostub.Stub(&outputFs, outFs)
+	defer outputStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServicesWithOptions(context.Background(), "/services", "example.com/provider", "v1.0.0", nil,
+		ScanOptions{InputFs: fs, Categories: []string{WalkCategoryEphemeral}})
+	require.NoError(t, err)
+	require.NoError(t, index.WriteIndexFiles("/test/output", nil))
+
+	// Verify - only the ephemeral resource is extracted and only its category directory is written
+	require.Len(t, index.Services, 1)
+	assert.Empty(t, index.Services[0].AWSSDKResources)
+	assert.Empty(t, index.Services[0].AWSSDKDataSources)
+	assert.Contains(t, index.Services[0].AWSEphemeralResources, "aws_widget_token")
+
+	var entryFiles []string
+	for _, dir := range []string{"resources", "datasources", "ephemeral"} {
+		root := filepath.Join("/test/output", dir)
+		exists, err := afero.DirExists(outFs, root)
+		require.NoError(t, err)
+		if !exists {
+			continue
+		}
+		require.NoError(t, afero.Walk(outFs, root, func(filePath string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				entryFiles = append(entryFiles, filepath.ToSlash(strings.TrimPrefix(filePath, "/test/output/")))
+			}
+			return err
+		}))
+	}
+	assert.Equal(t, []string{"ephemeral/aws_widget_token.json"}, entryFiles)
+	for _, dir := range []string{"resources", "datasources"} {
+		exists, err := afero.DirExists(outFs, filepath.Join("/test/output", dir))
+		require.NoError(t, err)
+		assert.False(t, exists, dir)
+	}
+}
+
+func TestScanTerraformProviderServicesWithOptions_InvalidCategory(t *testing.T) {
+	index, err := ScanTerraformProviderServicesWithOptions(context.Background(), "/services", "example.com/provider", "v1.0.0", nil,
+		ScanOptions{Categories: []string{"resources"}})
+
+	assert.Nil(t, index)
+	assert.ErrorContains(t, err, "invalid category")
+}
+
+// capturingLogger records formatted messages per level for assertions
+type capturingLogger struct {
+	mu       sync.Mutex
+	messages map[string][]string
+}
+
+func newCapturingLogger() *capturingLogger {
+	return &capturingLogger{messages: make(map[string][]string)}
+}
+
+func (l *capturingLogger) log(level, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages[level] = append(l.messages[level], fmt.Sprintf(format, args...))
+}
+
+func (l *capturingLogger) Debugf(format string, args ...interface{}) { l.log("debug", format, args...) }
+func (l *capturingLogger) Infof(format string, args ...interface{})  { l.log("info", format, args...) }
+func (l *capturingLogger) Warnf(format string, args ...interface{})  { l.log("warn", format, args...) }
+func (l *capturingLogger) Errorf(format string, args ...interface{}) { l.log("error", format, args...) }
+
+func TestScanTerraformProviderServicesWithOptions_LogsUnparsableFile(t *testing.T) {
+	// Setup - one service with a valid file and a file without an AST, one service failing to scan
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/services/widget", 0755))
+	require.NoError(t, fs.MkdirAll("/services/broken", 0755))
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	widgetPackage := createMockPackageInfoFromSource(t, "/services/widget/widget.go", "example.com/provider/widget", `package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{}
+}
+`)
+	widgetPackage.Files = append(widgetPackage.Files, &gophon.FileInfo{FileName: "/services/widget/garbled.go"})
+
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		if filepath.Base(pkgPath) == "broken" {
+			return nil, fmt.Errorf("syntax error")
+		}
+		return widgetPackage, nil
+	})
+	defer scanStub.Reset()
+
+	logger := newCapturingLogger()
+
+	// Execute
+	index, err := ScanTerraformProviderServicesWithOptions(context.Background(), "/services", "example.com/provider", "v1.0.0", nil, ScanOptions{Logger: logger})
+
+	// Verify - the valid file is still indexed and both problems are logged
+	require.NoError(t, err)
+	require.Len(t, index.Services, 1)
+	assert.Contains(t, index.Services[0].AWSSDKResources, "aws_widget")
+
+	require.Len(t, logger.messages["warn"], 1)
+	assert.Contains(t, logger.messages["warn"][0], "/services/widget/garbled.go")
+	require.Len(t, logger.messages["error"], 1)
+	assert.Contains(t, logger.messages["error"][0], "failed to scan service broken")
+	assert.Equal(t, []string{"scanning 2 services in /services"}, logger.messages["info"])
+}
+
+func TestNewTerraformResourceFromAWSSDK_SchemaVersion(t *testing.T) {
+	// Setup
+	packageInfo := createMockPackageInfoFromSource(t, "/services/widget/widget.go", "example.com/provider/widget", `package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceWidgetV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceWidgetStateUpgradeV1,
+				Version: 1,
+			},
+		},
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+`)
+	serviceReg := CreateTestServiceRegistration("widget")
+	require.NoError(t, parseAWSServiceFileWithAnnotations(packageInfo, &serviceReg))
+
+	// Execute
+	resource := NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_widget"], serviceReg)
+
+	// Verify
+	assert.Equal(t, 2, resource.SchemaVersion)
+	assert.Equal(t, []string{"resourceWidgetStateUpgradeV1"}, resource.StateUpgraders)
+}
+
+func TestNewTerraformResourceFromAWSSDK_Importer(t *testing.T) {
+	// Setup - one custom importer and one passthrough importer
+	packageInfo := createMockPackageInfoFromSource(t, "/services/widget/widget.go", "example.com/provider/widget", `package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceWidgetImport,
+		},
+	}
+}
+`)
+	passthroughInfo := createMockPackageInfoFromSource(t, "/services/widget/gadget.go", "example.com/provider/widget", `package widget
+
+// @SDKResource("aws_gadget", name="Gadget")
+func resourceGadget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceGadgetRead,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+`)
+	serviceReg := CreateTestServiceRegistration("widget")
+	require.NoError(t, parseAWSServiceFileWithAnnotations(packageInfo, &serviceReg))
+	require.NoError(t, parseAWSServiceFileWithAnnotations(passthroughInfo, &serviceReg))
+
+	// Execute
+	custom := NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_widget"], serviceReg)
+	passthrough := NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_gadget"], serviceReg)
+
+	// Verify
+	assert.Equal(t, &AWSImportConfig{ImportStateFunc: "resourceWidgetImport"}, serviceReg.AWSSDKResources["aws_widget"].Import)
+	assert.True(t, custom.ImportSupported)
+	assert.Equal(t, "func.resourceWidgetImport.goindex", custom.ImportIndex)
+
+	assert.Equal(t, &AWSImportConfig{Passthrough: true}, serviceReg.AWSSDKResources["aws_gadget"].Import)
+	assert.True(t, passthrough.ImportSupported)
+	assert.Empty(t, passthrough.ImportIndex)
+}
+
+func TestNewTerraformResource_Singleton(t *testing.T) {
+	// Setup - singleton identities with and without a Delete, plus a framework noop delete helper
+	sources := map[string]string{
+		"/services/widget/settings.go": `package widget
+
+// @SDKResource("aws_widget_settings", name="Widget Settings")
+// @SingletonIdentity
+func resourceWidgetSettings() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceWidgetSettingsPut,
+		ReadWithoutTimeout:   resourceWidgetSettingsRead,
+		DeleteWithoutTimeout: schema.NoopContext,
+	}
+}
+`,
+		"/services/widget/policy.go": `package widget
+
+// @SDKResource("aws_widget_policy", name="Widget Policy")
+// @SingletonIdentity
+func resourceWidgetPolicy() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceWidgetPolicyPut,
+		ReadWithoutTimeout:   resourceWidgetPolicyRead,
+		DeleteWithoutTimeout: resourceWidgetPolicyDelete,
+	}
+}
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+`,
+		"/services/widget/defaults.go": `package widget
+
+// @FrameworkResource("aws_widget_defaults", name="Widget Defaults")
+// @SingletonIdentity
+func newWidgetDefaultsResource(_ context.Context) (resource.ResourceWithConfigure, error) {
+	return &widgetDefaultsResource{}, nil
+}
+
+type widgetDefaultsResource struct {
+	framework.ResourceWithModel[widgetDefaultsResourceModel]
+	framework.WithNoOpDelete
+}
+
+func (r *widgetDefaultsResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+}
+
+func (r *widgetDefaultsResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+}
+`,
+	}
+	serviceReg := CreateTestServiceRegistration("widget")
+	packages := make(map[string]*gophon.PackageInfo)
+	for path, src := range sources {
+		packages[path] = createMockPackageInfoFromSource(t, path, "example.com/provider/widget", src)
+		require.NoError(t, parseAWSServiceFileWithAnnotations(packages[path], &serviceReg))
+	}
+
+	// Execute
+	settings := NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_widget_settings"], serviceReg)
+	policy := NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_widget_policy"], serviceReg)
+	widget := NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_widget"], serviceReg)
+	defaults := NewTerraformResourceFromAWSFramework(serviceReg.AWSFrameworkResources["aws_widget_defaults"], serviceReg, packages["/services/widget/defaults.go"])
+
+	// Verify
+	assert.True(t, settings.Singleton, "noop delete should not count as a Delete method")
+	assert.False(t, policy.Singleton, "a real Delete method rules out a singleton")
+	assert.False(t, widget.Singleton, "no singleton identity")
+	assert.True(t, defaults.Singleton, "embedded WithNoOpDelete should not count as a Delete method")
+}
+
+func TestNewTerraformDataSource_SupportsFilters(t *testing.T) {
+	// Setup - SDK and framework data sources, each with and without a filter block
+	packageInfo := createMockPackageInfoFromSource(t, "/services/widget/widget.go", "example.com/provider/widget", `package widget
+
+// @SDKDataSource("aws_widgets", name="Widgets")
+func dataSourceWidgets() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceWidgetsRead,
+		Schema: map[string]*schema.Schema{
+			names.AttrFilter: customFiltersSchema(),
+			"ids":            {Type: schema.TypeList, Computed: true},
+		},
+	}
+}
+
+// @SDKDataSource("aws_widget", name="Widget")
+func dataSourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceWidgetRead,
+		Schema: map[string]*schema.Schema{
+			names.AttrName: {Type: schema.TypeString, Required: true},
+		},
+	}
+}
+
+// @SDKDataSource("aws_widget_versions", name="Widget Versions")
+func dataSourceVersions() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceVersionsRead,
+		Schema: map[string]*schema.Schema{
+			names.AttrFilter: customFiltersSchema(),
+		},
+	}
+}
+
+// @FrameworkDataSource("aws_gadgets", name="Gadgets")
+func newGadgetsDataSource(context.Context) (datasource.DataSourceWithConfigure, error) {
+	return &gadgetsDataSource{}, nil
+}
+
+type gadgetsDataSource struct{}
+
+func (d *gadgetsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"ids": schema.ListAttribute{Computed: true},
+		},
+		Blocks: map[string]schema.Block{
+			names.AttrFilter: tfec2.CustomFiltersBlock(ctx),
+		},
+	}
+}
+`)
+	serviceReg := CreateTestServiceRegistration("widget")
+	serviceReg.Package = packageInfo
+	require.NoError(t, parseAWSServiceFileWithAnnotations(packageInfo, &serviceReg))
+
+	// Execute
+	widgets := NewTerraformDataSourceFromAWSSDK(serviceReg.AWSSDKDataSources["aws_widgets"], serviceReg)
+	widget := NewTerraformDataSourceFromAWSSDK(serviceReg.AWSSDKDataSources["aws_widget"], serviceReg)
+	versions := NewTerraformDataSourceFromAWSSDK(serviceReg.AWSSDKDataSources["aws_widget_versions"], serviceReg)
+	gadgets := NewTerraformDataSourceFromAWSFramework(serviceReg.AWSFrameworkDataSources["aws_gadgets"], serviceReg)
+	gizmos := NewTerraformDataSourceFromAWSFramework(AWSResource{TerraformType: "aws_gizmos", StructType: "gizmosDataSource"}, serviceReg)
+
+	// Verify
+	assert.True(t, widgets.SupportsFilters)
+	assert.False(t, widget.SupportsFilters)
+	assert.True(t, versions.SupportsFilters, "factory named differently from dataSourceWidgetVersions")
+	assert.True(t, gadgets.SupportsFilters)
+	assert.False(t, gizmos.SupportsFilters, "no Schema method in the package")
+}
+
+func TestNewTerraformEntries_Category(t *testing.T) {
+	// Setup
+	service := CreateTestServiceRegistration("widget")
+	service.EphemeralTerraformTypes["widgetEphemeral"] = "aws_widget"
+	awsResource := AWSResource{TerraformType: "aws_widget", FactoryFunction: "resourceWidget", StructType: "widgetResource"}
+
+	// Execute & Verify - every conversion matches the directory its entries are written to
+	assert.Equal(t, WalkCategoryResource, NewTerraformResourceFromAWSSDK(awsResource, service).Category)
+	assert.Equal(t, WalkCategoryResource, NewTerraformResourceFromAWSFramework(awsResource, service, nil).Category)
+	assert.Equal(t, WalkCategoryDataSource, NewTerraformDataSourceFromAWSSDK(awsResource, service).Category)
+	assert.Equal(t, WalkCategoryDataSource, NewTerraformDataSourceFromAWSFramework(awsResource, service).Category)
+	assert.Equal(t, WalkCategoryDataSource, NewTerraformDataSourceInfo("aws_widget", "", "dataSourceWidget", "legacy_pluginsdk", service).Category)
+	assert.Equal(t, WalkCategoryDataSource, NewTerraformDataSourceInfo("aws_widget", "widgetDataSource", "DataSources", "framework", service).Category)
+	assert.Equal(t, WalkCategoryEphemeral, NewTerraformEphemeralFromAWS(awsResource, service).Category)
+	assert.Equal(t, WalkCategoryEphemeral, NewTerraformEphemeralInfo("widgetEphemeral", service).Category)
+}
+
+func TestNewTerraformEntries_DisplayName(t *testing.T) {
+	// Setup
+	serviceReg, err := ParseServiceSource([]byte(`package s3
+
+// @SDKResource("aws_s3_bucket", name="Bucket")
+func resourceBucket() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @SDKResource("aws_s3_bucket_policy")
+func resourceBucketPolicy() *schema.Resource {
+	return &schema.Resource{}
+}
+`), "example.com/provider/s3")
+	require.NoError(t, err)
+
+	// Execute
+	named := NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_s3_bucket"], *serviceReg)
+	unnamed := NewTerraformResourceFromAWSSDK(serviceReg.AWSSDKResources["aws_s3_bucket_policy"], *serviceReg)
+
+	// Verify - a declared name is kept, a missing one is derived without touching the raw name
+	assert.Equal(t, "Bucket", named.DisplayName)
+	assert.Equal(t, "S3 Bucket Policy", unnamed.DisplayName)
+	assert.Empty(t, serviceReg.AWSSDKResources["aws_s3_bucket_policy"].Name)
+	assert.Equal(t, "S3 Bucket Policy", NewTerraformEphemeralFromAWS(AWSResource{TerraformType: "aws_s3_bucket_policy"}, *serviceReg).DisplayName)
+}
+
+func TestTerraformProviderIndex_WriteIndexFiles_RawAnnotation(t *testing.T) {
+	// Setup
+	packageInfo := createMockPackageInfoFromSource(t, "/services/widget/widget.go", "example.com/provider/widget", `package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: resourceWidgetRead,
+	}
+}
+
+// @SDKDataSource("aws_widget", name="Widget")
+func dataSourceWidget() *schema.Resource {
+	return &schema.Resource{}
+}
+`)
+	serviceReg := CreateTestServiceRegistration("widget")
+	require.NoError(t, parseAWSServiceFileWithAnnotations(packageInfo, &serviceReg))
+	sut := &TerraformProviderIndex{Version: "v1.0.0", Services: []ServiceRegistration{serviceReg}}
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+
+	// Execute
+	err := sut.WriteIndexFiles("/out", nil)
+
+	// Verify - the annotation text is carried into the emitted entry files
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, "/out/resources/aws_widget.json")
+	require.NoError(t, err)
+	var resource TerraformResource
+	require.NoError(t, json.Unmarshal(data, &resource))
+	assert.Equal(t, `@SDKResource("aws_widget", name="Widget")`, resource.RawAnnotation)
+
+	data, err = afero.ReadFile(fs, "/out/datasources/aws_widget.json")
+	require.NoError(t, err)
+	var dataSource TerraformDataSource
+	require.NoError(t, json.Unmarshal(data, &dataSource))
+	assert.Equal(t, `@SDKDataSource("aws_widget", name="Widget")`, dataSource.RawAnnotation)
+
+	// Entries without an annotation omit the field
+	data, err = json.Marshal(NewTerraformResourceFromAWSSDK(AWSResource{TerraformType: "aws_other"}, serviceReg))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "raw_annotation")
+}
+
+func TestTerraformProviderIndex_MarshalJSON_Reproducible(t *testing.T) {
+	// Setup - the same services added in opposite orders, with several entries per resource map
+	newIndex := func(names ...string) *TerraformProviderIndex {
+		index := &TerraformProviderIndex{Version: "v1.0.0"}
+		for _, name := range names {
+			service := CreateTestServiceRegistration(name)
+			for _, suffix := range []string{"zeta", "alpha", "mid"} {
+				terraformType := fmt.Sprintf("aws_%s_%s", name, suffix)
+				service.AWSSDKResources[terraformType] = AWSResource{TerraformType: terraformType, SDKType: "sdk"}
+			}
+			index.Services = append(index.Services, service)
+		}
+		index.Statistics = newProviderStatistics(index.Services)
+		return index
+	}
+	forward := newIndex("ec2", "lambda", "s3")
+	reversed := newIndex("s3", "lambda", "ec2")
+
+	// Execute
+	first, err := json.Marshal(forward)
+	require.NoError(t, err)
+	second, err := json.Marshal(forward)
+	require.NoError(t, err)
+	fromReversed, err := json.Marshal(reversed)
+	require.NoError(t, err)
+	byValue, err := json.Marshal(*reversed)
+	require.NoError(t, err)
+
+	// Verify - identical bytes regardless of insertion order, and the index itself is not reordered
+	assert.Equal(t, first, second)
+	assert.Equal(t, first, fromReversed)
+	assert.Equal(t, first, byValue)
+	assert.Equal(t, "s3", reversed.Services[0].ServiceName)
+
+	var decoded TerraformProviderIndex
+	require.NoError(t, json.Unmarshal(first, &decoded))
+	assert.Equal(t, []string{"ec2", "lambda", "s3"}, []string{decoded.Services[0].ServiceName, decoded.Services[1].ServiceName, decoded.Services[2].ServiceName})
+}
+
+func TestScanTerraformProviderServices_RecordsScanDuration(t *testing.T) {
+	// Setup - scanning the package takes at least a few milliseconds
+	servicesFs := afero.NewMemMapFs()
+	require.NoError(t, servicesFs.MkdirAll("/services/widget", 0755))
+	inputStub := gostub.Stub(&inputFs, servicesFs)
+	defer inputStub.Reset()
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		time.Sleep(5 * time.Millisecond)
+		return createMockPackageInfoFromSource(t, pkgPath+"/widget.go", "example.com/provider/widget", `package widget
+
+// @SDKResource("aws_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{}
+}
+`), nil
+	})
+	defer scanStub.Reset()
+	fs := afero.NewMemMapFs()
+	outputStub := gostub.Stub(&outputFs, fs)
+	defer outputStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServices("/services", "example.com/provider", "v1.0.0", nil)
+
+	// Verify - the duration is reported and written with the statistics
+	require.NoError(t, err)
+	assert.Positive(t, index.Statistics.ScanDurationMs)
+
+	require.NoError(t, index.WriteMainIndexFile("/output"))
+	data, err := afero.ReadFile(fs, "/output/terraform-provider-aws-index.json")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "scan_duration_ms")
+}
+
+func TestScanTerraformProviderServices_DeterministicMainIndex(t *testing.T) {
+	// Setup - services finish scanning in a different order on every run
+	servicesFs := afero.NewMemMapFs()
+	var serviceNames []string
+	for i := 0; i < 16; i++ {
+		serviceName := fmt.Sprintf("svc%02d", i)
+		serviceNames = append(serviceNames, serviceName)
+		require.NoError(t, servicesFs.MkdirAll("/services/"+serviceName, 0755))
+	}
+	inputStub := gostub.Stub(&inputFs, servicesFs)
+	defer inputStub.Reset()
+
+	var run atomic.Int32
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		service := filepath.Base(pkgPath)
+		// Reverse the completion order between runs
+		index := sort.SearchStrings(serviceNames, service)
+		if run.Load()%2 == 1 {
+			index = len(serviceNames) - index
+		}
+		time.Sleep(time.Duration(index) * time.Millisecond)
+		return createMockPackageInfoFromSource(t, pkgPath+"/main.go", "example.com/provider/"+service, fmt.Sprintf(`package %[1]s
+
+// @SDKResource("aws_%[1]s_widget", name="Widget")
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{}
+}
+`, service)), nil
+	})
+	defer scanStub.Reset()
+
+	fs := afero.NewMemMapFs()
+	outputStub := gostub.Stub(&outputFs, fs)
+	defer outputStub.Reset()
+
+	// Execute
+	var outputs [][]byte
+	for i := 0; i < 2; i++ {
+		run.Store(int32(i))
+		index, err := ScanTerraformProviderServices("/services", "example.com/provider", "v1.0.0", nil)
+		require.NoError(t, err)
+		// The scan duration varies between runs by design
+		index.Statistics.ScanDurationMs = 0
+
+		outputDir := fmt.Sprintf("/output/run%d", i)
+		require.NoError(t, index.WriteMainIndexFile(outputDir))
+		data, err := afero.ReadFile(fs, filepath.Join(outputDir, "terraform-provider-aws-index.json"))
+		require.NoError(t, err)
+		outputs = append(outputs, data)
+
+		var written TerraformProviderIndex
+		require.NoError(t, json.Unmarshal(data, &written))
+		var names []string
+		for _, service := range written.Services {
+			names = append(names, service.ServiceName)
+		}
+		assert.Equal(t, serviceNames, names)
+	}
+
+	// Verify
+	assert.Equal(t, string(outputs[0]), string(outputs[1]))
+}
+
+func TestTerraformProviderIndex_WriteMainIndexFile_SortsServices(t *testing.T) {
+	// Setup - services out of order, e.g. after Merge
+	index := CreateTestTerraformProviderIndex()
+	index.Services = append(index.Services, CreateTestServiceRegistration("ec2"))
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+
+	// Execute
+	require.NoError(t, index.WriteMainIndexFile("/test/output"))
+
+	// Verify - written sorted, caller's slice untouched
+	data, err := afero.ReadFile(fs, "/test/output/terraform-provider-aws-index.json")
+	require.NoError(t, err)
+	var written TerraformProviderIndex
+	require.NoError(t, json.Unmarshal(data, &written))
+	require.Len(t, written.Services, 2)
+	assert.Equal(t, "ec2", written.Services[0].ServiceName)
+	assert.Equal(t, "s3", written.Services[1].ServiceName)
+	assert.Equal(t, "s3", index.Services[0].ServiceName)
+}
+
+func TestScanTerraformProviderServices_RecordsPackageName(t *testing.T) {
+	// Setup - the package clause differs from both the directory and the import path
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/services/route53", 0755))
+	inputStub := gostub.Stub(&inputFs, fs)
+	defer inputStub.Reset()
+
+	packageInfo := createMockPackageInfoFromSource(t, "/services/route53/zone.go", "example.com/provider/route53", `package route53zones
+
+// @SDKResource("aws_route53_zone", name="Zone")
+func resourceZone() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @SDKDataSource("aws_route53_zone", name="Zone")
+func dataSourceZone() *schema.Resource {
+	return &schema.Resource{}
+}
+
+// @FrameworkResource("aws_route53_profile", name="Profile")
+func newProfileResource(context.Context) (resource.ResourceWithConfigure, error) {
+	return &profileResource{}, nil
+}
+
+type profileResource struct{}
+
+func (r *profileResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+
+// @EphemeralResource("aws_route53_key", name="Key")
+func newKeyEphemeralResource(context.Context) (ephemeral.EphemeralResourceWithConfigure, error) {
+	return &keyEphemeralResource{}, nil
+}
+
+type keyEphemeralResource struct{}
+
+func (r *keyEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {}
+`)
+	scanStub := gostub.Stub(&scanSinglePackage, func(pkgPath, basePkgUrl string) (*gophon.PackageInfo, error) {
+		return packageInfo, nil
+	})
+	defer scanStub.Reset()
+
+	// Execute
+	index, err := ScanTerraformProviderServices("/services", "example.com/provider", "v1.0.0", nil)
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, index.Services, 1)
+	service := index.Services[0]
+	assert.Equal(t, "route53zones", service.PackageName)
+
+	assert.Equal(t, "route53zones", NewTerraformResourceFromAWSSDK(service.AWSSDKResources["aws_route53_zone"], service).PackageName)
+	assert.Equal(t, "route53zones", NewTerraformDataSourceFromAWSSDK(service.AWSSDKDataSources["aws_route53_zone"], service).PackageName)
+	assert.Equal(t, "route53zones", NewTerraformResourceFromAWSFramework(service.AWSFrameworkResources["aws_route53_profile"], service, service.Package).PackageName)
+	assert.Equal(t, "route53zones", NewTerraformEphemeralFromAWS(service.AWSEphemeralResources["aws_route53_key"], service).PackageName)
+}
@@ -1,34 +1,132 @@
 package pkg
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+
+	gophon "github.com/lonegunmanb/gophon/pkg"
+)
 
 // TerraformResource represents information about a Terraform resource
 type TerraformResource struct {
-	TerraformType      string `json:"terraform_type"` // "aws_vpc"
-	StructType         string `json:"struct_type"`
-	Namespace          string `json:"namespace"`           // "github.com/hashicorp/terraform-provider-aws/internal/service/resource/ec2"
-	RegistrationMethod string `json:"registration_method"` // "SupportedResources", "Resources", etc.
-	SDKType            string `json:"sdk_type"`            // "legacy_pluginsdk", "modern_sdk"
-	SchemaIndex        string `json:"schema_index,omitempty"`
-	CreateIndex        string `json:"create_index,omitempty"`
-	ReadIndex          string `json:"read_index,omitempty"`
-	UpdateIndex        string `json:"update_index,omitempty"`
-	DeleteIndex        string `json:"delete_index,omitempty"`
-	AttributeIndex     string `json:"attribute_index,omitempty"`
+	ID                 string   `json:"id"`                     // Stable hash of namespace, terraform type and category
+	Category           string   `json:"category"`               // Walk category matching the output directory, e.g. "resource"
+	TerraformType      string   `json:"terraform_type"`         // "aws_vpc"
+	DisplayName        string   `json:"display_name,omitempty"` // Registration name, or one derived from the terraform type, e.g. "S3 Bucket"
+	StructType         string   `json:"struct_type"`
+	ReceiverName       string   `json:"receiver_name,omitempty"` // Framework method receiver variable, e.g. "r"
+	Namespace          string   `json:"namespace"`               // "github.com/hashicorp/terraform-provider-aws/internal/service/resource/ec2"
+	PackageName        string   `json:"package_name,omitempty"`  // Go package name, e.g. "s3"
+	RegistrationMethod string   `json:"registration_method"`     // "SupportedResources", "Resources", etc.
+	SDKType            string   `json:"sdk_type"`                // "legacy_pluginsdk", "modern_sdk"
+	SchemaIndex        string   `json:"schema_index,omitempty"`
+	CreateIndex        string   `json:"create_index,omitempty"`
+	ReadIndex          string   `json:"read_index,omitempty"`
+	UpdateIndex        string   `json:"update_index,omitempty"`
+	DeleteIndex        string   `json:"delete_index,omitempty"`
+	AttributeIndex     string   `json:"attribute_index,omitempty"`
+	Attributes         []string `json:"attributes,omitempty"`        // Top-level schema attribute and block names
+	RegistrationFile   string   `json:"registration_file,omitempty"` // Source file declaring the entry
+	RawAnnotation      string   `json:"raw_annotation,omitempty"`    // Registration annotation as written in the source
+	ImportIndex        string   `json:"import_index,omitempty"`      // Explicit ImportState method index
+	ImportSupported    bool     `json:"import_supported,omitempty"`  // Explicit ImportState method or embedded import helper
+	NoopMethods        []string `json:"noop_methods,omitempty"`      // CRUD methods that are intentional noops, e.g. ["read"]
+	Description        string   `json:"description,omitempty"`
+	Deprecated         bool     `json:"deprecated,omitempty"`
+	DeprecationMessage string   `json:"deprecation_message,omitempty"`
+	SchemaVersion      int      `json:"schema_version,omitempty"`       // SDK schema.Resource SchemaVersion
+	StateUpgraders     []string `json:"state_upgraders,omitempty"`      // SDK state upgrade functions, oldest first
+	HasDataSourceTwin  bool     `json:"has_data_source_twin,omitempty"` // A data source with the same terraform type exists
+
+	// Framework methods expected of the struct split by whether it provides them, a missing CRUD method has no index
+	ImplementedFrameworkMethods []string `json:"implemented_framework_methods,omitempty"` // e.g. ["Create", "Read", "Delete", "Metadata", "Schema"]
+	MissingFrameworkMethods     []string `json:"missing_framework_methods,omitempty"`     // e.g. ["Update"]
+
+	// Framework plan-time and validation method indexes, only set when the struct declares the method
+	ModifyPlanIndex       string `json:"modify_plan_index,omitempty"`
+	ValidateConfigIndex   string `json:"validate_config_index,omitempty"`
+	ConfigValidatorsIndex string `json:"config_validators_index,omitempty"`
+
+	// Child attribute names of SDK blocks declared via Elem: &schema.Resource{...}, keyed by block name, one level deep
+	NestedAttributes map[string][]string `json:"nested_attributes,omitempty"`
+
+	// SHA-256 of the registration function's code, for detecting changed resources without diffing sources
+	SourceHash string `json:"source_hash,omitempty"`
+
+	// Tags of the //go:build constraint of the registration file, the entry only exists in builds satisfying it
+	BuildConstrained bool     `json:"build_constrained,omitempty"`
+	BuildTags        []string `json:"build_tags,omitempty"`
+
+	// Account-level settings resource, declared via @SingletonIdentity and without a real Delete method
+	Singleton bool `json:"singleton,omitempty"`
+
+	// Package functions supplying plan-time defaults of top-level SDK attributes via Default or DefaultFunc,
+	// keyed by attribute name, e.g. {"region": "func.regionDefault.goindex"}
+	DefaultFuncIndexes map[string]string `json:"default_func_indexes,omitempty"`
+
+	// Indexes of unexported package functions referenced by the factory, e.g. expand and flatten helpers.
+	// Only set with OutputOptions.RelatedFunctions
+	RelatedFunctions []string `json:"related_functions,omitempty"`
+
+	// Top-level attributes declared with `WriteOnly: true`, accepted in configuration but never stored in plan or state,
+	// e.g. passwords supplied through ephemeral values
+	HasWriteOnlyAttributes bool     `json:"has_write_only_attributes,omitempty"`
+	WriteOnlyAttributes    []string `json:"write_only_attributes,omitempty"`
+
+	// Acceptance test generation hints declared by @Testing, keyed by annotation argument, e.g. "importIgnore"
+	TestingConfig map[string]string `json:"testing_config,omitempty"`
 }
 
 // NewTerraformResourceFromAWSSDK creates a TerraformResource from AWS SDK resource info
 func NewTerraformResourceFromAWSSDK(awsResource AWSResource, serviceReg ServiceRegistration) TerraformResource {
 	result := TerraformResource{
+		ID:                 entryID(serviceReg.PackagePath, awsResource.TerraformType, WalkCategoryResource),
+		Category:           WalkCategoryResource,
 		TerraformType:      awsResource.TerraformType,
+		DisplayName:        displayName(awsResource),
 		StructType:         "", // AWS SDK resources don't have struct types
 		Namespace:          serviceReg.PackagePath,
+		PackageName:        serviceReg.PackageName,
 		RegistrationMethod: "SDKResources",
 		SDKType:            "aws_sdk",
 		// Schema and Attribute indexes always use the factory function
-		SchemaIndex:    fmt.Sprintf("func.%s.goindex", awsResource.FactoryFunction),
-		AttributeIndex: fmt.Sprintf("func.%s.goindex", awsResource.FactoryFunction),
+		SchemaIndex:    fmt.Sprintf("func.%s.goindex", awsResource.declaredFactory()),
+		AttributeIndex: fmt.Sprintf("func.%s.goindex", awsResource.declaredFactory()),
+
+		RegistrationFile: awsResource.RegistrationFile,
+		RawAnnotation:    awsResource.RawAnnotation,
+
+		Description:        awsResource.Description,
+		Deprecated:         awsResource.DeprecationMessage != "",
+		DeprecationMessage: awsResource.DeprecationMessage,
+
+		SchemaVersion:  awsResource.SchemaVersion,
+		StateUpgraders: awsResource.StateUpgraders,
+
+		HasDataSourceTwin: awsResource.HasDataSourceTwin,
+
+		SourceHash:       awsResource.SourceHash,
+		BuildConstrained: len(awsResource.BuildTags) > 0,
+		BuildTags:        awsResource.BuildTags,
+
+		TestingConfig: awsResource.TestingConfig,
 	}
+	// Any importer makes the resource importable, only a custom StateContext function has an index
+	if awsResource.Import != nil {
+		result.ImportSupported = true
+		if awsResource.Import.ImportStateFunc != "" {
+			result.ImportIndex = fmt.Sprintf("func.%s.goindex", awsResource.Import.ImportStateFunc)
+		}
+	}
+	result.Attributes, result.NestedAttributes = extractSDKSchemaAttributes(serviceReg.Package, awsResource.declaredFactory())
+	for attribute, funcName := range extractSDKSchemaDefaultFuncs(serviceReg.Package, awsResource.declaredFactory()) {
+		if result.DefaultFuncIndexes == nil {
+			result.DefaultFuncIndexes = make(map[string]string)
+		}
+		result.DefaultFuncIndexes[attribute] = fmt.Sprintf("func.%s.goindex", funcName)
+	}
+	result.WriteOnlyAttributes = extractSDKSchemaWriteOnlyAttributes(serviceReg.Package, awsResource.declaredFactory())
+	result.HasWriteOnlyAttributes = len(result.WriteOnlyAttributes) > 0
 
 	// Use extracted CRUD methods if available (same pattern as legacy plugin SDK resources)
 	if crudMethods, exists := serviceReg.ResourceCRUDMethods[awsResource.TerraformType]; exists && crudMethods != nil {
@@ -44,31 +142,102 @@ func NewTerraformResourceFromAWSSDK(awsResource AWSResource, serviceReg ServiceR
 		if crudMethods.DeleteMethod != "" {
 			result.DeleteIndex = fmt.Sprintf("func.%s.goindex", crudMethods.DeleteMethod)
 		}
+		// Noop methods have no index, record them so consumers can tell them apart from missing methods
+		result.NoopMethods = crudMethods.NoopMethods
+	}
+	// Older service packages register the resource through a map instead of an annotation
+	if awsResource.RegistrationMethod != "" {
+		result.RegistrationMethod = awsResource.RegistrationMethod
 	}
+	result.Singleton = isSingletonResource(awsResource.Identity, result.DeleteIndex != "" && !slices.Contains(result.NoopMethods, "delete"))
 
 	return result
 }
 
 // NewTerraformResourceFromAWSFramework creates a TerraformResource from AWS Framework resource info
-func NewTerraformResourceFromAWSFramework(awsResource AWSResource, serviceReg ServiceRegistration) TerraformResource {
+// The package info is used to locate the struct's Schema method and extract its attribute names
+func NewTerraformResourceFromAWSFramework(awsResource AWSResource, serviceReg ServiceRegistration, packageInfo *gophon.PackageInfo) TerraformResource {
 	// Framework resources use the actual struct type extracted from the factory function
 	structType := awsResource.StructType
 
 	result := TerraformResource{
+		ID:                 entryID(serviceReg.PackagePath, awsResource.TerraformType, WalkCategoryResource),
+		Category:           WalkCategoryResource,
 		TerraformType:      awsResource.TerraformType,
+		DisplayName:        displayName(awsResource),
 		StructType:         structType, // Framework resources use struct types
 		Namespace:          serviceReg.PackagePath,
+		PackageName:        serviceReg.PackageName,
 		RegistrationMethod: "FrameworkResources",
 		SDKType:            "aws_framework",
 		// Framework resources use method-based indexes on struct types
 		SchemaIndex:    fmt.Sprintf("method.%s.Schema.goindex", structType),
 		AttributeIndex: fmt.Sprintf("method.%s.Schema.goindex", structType),
+
+		RegistrationFile:  awsResource.RegistrationFile,
+		RawAnnotation:     awsResource.RawAnnotation,
+		HasDataSourceTwin: awsResource.HasDataSourceTwin,
+
+		SourceHash:       awsResource.SourceHash,
+		BuildConstrained: len(awsResource.BuildTags) > 0,
+		BuildTags:        awsResource.BuildTags,
+
+		TestingConfig: awsResource.TestingConfig,
+
+		ImplementedFrameworkMethods: awsResource.ImplementedFrameworkMethods,
+		MissingFrameworkMethods:     awsResource.MissingFrameworkMethods,
 	}
 
-	result.CreateIndex = fmt.Sprintf("method.%s.Create.goindex", structType)
-	result.ReadIndex = fmt.Sprintf("method.%s.Read.goindex", structType)
-	result.UpdateIndex = fmt.Sprintf("method.%s.Update.goindex", structType)
-	result.DeleteIndex = fmt.Sprintf("method.%s.Delete.goindex", structType)
+	// CRUD methods the struct lacks, e.g. Update of a resource whose attributes all force replacement, get no index
+	crudIndex := func(method string) string {
+		if slices.Contains(awsResource.MissingFrameworkMethods, method) {
+			return ""
+		}
+		return fmt.Sprintf("method.%s.%s.goindex", structType, method)
+	}
+	result.CreateIndex = crudIndex("Create")
+	result.ReadIndex = crudIndex("Read")
+	result.UpdateIndex = crudIndex("Update")
+	result.DeleteIndex = crudIndex("Delete")
+	result.Attributes = extractFrameworkSchemaAttributes(packageInfo, structType)
+	result.WriteOnlyAttributes = extractFrameworkSchemaWriteOnlyAttributes(packageInfo, structType)
+	result.HasWriteOnlyAttributes = len(result.WriteOnlyAttributes) > 0
+
+	if methods, exists := serviceReg.FrameworkResourceMethods[awsResource.TerraformType]; exists && methods != nil {
+		result.ReceiverName = methods.ReceiverName
+
+		// Import support is either an explicit ImportState method or an embedded framework helper
+		if methods.ImportStateMethod != "" {
+			result.ImportSupported = true
+			if methods.ImportStateMethod == "ImportState" {
+				result.ImportIndex = fmt.Sprintf("method.%s.ImportState.goindex", structType)
+			}
+		}
+
+		// Optional plan-time and validation methods only get an index when declared
+		if methods.ModifyPlanMethod != "" {
+			result.ModifyPlanIndex = fmt.Sprintf("method.%s.%s.goindex", structType, methods.ModifyPlanMethod)
+		}
+		if methods.ValidateConfigMethod != "" {
+			result.ValidateConfigIndex = fmt.Sprintf("method.%s.%s.goindex", structType, methods.ValidateConfigMethod)
+		}
+		if methods.ConfigValidatorsMethod != "" {
+			result.ConfigValidatorsIndex = fmt.Sprintf("method.%s.%s.goindex", structType, methods.ConfigValidatorsMethod)
+		}
+	}
+	// DeleteIndex is also set for a Delete from an embedded helper, for singletons only a Delete declared on
+	// the struct counts, an embedded framework.WithNoOpDelete does not
+	hasDelete := false
+	if methods, exists := serviceReg.FrameworkResourceMethods[awsResource.TerraformType]; exists && methods != nil {
+		hasDelete = methods.DeleteMethod != ""
+	}
+	result.Singleton = isSingletonResource(awsResource.Identity, hasDelete)
 
 	return result
 }
+
+// isSingletonResource reports whether a resource manages a single account-level setting,
+// i.e. it declares a singleton identity and has nothing to delete
+func isSingletonResource(identity *AWSIdentityConfig, hasDelete bool) bool {
+	return identity != nil && identity.Singleton && !hasDelete
+}
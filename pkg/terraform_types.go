@@ -0,0 +1,67 @@
+package pkg
+
+import "sort"
+
+// AllTerraformTypes returns every terraform type registered in the index, resources, data sources and
+// ephemeral resources alike, sorted and de-duplicated. A type registered as both a resource and a data
+// source is listed once. Aliases are not included
+func (index *TerraformProviderIndex) AllTerraformTypes() []string {
+	return index.terraformTypes(WalkCategoryResource, WalkCategoryDataSource, WalkCategoryEphemeral)
+}
+
+// TerraformTypes is like AllTerraformTypes but only lists the types of one Walk category,
+// e.g. WalkCategoryDataSource. An unknown category yields an empty list
+func (index *TerraformProviderIndex) TerraformTypes(category string) []string {
+	return index.terraformTypes(category)
+}
+
+// IsKnownType reports whether terraformType is registered in the index and in which Walk category, e.g. for
+// linting the types used in a Terraform configuration. A type registered in several categories reports
+// WalkCategoryResource over WalkCategoryDataSource over WalkCategoryEphemeral. Aliases are not known types
+func (index *TerraformProviderIndex) IsKnownType(terraformType string) (category string, ok bool) {
+	for _, category := range []string{WalkCategoryResource, WalkCategoryDataSource, WalkCategoryEphemeral} {
+		for i := range index.Services {
+			for _, resources := range serviceCategoryMaps(&index.Services[i], category) {
+				if _, exists := resources[terraformType]; exists {
+					return category, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// terraformTypes returns the sorted, de-duplicated terraform types of the given categories
+func (index *TerraformProviderIndex) terraformTypes(categories ...string) []string {
+	seen := make(map[string]bool)
+	for i := range index.Services {
+		service := &index.Services[i]
+		for _, category := range categories {
+			for _, resources := range serviceCategoryMaps(service, category) {
+				for terraformType := range resources {
+					seen[terraformType] = true
+				}
+			}
+		}
+	}
+
+	terraformTypes := make([]string, 0, len(seen))
+	for terraformType := range seen {
+		terraformTypes = append(terraformTypes, terraformType)
+	}
+	sort.Strings(terraformTypes)
+	return terraformTypes
+}
+
+// serviceCategoryMaps returns the SDK and framework maps of a service holding entries of category
+func serviceCategoryMaps(service *ServiceRegistration, category string) []map[string]AWSResource {
+	switch category {
+	case WalkCategoryResource:
+		return []map[string]AWSResource{service.AWSSDKResources, service.AWSFrameworkResources}
+	case WalkCategoryDataSource:
+		return []map[string]AWSResource{service.AWSSDKDataSources, service.AWSFrameworkDataSources}
+	case WalkCategoryEphemeral:
+		return []map[string]AWSResource{service.AWSEphemeralResources}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package pkg
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerraformProviderIndex_AllTerraformTypes(t *testing.T) {
+	// Setup - aws_s3_bucket is both a resource and a data source, the second service adds an ephemeral
+	index := createTestTerraformProviderIndex()
+	kms := CreateTestServiceRegistration("kms")
+	kms.AWSSDKResources["aws_kms_key"] = AWSResource{TerraformType: "aws_kms_key", SDKType: "sdk"}
+	kms.AWSEphemeralResources["aws_kms_secrets"] = AWSResource{TerraformType: "aws_kms_secrets", SDKType: "framework"}
+	index.Services = append(index.Services, kms)
+
+	// Execute
+	allTypes := index.AllTerraformTypes()
+
+	// Verify - the result is the sorted union of every category map
+	seen := make(map[string]bool)
+	for _, service := range index.Services {
+		for _, resources := range []map[string]AWSResource{
+			service.AWSSDKResources, service.AWSFrameworkResources,
+			service.AWSSDKDataSources, service.AWSFrameworkDataSources,
+			service.AWSEphemeralResources,
+		} {
+			for terraformType := range resources {
+				seen[terraformType] = true
+			}
+		}
+	}
+	var expected []string
+	for terraformType := range seen {
+		expected = append(expected, terraformType)
+	}
+	sort.Strings(expected)
+
+	assert.Equal(t, expected, allTypes)
+	assert.Equal(t, []string{"aws_kms_key", "aws_kms_secrets", "aws_s3_bucket", "aws_s3_bucket_policy"}, allTypes)
+	assert.Equal(t, []string{"aws_kms_key", "aws_s3_bucket", "aws_s3_bucket_policy"}, index.TerraformTypes(WalkCategoryResource))
+	assert.Equal(t, []string{"aws_s3_bucket"}, index.TerraformTypes(WalkCategoryDataSource))
+	assert.Equal(t, []string{"aws_kms_secrets"}, index.TerraformTypes(WalkCategoryEphemeral))
+	assert.Empty(t, index.TerraformTypes("unknown"))
+}
+
+func TestTerraformProviderIndex_IsKnownType(t *testing.T) {
+	// Setup - aws_s3_bucket is both a resource and a data source
+	index := createTestTerraformProviderIndex()
+	kms := CreateTestServiceRegistration("kms")
+	kms.AWSEphemeralResources["aws_kms_secrets"] = AWSResource{TerraformType: "aws_kms_secrets", SDKType: "framework"}
+	kms.AWSSDKDataSources["aws_kms_alias"] = AWSResource{TerraformType: "aws_kms_alias", SDKType: "sdk"}
+	index.Services = append(index.Services, kms)
+
+	tests := []struct {
+		terraformType    string
+		expectedCategory string
+		expectedOK       bool
+	}{
+		{terraformType: "aws_s3_bucket_policy", expectedCategory: WalkCategoryResource, expectedOK: true},
+		{terraformType: "aws_kms_alias", expectedCategory: WalkCategoryDataSource, expectedOK: true},
+		{terraformType: "aws_kms_secrets", expectedCategory: WalkCategoryEphemeral, expectedOK: true},
+		{terraformType: "aws_s3_bucket", expectedCategory: WalkCategoryResource, expectedOK: true},
+		{terraformType: "aws_unknown_widget"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.terraformType, func(t *testing.T) {
+			category, ok := index.IsKnownType(tt.terraformType)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedCategory, category)
+		})
+	}
+}
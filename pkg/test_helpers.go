@@ -80,6 +80,7 @@ func CreateTestServiceRegistration(serviceName string) ServiceRegistration {
 	return ServiceRegistration{
 		ServiceName:              serviceName,
 		PackagePath:              "github.com/hashicorp/terraform-provider-aws/internal/service/" + serviceName,
+		PackageName:              serviceName,
 		AWSSDKResources:          make(map[string]AWSResource),
 		AWSSDKDataSources:        make(map[string]AWSResource),
 		AWSFrameworkResources:    make(map[string]AWSResource),
@@ -87,6 +88,7 @@ func CreateTestServiceRegistration(serviceName string) ServiceRegistration {
 		AWSEphemeralResources:    make(map[string]AWSResource),
 		ResourceCRUDMethods:      make(map[string]*LegacyResourceCRUDFunctions),
 		DataSourceMethods:        make(map[string]*LegacyDataSourceMethods),
+		FrameworkResourceMethods: make(map[string]*AWSCRUDMethods),
 		ResourceTerraformTypes:   make(map[string]string),
 		DataSourceTerraformTypes: make(map[string]string),
 		EphemeralTerraformTypes:  make(map[string]string),
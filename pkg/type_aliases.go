@@ -0,0 +1,36 @@
+package pkg
+
+import "sort"
+
+// TypeAlias maps a deprecated terraform type to the canonical type registered by the same function
+type TypeAlias struct {
+	Alias         string `json:"alias"`          // "aws_alb"
+	TerraformType string `json:"terraform_type"` // "aws_lb"
+	Category      string `json:"category"`       // WalkCategoryResource, WalkCategoryDataSource or WalkCategoryEphemeral
+	ServiceName   string `json:"service_name"`
+}
+
+// collectTypeAliases lists the aliases of every entry sorted by alias and category. Aliases are kept out of the
+// service maps so they are neither counted in statistics nor written as entry files of their own
+func collectTypeAliases(index *TerraformProviderIndex) []TypeAlias {
+	var aliases []TypeAlias
+	_ = index.walkEntries(func(category string, _ bool, service *ServiceRegistration, resource AWSResource) error {
+		for _, alias := range resource.Aliases {
+			aliases = append(aliases, TypeAlias{
+				Alias:         alias,
+				TerraformType: resource.TerraformType,
+				Category:      category,
+				ServiceName:   service.ServiceName,
+			})
+		}
+		return nil
+	})
+
+	sort.SliceStable(aliases, func(i, j int) bool {
+		if aliases[i].Alias != aliases[j].Alias {
+			return aliases[i].Alias < aliases[j].Alias
+		}
+		return aliases[i].Category < aliases[j].Category
+	})
+	return aliases
+}
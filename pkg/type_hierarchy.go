@@ -0,0 +1,51 @@
+package pkg
+
+import "strings"
+
+// TypeHierarchyNode is a terraform type in the hierarchy built by BuildTypeHierarchy, with the types
+// extending its name as children, e.g. aws_s3_bucket_policy under aws_s3_bucket
+type TypeHierarchyNode struct {
+	TerraformType string               `json:"terraform_type"`
+	Children      []*TypeHierarchyNode `json:"children,omitempty"`
+}
+
+// BuildTypeHierarchy groups the terraform types of the index by shared name prefix, purely from the type
+// names. A type is nested under the longest other indexed type its name extends by whole "_" separated
+// words, so aws_s3_bucket_acl goes under aws_s3_bucket but aws_s3control_bucket does not go under aws_s3.
+// Prefixes that are not types themselves, like the provider's "aws" or a service's "aws_s3", never become
+// nodes: types without an indexed parent are roots. Roots and children are sorted by terraform type
+func (index *TerraformProviderIndex) BuildTypeHierarchy() []*TypeHierarchyNode {
+	terraformTypes := index.AllTerraformTypes()
+
+	nodes := make(map[string]*TypeHierarchyNode, len(terraformTypes))
+	for _, terraformType := range terraformTypes {
+		nodes[terraformType] = &TypeHierarchyNode{TerraformType: terraformType}
+	}
+
+	// AllTerraformTypes is sorted, so children are appended in order
+	var roots []*TypeHierarchyNode
+	for _, terraformType := range terraformTypes {
+		node := nodes[terraformType]
+		if parent := nodes[typeHierarchyParent(terraformType, nodes)]; parent != nil {
+			parent.Children = append(parent.Children, node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+// typeHierarchyParent returns the longest prefix of terraformType ending before a "_" that is a node,
+// or "" when there is none
+func typeHierarchyParent(terraformType string, nodes map[string]*TypeHierarchyNode) string {
+	for prefix := terraformType; ; {
+		i := strings.LastIndex(prefix, "_")
+		if i <= 0 {
+			return ""
+		}
+		prefix = prefix[:i]
+		if _, exists := nodes[prefix]; exists {
+			return prefix
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_BuildTypeHierarchy(t *testing.T) {
+	// Setup - s3 registers aws_s3_bucket and aws_s3_bucket_policy, add related types of other services
+	index := createTestTerraformProviderIndex()
+	s3control := newMergeTestService("s3control", []string{"aws_s3control_bucket"}, nil)
+	s3control.AWSSDKResources["aws_s3_bucket_acl"] = AWSResource{TerraformType: "aws_s3_bucket_acl", SDKType: "sdk"}
+	s3control.AWSSDKResources["aws_s3_bucket_acl_grant"] = AWSResource{TerraformType: "aws_s3_bucket_acl_grant", SDKType: "sdk"}
+	s3control.AWSSDKResources["aws_s3_object"] = AWSResource{TerraformType: "aws_s3_object", SDKType: "sdk"}
+	index.Services = append(index.Services, s3control)
+
+	// Execute
+	roots := index.BuildTypeHierarchy()
+
+	// Verify - neither "aws" nor "aws_s3" become nodes, aws_s3control_bucket does not nest under the s3 types
+	require.Len(t, roots, 3)
+	assert.Equal(t, "aws_s3_bucket", roots[0].TerraformType)
+	assert.Equal(t, "aws_s3_object", roots[1].TerraformType)
+	assert.Equal(t, "aws_s3control_bucket", roots[2].TerraformType)
+	assert.Empty(t, roots[1].Children)
+
+	// The data source twin of aws_s3_bucket is the same node
+	bucket := roots[0]
+	require.Len(t, bucket.Children, 2)
+	assert.Equal(t, "aws_s3_bucket_acl", bucket.Children[0].TerraformType)
+	assert.Equal(t, "aws_s3_bucket_policy", bucket.Children[1].TerraformType)
+
+	// Nesting goes as deep as the names do
+	require.Len(t, bucket.Children[0].Children, 1)
+	assert.Equal(t, "aws_s3_bucket_acl_grant", bucket.Children[0].Children[0].TerraformType)
+}
+
+func TestTerraformProviderIndex_BuildTypeHierarchy_Empty(t *testing.T) {
+	assert.Empty(t, (&TerraformProviderIndex{}).BuildTypeHierarchy())
+}
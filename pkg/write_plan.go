@@ -0,0 +1,143 @@
+package pkg
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+// PlannedFile is a file WriteIndexFiles would write
+type PlannedFile struct {
+	Path     string `json:"path"`     // Slash-separated path relative to the output directory
+	Category string `json:"category"` // "index", "service", "attribute_schema", "resource", "datasource" or "ephemeral"
+}
+
+// PlannedFile categories of files that are not resource, data source or ephemeral entries
+const (
+	WritePlanCategoryIndex   = "index"   // Top-level index files
+	WritePlanCategoryService = "service" // Per-service files written when OutputOptions.ServiceFiles is set
+
+	// Per-resource attribute schema files written when OutputOptions.AttributeSchemas is set
+	WritePlanCategoryAttributeSchema = "attribute_schema"
+)
+
+// WritePlan describes the output of WriteIndexFiles without writing anything
+type WritePlan struct {
+	OutputDir string         `json:"output_dir"`
+	Files     []PlannedFile  `json:"files"`  // Unique files sorted by path
+	Totals    map[string]int `json:"totals"` // Unique file count per directory, "." for the output directory itself
+}
+
+// PlanIndexFiles computes the files WriteIndexFiles would write to outputDir without touching the filesystem
+func (index *TerraformProviderIndex) PlanIndexFiles(outputDir string) (*WritePlan, error) {
+	outputDir, err := cleanOutputDir(outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	writes, err := index.plannedWrites()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &WritePlan{
+		OutputDir: outputDir,
+		Totals:    make(map[string]int),
+	}
+
+	seen := make(map[string]bool, len(writes))
+	for _, file := range writes {
+		if seen[file.Path] {
+			continue
+		}
+		seen[file.Path] = true
+		plan.Files = append(plan.Files, file)
+		plan.Totals[path.Dir(file.Path)]++
+	}
+	sort.Slice(plan.Files, func(i, j int) bool {
+		return plan.Files[i].Path < plan.Files[j].Path
+	})
+
+	return plan, nil
+}
+
+// plannedWrites lists every write WriteIndexFiles performs, one per file, in no particular order.
+// It is the single source for progress totals and write plans
+func (index *TerraformProviderIndex) plannedWrites() ([]PlannedFile, error) {
+	files := []PlannedFile{
+		{Path: index.outputFileName(index.outputOptions.mainIndexFileName()), Category: WritePlanCategoryIndex},
+		{Path: index.outputFileName("factory-index.json"), Category: WritePlanCategoryIndex},
+		{Path: index.outputFileName("statistics.json"), Category: WritePlanCategoryIndex},
+		{Path: index.outputFileName(manifestFileName), Category: WritePlanCategoryIndex},
+	}
+
+	// add records the first error and skips later entries so the loops below stay flat
+	var err error
+	add := func(category, service, terraformType string) {
+		if err != nil {
+			return
+		}
+		var fileName string
+		if fileName, err = index.entryFileName(category, service, terraformType); err != nil {
+			return
+		}
+		files = append(files, PlannedFile{
+			Path:     path.Join(index.outputOptions.categoryDir(category), index.outputFileName(fileName)),
+			Category: category,
+		})
+	}
+
+	for _, service := range index.Services {
+		// AWS 5-category files
+		for terraformType := range service.AWSSDKResources {
+			add(WalkCategoryResource, service.ServiceName, terraformType)
+		}
+		for terraformType := range service.AWSFrameworkResources {
+			add(WalkCategoryResource, service.ServiceName, terraformType)
+		}
+		for terraformType := range service.AWSSDKDataSources {
+			add(WalkCategoryDataSource, service.ServiceName, terraformType)
+		}
+		for terraformType := range service.AWSFrameworkDataSources {
+			add(WalkCategoryDataSource, service.ServiceName, terraformType)
+		}
+		for _, ephemeral := range service.AWSEphemeralResources {
+			add(WalkCategoryEphemeral, service.ServiceName, ephemeral.TerraformType)
+		}
+		// Framework ephemeral resources (backward compatibility) without an AWS ephemeral resource
+		for _, terraformType := range service.legacyEphemeralTerraformTypes() {
+			add(WalkCategoryEphemeral, service.ServiceName, terraformType)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if index.outputOptions.ServiceFiles {
+		for _, service := range index.Services {
+			files = append(files, PlannedFile{
+				Path:     path.Join(serviceFilesDir, index.outputFileName(fmt.Sprintf("%s.json", service.ServiceName))),
+				Category: WritePlanCategoryService,
+			})
+		}
+	}
+
+	if index.outputOptions.AttributeSchemas && index.writesCategory(WalkCategoryResource) {
+		for _, service := range index.Services {
+			for _, resources := range []map[string]AWSResource{service.AWSSDKResources, service.AWSFrameworkResources} {
+				for terraformType := range resources {
+					fileName, err := index.entryFileName(WalkCategoryResource, service.ServiceName, terraformType)
+					if err != nil {
+						return nil, err
+					}
+					files = append(files, PlannedFile{
+						Path:     path.Join(attributeSchemasDir, index.outputFileName(fileName)),
+						Category: WritePlanCategoryAttributeSchema,
+					})
+				}
+			}
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformProviderIndex_PlanIndexFiles_MatchesWrittenFiles(t *testing.T) {
+	// Setup - an ephemeral resource also present in the legacy mapping is written once
+	index := CreateTestTerraformProviderIndex()
+	kms := CreateTestServiceRegistration("kms")
+	kms.AWSEphemeralResources["aws_kms_secrets"] = CreateTestAWSResourceInfo("ephemeral_resource", "aws_kms_secrets", "newSecretsEphemeralResource", "Secrets")
+	kms.EphemeralTerraformTypes["secretsEphemeralResource"] = "aws_kms_secrets"
+	index.Services = append(index.Services, kms)
+
+	fs := afero.NewMemMapFs()
+	stub := gostub.Stub(&outputFs, fs)
+	defer stub.Reset()
+	outputDir := "/test/output"
+
+	// Execute
+	plan, err := index.PlanIndexFiles(outputDir)
+	require.NoError(t, err)
+
+	// Verify - planning does not touch the filesystem
+	exists, err := afero.Exists(fs, outputDir)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	var progressTotal int
+	require.NoError(t, index.WriteIndexFiles(outputDir, func(info ProgressInfo) {
+		progressTotal = info.Total
+	}))
+
+	var written []string
+	require.NoError(t, afero.Walk(fs, outputDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(outputDir, path)
+		if err != nil {
+			return err
+		}
+		written = append(written, filepath.ToSlash(relPath))
+		return nil
+	}))
+	sort.Strings(written)
+
+	var planned []string
+	for _, file := range plan.Files {
+		planned = append(planned, file.Path)
+	}
+	assert.Equal(t, written, planned)
+	assert.Equal(t, map[string]int{".": 4, "resources": 2, "datasources": 1, "ephemeral": 1}, plan.Totals)
+	assert.Equal(t, len(plan.Files), progressTotal)
+}
@@ -0,0 +1,49 @@
+package pkg
+
+import (
+	"errors"
+	"syscall"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// sleep is stubbed in tests to avoid waiting between write retries
+var sleep = time.Sleep
+
+// transientWriteErrors are errors that may succeed when the write is retried, typically caused by
+// resource exhaustion during the parallel write phase
+var transientWriteErrors = []error{
+	syscall.EMFILE, // too many open files
+	syscall.ENFILE, // file table overflow
+	syscall.EAGAIN,
+	syscall.EINTR,
+	syscall.EBUSY,
+}
+
+// isTransientWriteError reports whether err is worth retrying. Anything not known to be transient,
+// such as a read-only filesystem or a permission error, is treated as permanent
+func isTransientWriteError(err error) bool {
+	for _, transient := range transientWriteErrors {
+		if errors.Is(err, transient) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFileWithRetry writes data to filePath on outputFs, retrying transient errors with exponential backoff
+func (index *TerraformProviderIndex) writeFileWithRetry(filePath string, data []byte) error {
+	maxRetries, delay := index.outputOptions.writeRetryPolicy()
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = afero.WriteFile(outputFs, filePath, data, 0644)
+		if err == nil || attempt >= maxRetries || !isTransientWriteError(err) {
+			return err
+		}
+
+		sleep(delay)
+		delay *= 2
+	}
+}
@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyFs fails the first failures file opens for writing with err, then behaves like the wrapped Fs
+type flakyFs struct {
+	afero.Fs
+	failures int32
+	err      error
+	attempts atomic.Int32
+}
+
+func (f *flakyFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&os.O_WRONLY != 0 || flag&os.O_RDWR != 0 {
+		if f.attempts.Add(1) <= f.failures {
+			return nil, &os.PathError{Op: "open", Path: name, Err: f.err}
+		}
+	}
+	return f.Fs.OpenFile(name, flag, perm)
+}
+
+func TestTerraformProviderIndex_WriteJSONFile_RetriesTransientErrors(t *testing.T) {
+	// Setup
+	fs := &flakyFs{Fs: afero.NewMemMapFs(), failures: 2, err: syscall.EMFILE}
+	stub := gostub.Stub(&outputFs, afero.Fs(fs))
+	defer stub.Reset()
+
+	var delays []time.Duration
+	sleepStub := gostub.Stub(&sleep, func(d time.Duration) { delays = append(delays, d) })
+	defer sleepStub.Reset()
+
+	index := &TerraformProviderIndex{outputOptions: OutputOptions{RetryBaseDelay: time.Second}}
+
+	// Execute
+	err := index.WriteJSONFile("/test/output/data.json", map[string]string{"key": "value"})
+
+	// Verify - two failures, then success with exponential backoff between attempts
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), fs.attempts.Load())
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, delays)
+
+	exists, err := afero.Exists(fs, "/test/output/data.json")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestTerraformProviderIndex_WriteJSONFile_GivesUpAfterMaxRetries(t *testing.T) {
+	fs := &flakyFs{Fs: afero.NewMemMapFs(), failures: 10, err: syscall.EMFILE}
+	stub := gostub.Stub(&outputFs, afero.Fs(fs))
+	defer stub.Reset()
+	sleepStub := gostub.Stub(&sleep, func(time.Duration) {})
+	defer sleepStub.Reset()
+
+	index := &TerraformProviderIndex{outputOptions: OutputOptions{MaxRetries: 2}}
+
+	err := index.WriteJSONFile("/test/output/data.json", map[string]string{"key": "value"})
+
+	assert.ErrorIs(t, err, syscall.EMFILE)
+	assert.Equal(t, int32(3), fs.attempts.Load())
+}
+
+func TestTerraformProviderIndex_WriteJSONFile_DoesNotRetryPermanentErrors(t *testing.T) {
+	fs := &flakyFs{Fs: afero.NewMemMapFs(), failures: 1, err: syscall.EROFS}
+	stub := gostub.Stub(&outputFs, afero.Fs(fs))
+	defer stub.Reset()
+	sleepStub := gostub.Stub(&sleep, func(time.Duration) {
+		t.Fatal("permanent errors must not be retried")
+	})
+	defer sleepStub.Reset()
+
+	index := &TerraformProviderIndex{}
+
+	err := index.WriteJSONFile("/test/output/data.json", map[string]string{"key": "value"})
+
+	assert.ErrorIs(t, err, syscall.EROFS)
+	assert.Equal(t, int32(1), fs.attempts.Load())
+}